@@ -0,0 +1,49 @@
+package workos
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/omi-lab/workos-go/v4/pkg/common"
+)
+
+// ResolveDateRange merges the bounds of a date range that can be given either
+// as ISO-8601 strings or as times, and returns them as ISO-8601 strings.
+//
+// It returns an error wrapping common.ErrInvalidDateRange when a bound is
+// given both ways, when a bound given as a string is not an RFC 3339 date, or
+// when the start is not before the end.
+func ResolveDateRange(start string, startTime time.Time, end string, endTime time.Time) (string, string, error) {
+	start, startTime, err := resolveDateRangeBound("RangeStart", start, startTime)
+	if err != nil {
+		return "", "", err
+	}
+	end, endTime, err = resolveDateRangeBound("RangeEnd", end, endTime)
+	if err != nil {
+		return "", "", err
+	}
+
+	if !startTime.IsZero() && !endTime.IsZero() && !startTime.Before(endTime) {
+		return "", "", fmt.Errorf("%w: the start %s is not before the end %s", common.ErrInvalidDateRange, start, end)
+	}
+	return start, end, nil
+}
+
+func resolveDateRangeBound(name, s string, t time.Time) (string, time.Time, error) {
+	if !t.IsZero() {
+		if s != "" {
+			return "", time.Time{}, fmt.Errorf("%w: both %s and %sTime are set", common.ErrInvalidDateRange, name, name)
+		}
+		return t.UTC().Format(time.RFC3339Nano), t, nil
+	}
+
+	if s == "" {
+		return "", time.Time{}, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("%w: %s %q is not an RFC 3339 date", common.ErrInvalidDateRange, name, s)
+	}
+	return s, t, nil
+}
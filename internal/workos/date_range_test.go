@@ -0,0 +1,69 @@
+package workos
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/omi-lab/workos-go/v4/pkg/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveDateRange(t *testing.T) {
+	start := time.Date(2024, time.January, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		scenario  string
+		start     string
+		startTime time.Time
+		end       string
+		endTime   time.Time
+		expected  [2]string
+		err       error
+	}{
+		{
+			scenario: "empty range",
+		},
+		{
+			scenario:  "times",
+			startTime: start,
+			endTime:   start.Add(time.Hour),
+			expected:  [2]string{"2024-01-01T12:00:00Z", "2024-01-01T13:00:00Z"},
+		},
+		{
+			scenario: "strings",
+			start:    "2024-01-01T12:00:00Z",
+			end:      "2024-01-01T13:00:00.5+01:00",
+			expected: [2]string{"2024-01-01T12:00:00Z", "2024-01-01T13:00:00.5+01:00"},
+		},
+		{
+			scenario:  "bound given twice",
+			start:     "2024-01-01T12:00:00Z",
+			startTime: start,
+			err:       common.ErrInvalidDateRange,
+		},
+		{
+			scenario: "malformed string",
+			start:    "yesterday",
+			err:      common.ErrInvalidDateRange,
+		},
+		{
+			scenario:  "start after end",
+			startTime: start,
+			end:       "2024-01-01T11:00:00Z",
+			err:       common.ErrInvalidDateRange,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			start, end, err := ResolveDateRange(test.start, test.startTime, test.end, test.endTime)
+			if test.err != nil {
+				require.True(t, errors.Is(err, test.err), "unexpected error: %v", err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.expected, [2]string{start, end})
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package workos
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/omi-lab/workos-go/v4/pkg/common"
+)
+
+// PageFunc fetches the page located at the given cursors, only one of which
+// is set. It returns the records of the page, as a slice, and the pagination
+// metadata of the response.
+type PageFunc func(ctx context.Context, before, after string) (interface{}, common.ListMetadata, error)
+
+// Pager walks the records of a cursor-paginated list endpoint. It backs the
+// typed iterators exposed by the product packages, which only convert the
+// records it returns.
+//
+// Pages are followed through the After cursor, or through the Before cursor
+// when the iteration starts with only a Before cursor.
+type Pager struct {
+	ctx      context.Context
+	fetch    PageFunc
+	backward bool
+	cursor   string
+	page     reflect.Value
+	index    int
+	last     bool
+	err      error
+}
+
+// NewPager returns a Pager that starts fetching at the given cursors.
+func NewPager(ctx context.Context, before, after string, fetch PageFunc) *Pager {
+	p := &Pager{
+		ctx:    ctx,
+		fetch:  fetch,
+		cursor: after,
+		index:  -1,
+	}
+	if before != "" && after == "" {
+		p.backward = true
+		p.cursor = before
+	}
+	return p
+}
+
+// Next advances to the next record, fetching the following page when the
+// current one is exhausted. It returns false when there are no more records,
+// when a request fails or when the context is done.
+func (p *Pager) Next() bool {
+	if p.err != nil {
+		return false
+	}
+	if err := p.ctx.Err(); err != nil {
+		p.err = err
+		return false
+	}
+
+	p.index++
+	for p.index >= p.size() {
+		if p.last {
+			return false
+		}
+
+		var page interface{}
+		var metadata common.ListMetadata
+		var err error
+		if p.backward {
+			page, metadata, err = p.fetch(p.ctx, p.cursor, "")
+		} else {
+			page, metadata, err = p.fetch(p.ctx, "", p.cursor)
+		}
+		if err != nil {
+			p.err = err
+			return false
+		}
+
+		next := metadata.After
+		if p.backward {
+			next = metadata.Before
+		}
+
+		p.page = reflect.ValueOf(page)
+		p.index = 0
+
+		// A cursor that does not move would loop forever.
+		p.last = p.size() == 0 || next == "" || next == p.cursor
+		p.cursor = next
+	}
+	return true
+}
+
+func (p *Pager) size() int {
+	if !p.page.IsValid() {
+		return 0
+	}
+	return p.page.Len()
+}
+
+// Value returns the current record.
+func (p *Pager) Value() interface{} {
+	return p.page.Index(p.index).Interface()
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (p *Pager) Err() error {
+	return p.err
+}
+
+// CollectAll appends the remaining records to the slice pointed to by dst,
+// up to max when max is positive.
+func (p *Pager) CollectAll(max int, dst interface{}) error {
+	all := reflect.ValueOf(dst).Elem()
+	for n := 0; (max <= 0 || n < max) && p.Next(); n++ {
+		all.Set(reflect.Append(all, p.page.Index(p.index)))
+	}
+	return p.Err()
+}
@@ -0,0 +1,125 @@
+package workos
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/omi-lab/workos-go/v4/pkg/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPager(t *testing.T) {
+	pages := map[string]struct {
+		records  []string
+		metadata common.ListMetadata
+	}{
+		"":       {[]string{"a", "b"}, common.ListMetadata{After: "page_2"}},
+		"page_2": {[]string{"c", "d"}, common.ListMetadata{Before: "page_1", After: "page_3"}},
+		"page_3": {[]string{"e"}, common.ListMetadata{Before: "page_2"}},
+	}
+	fetch := func(ctx context.Context, before, after string) (interface{}, common.ListMetadata, error) {
+		page := pages[after]
+		return page.records, page.metadata, nil
+	}
+
+	t.Run("follows the After cursor until the last page", func(t *testing.T) {
+		var cursors []string
+		pager := NewPager(context.Background(), "", "", func(ctx context.Context, before, after string) (interface{}, common.ListMetadata, error) {
+			require.Empty(t, before)
+			cursors = append(cursors, after)
+			return fetch(ctx, before, after)
+		})
+
+		var records []string
+		for pager.Next() {
+			records = append(records, pager.Value().(string))
+		}
+
+		require.NoError(t, pager.Err())
+		require.Equal(t, []string{"", "page_2", "page_3"}, cursors)
+		require.Equal(t, []string{"a", "b", "c", "d", "e"}, records)
+		require.False(t, pager.Next())
+	})
+
+	t.Run("collects the remaining records", func(t *testing.T) {
+		pager := NewPager(context.Background(), "", "", fetch)
+		require.True(t, pager.Next())
+
+		var all []string
+		require.NoError(t, pager.CollectAll(0, &all))
+		require.Equal(t, []string{"b", "c", "d", "e"}, all)
+	})
+
+	t.Run("collects up to the max number of records", func(t *testing.T) {
+		pager := NewPager(context.Background(), "", "", fetch)
+
+		var all []string
+		require.NoError(t, pager.CollectAll(3, &all))
+		require.Equal(t, []string{"a", "b", "c"}, all)
+
+		all = nil
+		require.NoError(t, pager.CollectAll(3, &all))
+		require.Equal(t, []string{"d", "e"}, all)
+	})
+
+	t.Run("stops on empty pages", func(t *testing.T) {
+		pager := NewPager(context.Background(), "", "", func(ctx context.Context, before, after string) (interface{}, common.ListMetadata, error) {
+			return []string{}, common.ListMetadata{After: "next"}, nil
+		})
+
+		require.False(t, pager.Next())
+		require.NoError(t, pager.Err())
+	})
+
+	t.Run("follows the Before cursor when starting before a record", func(t *testing.T) {
+		var cursors []string
+		pager := NewPager(context.Background(), "page_3", "", func(ctx context.Context, before, after string) (interface{}, common.ListMetadata, error) {
+			require.Empty(t, after)
+			cursors = append(cursors, before)
+			return []string{before}, common.ListMetadata{Before: map[string]string{"page_3": "page_2"}[before]}, nil
+		})
+
+		count := 0
+		for pager.Next() {
+			count++
+		}
+
+		require.NoError(t, pager.Err())
+		require.Equal(t, []string{"page_3", "page_2"}, cursors)
+		require.Equal(t, 2, count)
+	})
+
+	t.Run("stops on errors", func(t *testing.T) {
+		pager := NewPager(context.Background(), "", "", func(ctx context.Context, before, after string) (interface{}, common.ListMetadata, error) {
+			return nil, common.ListMetadata{}, errors.New("boom")
+		})
+
+		require.False(t, pager.Next())
+		require.EqualError(t, pager.Err(), "boom")
+	})
+
+	t.Run("stops when the cursor does not move", func(t *testing.T) {
+		calls := 0
+		pager := NewPager(context.Background(), "", "page_2", func(ctx context.Context, before, after string) (interface{}, common.ListMetadata, error) {
+			calls++
+			return []string{"a"}, common.ListMetadata{After: "page_2"}, nil
+		})
+
+		for pager.Next() {
+		}
+		require.Equal(t, 1, calls)
+	})
+
+	t.Run("stops when the context is canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		pager := NewPager(ctx, "", "", func(ctx context.Context, before, after string) (interface{}, common.ListMetadata, error) {
+			return []string{"a", "b"}, common.ListMetadata{After: "next"}, nil
+		})
+
+		require.True(t, pager.Next())
+		cancel()
+		require.False(t, pager.Next())
+		require.Equal(t, context.Canceled, pager.Err())
+	})
+}
@@ -0,0 +1,36 @@
+package workos
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+)
+
+// IsRetryableStatus reports whether a request that got a response with the
+// given status can be sent again: request timeouts, rate limited requests
+// and server errors other than 501.
+//
+// It defines, with IsRetryableError, the failures Transport retries and
+// workos_errors.IsRetryable reports.
+func IsRetryableStatus(code int) bool {
+	switch {
+	case code == http.StatusRequestTimeout, code == http.StatusTooManyRequests:
+		return true
+	case code == http.StatusNotImplemented:
+		return false
+	}
+	return code >= 500 && code < 600
+}
+
+// IsRetryableError reports whether a request that failed without a response
+// can be sent again: network errors, timeouts included, unless the request
+// was canceled.
+func IsRetryableError(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
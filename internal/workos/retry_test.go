@@ -0,0 +1,52 @@
+package workos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestShouldRetry(t *testing.T) {
+	refused := &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+
+	tests := []struct {
+		name   string
+		status int
+		err    error
+		want   bool
+	}{
+		{name: "ok", status: http.StatusOK},
+		{name: "bad request", status: http.StatusBadRequest},
+		{name: "request timeout", status: http.StatusRequestTimeout, want: true},
+		{name: "rate limited", status: http.StatusTooManyRequests, want: true},
+		{name: "server error", status: http.StatusInternalServerError, want: true},
+		{name: "not implemented", status: http.StatusNotImplemented},
+		{name: "gateway timeout", status: http.StatusGatewayTimeout, want: true},
+		{name: "http version not supported", status: http.StatusHTTPVersionNotSupported, want: true},
+		{name: "network authentication required", status: http.StatusNetworkAuthenticationRequired, want: true},
+		{name: "nonstandard server error", status: 599, want: true},
+		{name: "connection refused", err: refused, want: true},
+		{name: "url error", err: &url.Error{Op: "Post", URL: "https://api.workos.com", Err: refused}, want: true},
+		{name: "wrapped network error", err: fmt.Errorf("send: %w", refused), want: true},
+		{name: "canceled", err: &url.Error{Op: "Post", URL: "https://api.workos.com", Err: context.Canceled}},
+		{name: "other error", err: errors.New("invalid request")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var res *http.Response
+			if tt.err == nil {
+				res = &http.Response{StatusCode: tt.status}
+				require.Equal(t, tt.want, IsRetryableStatus(tt.status))
+			} else {
+				require.Equal(t, tt.want, IsRetryableError(tt.err))
+			}
+			require.Equal(t, tt.want, shouldRetry(res, tt.err))
+		})
+	}
+}
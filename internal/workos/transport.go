@@ -0,0 +1,205 @@
+package workos
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/omi-lab/workos-go/v4/pkg/common"
+)
+
+// Transport sends requests to the WorkOS API. Every product client sends its
+// requests through it so that transient failures are handled the same way
+// across the SDK.
+//
+// Not every mutating endpoint of the WorkOS API honors idempotency keys, so
+// keys are only generated for the POST endpoints listed in
+// idempotentEndpoints. The other POST requests are sent without a key, and
+// are therefore not retried, unless the caller passes one with
+// common.WithIdempotencyKey.
+type Transport struct {
+	// The http.Client used to send requests. Defaults to http.DefaultClient.
+	HTTPClient *http.Client
+
+	// The WorkOS API key sent in the Authorization header of requests that
+	// do not set one.
+	APIKey string
+
+	// The policy used to retry failed requests. Defaults to
+	// common.DefaultRetryPolicy.
+	RetryPolicy *common.RetryPolicy
+
+	// The middlewares every attempt is sent through, outermost first.
+	Middlewares []common.Middleware
+}
+
+// Do sends the request and returns its response. The User-Agent,
+// Authorization and Content-Type headers are set when the request does not
+// already carry them, and POST requests to the endpoints that honor
+// idempotency keys get a generated Idempotency-Key header when they have
+// none. The given options are applied on top.
+//
+// Idempotent requests that fail with a network error, a 408, a 429 or a 5xx
+// status other than 501 are retried with exponential backoff. When the
+// response carries a Retry-After header, the delay it requests is used
+// instead.
+func (t Transport) Do(req *http.Request, opts ...common.RequestOption) (*http.Response, error) {
+	settings := common.NewRequestSettings(opts...)
+
+	cancel := func() {}
+	if settings.Timeout > 0 {
+		var ctx context.Context
+		ctx, cancel = context.WithTimeout(req.Context(), settings.Timeout)
+		req = req.WithContext(ctx)
+	}
+
+	res, err := t.do(req, settings)
+	if err != nil || res == nil {
+		cancel()
+		return res, err
+	}
+
+	// The timeout must keep running until the caller is done reading the
+	// body.
+	res.Body = &cancelOnClose{ReadCloser: res.Body, cancel: cancel}
+	return res, nil
+}
+
+func (t Transport) do(req *http.Request, settings common.RequestSettings) (*http.Response, error) {
+	httpClient := t.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	policy := common.DefaultRetryPolicy
+	if t.RetryPolicy != nil {
+		policy = *t.RetryPolicy
+	}
+
+	if req.Header.Get("User-Agent") == "" {
+		req.Header.Set("User-Agent", "workos-go/"+Version)
+	}
+	apiKey := t.APIKey
+	if settings.APIKey != "" {
+		apiKey = settings.APIKey
+	}
+	if req.Header.Get("Authorization") == "" && apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+apiKey)
+	}
+	if req.Header.Get("Content-Type") == "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for k, v := range settings.Header {
+		req.Header[k] = append([]string(nil), v...)
+	}
+	if settings.IdempotencyKey != "" {
+		req.Header.Set("Idempotency-Key", settings.IdempotencyKey)
+	}
+	if req.Header.Get("Idempotency-Key") == "" && supportsIdempotencyKey(req) {
+		if key, err := NewIdempotencyKey(); err == nil {
+			req.Header.Set("Idempotency-Key", key)
+		}
+	}
+
+	send := common.Chain(t.Middlewares...)(httpClient.Do)
+	ctx := req.Context()
+	retryable := isIdempotent(req) && isRewindable(req)
+
+	for retry := 0; ; retry++ {
+		// Each attempt gets its own copy of the request so that middlewares
+		// cannot leak changes into the following attempts.
+		attempt := req.Clone(ctx)
+		if retry > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			attempt.Body = body
+		}
+
+		res, err := send(attempt)
+		if !retryable || retry >= policy.MaxRetries || ctx.Err() != nil || !shouldRetry(res, err) {
+			return res, err
+		}
+
+		delay := policy.Backoff(retry)
+		if res != nil {
+			if d, ok := common.ParseRetryAfter(res.Header.Get("Retry-After"), time.Now()); ok {
+				if policy.MaxBackoff > 0 && d > policy.MaxBackoff {
+					return res, err
+				}
+				delay = d
+			}
+
+			io.Copy(ioutil.Discard, res.Body)
+			res.Body.Close()
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+// NewIdempotencyKey returns a random version 4 UUID.
+func NewIdempotencyKey() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+}
+
+// idempotentEndpoints are the paths of the POST endpoints of the WorkOS API
+// that honor the Idempotency-Key header. The other POST requests, such as
+// authentications or the ones sending emails, are only retried when the
+// caller sets a key.
+var idempotentEndpoints = map[string]bool{
+	"/audit_logs/events": true,
+	"/organizations":     true,
+}
+
+func supportsIdempotencyKey(req *http.Request) bool {
+	return req.Method == http.MethodPost && idempotentEndpoints[strings.TrimSuffix(req.URL.Path, "/")]
+}
+
+func isIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return req.Header.Get("Idempotency-Key") != ""
+}
+
+func isRewindable(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+func shouldRetry(res *http.Response, err error) bool {
+	if err != nil {
+		return IsRetryableError(err)
+	}
+	return IsRetryableStatus(res.StatusCode)
+}
@@ -0,0 +1,355 @@
+package workos
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/omi-lab/workos-go/v4/pkg/common"
+	"github.com/stretchr/testify/require"
+)
+
+var testRetryPolicy = common.RetryPolicy{
+	MaxRetries: 2,
+	MinBackoff: time.Millisecond,
+	MaxBackoff: 10 * time.Millisecond,
+}
+
+func TestTransportDo(t *testing.T) {
+	t.Run("retries idempotent requests until they succeed", func(t *testing.T) {
+		var calls int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if atomic.AddInt32(&calls, 1) < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+		require.NoError(t, err)
+
+		res, err := Transport{HTTPClient: server.Client(), RetryPolicy: &testRetryPolicy}.Do(req)
+		require.NoError(t, err)
+		defer res.Body.Close()
+
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		require.Equal(t, int32(3), atomic.LoadInt32(&calls))
+	})
+
+	t.Run("returns the last response when retries are exhausted", func(t *testing.T) {
+		var calls int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			w.WriteHeader(http.StatusTooManyRequests)
+		}))
+		defer server.Close()
+
+		req, err := http.NewRequest(http.MethodDelete, server.URL, nil)
+		require.NoError(t, err)
+
+		res, err := Transport{HTTPClient: server.Client(), RetryPolicy: &testRetryPolicy}.Do(req)
+		require.NoError(t, err)
+		defer res.Body.Close()
+
+		require.Equal(t, http.StatusTooManyRequests, res.StatusCode)
+		require.Equal(t, int32(3), atomic.LoadInt32(&calls))
+	})
+
+	t.Run("does not retry requests whose body cannot be sent again", func(t *testing.T) {
+		var calls int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer server.Close()
+
+		req, err := http.NewRequest(http.MethodPut, server.URL, ioutil.NopCloser(strings.NewReader(`{}`)))
+		require.NoError(t, err)
+
+		res, err := Transport{HTTPClient: server.Client(), RetryPolicy: &testRetryPolicy}.Do(req)
+		require.NoError(t, err)
+		defer res.Body.Close()
+
+		require.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	})
+
+	t.Run("resends the body of POST requests with an idempotency key", func(t *testing.T) {
+		var calls int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := ioutil.ReadAll(r.Body)
+			if string(body) != `{"name":"Foo"}` {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			if atomic.AddInt32(&calls, 1) == 1 {
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			w.WriteHeader(http.StatusCreated)
+		}))
+		defer server.Close()
+
+		req, err := http.NewRequest(http.MethodPost, server.URL, strings.NewReader(`{"name":"Foo"}`))
+		require.NoError(t, err)
+		req.Header.Set("Idempotency-Key", "key")
+
+		res, err := Transport{HTTPClient: server.Client(), RetryPolicy: &testRetryPolicy}.Do(req)
+		require.NoError(t, err)
+		defer res.Body.Close()
+
+		require.Equal(t, http.StatusCreated, res.StatusCode)
+		require.Equal(t, int32(2), atomic.LoadInt32(&calls))
+	})
+
+	t.Run("does not retry POST requests without an idempotency key", func(t *testing.T) {
+		var calls int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		req, err := http.NewRequest(http.MethodPost, server.URL+"/user_management/authenticate", strings.NewReader(`{}`))
+		require.NoError(t, err)
+
+		res, err := Transport{HTTPClient: server.Client(), RetryPolicy: &testRetryPolicy}.Do(req)
+		require.NoError(t, err)
+		defer res.Body.Close()
+
+		require.Equal(t, http.StatusServiceUnavailable, res.StatusCode)
+		require.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	})
+
+	t.Run("retries POST requests to idempotent endpoints", func(t *testing.T) {
+		var calls int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if atomic.AddInt32(&calls, 1) == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.WriteHeader(http.StatusCreated)
+		}))
+		defer server.Close()
+
+		req, err := http.NewRequest(http.MethodPost, server.URL+"/organizations", strings.NewReader(`{}`))
+		require.NoError(t, err)
+
+		res, err := Transport{HTTPClient: server.Client(), RetryPolicy: &testRetryPolicy}.Do(req)
+		require.NoError(t, err)
+		defer res.Body.Close()
+
+		require.Equal(t, http.StatusCreated, res.StatusCode)
+		require.Equal(t, int32(2), atomic.LoadInt32(&calls))
+	})
+
+	t.Run("does not retry client errors", func(t *testing.T) {
+		var calls int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer server.Close()
+
+		req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+		require.NoError(t, err)
+
+		res, err := Transport{HTTPClient: server.Client(), RetryPolicy: &testRetryPolicy}.Do(req)
+		require.NoError(t, err)
+		defer res.Body.Close()
+
+		require.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	})
+
+	t.Run("gives up when Retry-After exceeds the maximum backoff", func(t *testing.T) {
+		var calls int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			w.Header().Set("Retry-After", "60")
+			w.WriteHeader(http.StatusTooManyRequests)
+		}))
+		defer server.Close()
+
+		req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+		require.NoError(t, err)
+
+		res, err := Transport{HTTPClient: server.Client(), RetryPolicy: &testRetryPolicy}.Do(req)
+		require.NoError(t, err)
+		defer res.Body.Close()
+
+		require.Equal(t, http.StatusTooManyRequests, res.StatusCode)
+		require.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	})
+
+	t.Run("stops waiting when the context is canceled", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+		require.NoError(t, err)
+		req = req.WithContext(ctx)
+
+		policy := common.RetryPolicy{MaxRetries: 1, MinBackoff: time.Minute}
+		_, err = Transport{HTTPClient: server.Client(), RetryPolicy: &policy}.Do(req)
+		require.Equal(t, context.DeadlineExceeded, err)
+	})
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := common.RetryPolicy{MinBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+
+	for retry := 0; retry < 10; retry++ {
+		d := policy.Backoff(retry)
+		require.True(t, d >= 50*time.Millisecond, "retry %d: %s", retry, d)
+		require.True(t, d <= time.Second, "retry %d: %s", retry, d)
+	}
+	require.True(t, policy.Backoff(2) >= 200*time.Millisecond)
+	require.Equal(t, time.Duration(0), common.NoRetry.Backoff(0))
+}
+
+func TestTransportHeadersAndMiddlewares(t *testing.T) {
+	var headers []http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = append(headers, r.Header.Clone())
+		if len(headers) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	var attempts int
+	countAttempts := func(next common.RoundTripFunc) common.RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			attempts++
+			req.Header.Add("X-Attempt", "1")
+			return next(req)
+		}
+	}
+
+	req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+
+	res, err := Transport{
+		HTTPClient:  server.Client(),
+		APIKey:      "test",
+		RetryPolicy: &testRetryPolicy,
+		Middlewares: []common.Middleware{
+			common.SetHeaders(http.Header{"X-Tenant": {"foo-corp"}}),
+			countAttempts,
+		},
+	}.Do(req)
+	require.NoError(t, err)
+	defer res.Body.Close()
+
+	require.Equal(t, 2, attempts)
+	require.Len(t, headers, 2)
+	for _, h := range headers {
+		require.Equal(t, "Bearer test", h.Get("Authorization"))
+		require.Equal(t, "application/json", h.Get("Content-Type"))
+		require.Equal(t, "workos-go/"+Version, h.Get("User-Agent"))
+		require.Equal(t, "foo-corp", h.Get("X-Tenant"))
+		require.Equal(t, []string{"1"}, h["X-Attempt"])
+	}
+}
+
+func TestTransportRequestOptions(t *testing.T) {
+	var header http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Clone()
+		if r.URL.Path == "/slow" {
+			time.Sleep(50 * time.Millisecond)
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	transport := Transport{HTTPClient: server.Client(), APIKey: "test", RetryPolicy: &common.NoRetry}
+
+	t.Run("generates an idempotency key for POST requests to idempotent endpoints", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodPost, server.URL+"/audit_logs/events", strings.NewReader(`{}`))
+		require.NoError(t, err)
+
+		res, err := transport.Do(req)
+		require.NoError(t, err)
+		res.Body.Close()
+
+		require.Regexp(t, `^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`, header.Get("Idempotency-Key"))
+	})
+
+	t.Run("does not generate an idempotency key for other POST requests", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodPost, server.URL+"/user_management/authenticate", strings.NewReader(`{}`))
+		require.NoError(t, err)
+
+		res, err := transport.Do(req)
+		require.NoError(t, err)
+		res.Body.Close()
+
+		require.Empty(t, header.Get("Idempotency-Key"))
+	})
+
+	t.Run("only generates idempotency keys for the exact paths of idempotent endpoints", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodPost, server.URL+"/user_management/organizations", strings.NewReader(`{}`))
+		require.NoError(t, err)
+
+		res, err := transport.Do(req)
+		require.NoError(t, err)
+		res.Body.Close()
+
+		require.Empty(t, header.Get("Idempotency-Key"))
+	})
+
+	t.Run("does not generate an idempotency key for GET requests", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+		require.NoError(t, err)
+
+		res, err := transport.Do(req)
+		require.NoError(t, err)
+		res.Body.Close()
+
+		require.Empty(t, header.Get("Idempotency-Key"))
+	})
+
+	t.Run("applies the request options", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodPost, server.URL, strings.NewReader(`{}`))
+		require.NoError(t, err)
+
+		res, err := transport.Do(req,
+			common.WithIdempotencyKey("my-key"),
+			common.WithAPIKey("other"),
+			common.WithHeader("X-Tenant", "foo-corp"),
+			common.WithTimeout(time.Second),
+		)
+		require.NoError(t, err)
+
+		body, err := ioutil.ReadAll(res.Body)
+		require.NoError(t, err)
+		require.NoError(t, res.Body.Close())
+
+		require.Equal(t, `{}`, string(body))
+		require.Equal(t, "my-key", header.Get("Idempotency-Key"))
+		require.Equal(t, "Bearer other", header.Get("Authorization"))
+		require.Equal(t, "foo-corp", header.Get("X-Tenant"))
+	})
+
+	t.Run("fails when the timeout expires", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet, server.URL+"/slow", nil)
+		require.NoError(t, err)
+
+		_, err = transport.Do(req, common.WithTimeout(10*time.Millisecond))
+		require.Error(t, err)
+	})
+}
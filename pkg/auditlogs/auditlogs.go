@@ -36,14 +36,14 @@ package auditlogs
 import (
 	"context"
 
+	"github.com/omi-lab/workos-go/v4/pkg/common"
 	"github.com/omi-lab/workos-go/v4/pkg/models"
 )
 
 var (
 	// DefaultClient is the client used by SetAPIKey and Publish functions.
 	DefaultClient = &Client{
-		EventsEndpoint:  "https://api.workos.com/audit_logs/events",
-		ExportsEndpoint: "https://api.workos.com/audit_logs/exports",
+		Endpoint: "https://api.workos.com",
 	}
 )
 
@@ -53,16 +53,16 @@ func SetAPIKey(k string) {
 }
 
 // CreateEvent creates the given event.
-func CreateEvent(ctx context.Context, e CreateEventOpts) error {
-	return DefaultClient.CreateEvent(ctx, e)
+func CreateEvent(ctx context.Context, e CreateEventOpts, reqOpts ...common.RequestOption) error {
+	return DefaultClient.CreateEvent(ctx, e, reqOpts...)
 }
 
 // CreateEvent creates the given event.
-func CreateExport(ctx context.Context, e CreateExportOpts) (models.AuditLogExport, error) {
-	return DefaultClient.CreateExport(ctx, e)
+func CreateExport(ctx context.Context, e CreateExportOpts, reqOpts ...common.RequestOption) (models.AuditLogExport, error) {
+	return DefaultClient.CreateExport(ctx, e, reqOpts...)
 }
 
 // CreateEvent creates the given event.
-func GetExport(ctx context.Context, e GetExportOpts) (models.AuditLogExport, error) {
-	return DefaultClient.GetExport(ctx, e)
+func GetExport(ctx context.Context, e GetExportOpts, reqOpts ...common.RequestOption) (models.AuditLogExport, error) {
+	return DefaultClient.GetExport(ctx, e, reqOpts...)
 }
@@ -48,7 +48,10 @@ func TestAuditLogsCreateExport(t *testing.T) {
 
 	SetAPIKey("test")
 
-	_, err := CreateExport(context.TODO(), CreateExportOpts{})
+	_, err := CreateExport(context.TODO(), CreateExportOpts{
+		RangeStart: "2024-01-01T00:00:00Z",
+		RangeEnd:   "2024-01-02T00:00:00Z",
+	})
 	require.NoError(t, err)
 }
 
@@ -4,7 +4,9 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"strings"
 	"sync"
 	"time"
 
@@ -12,6 +14,7 @@ import (
 	"github.com/omi-lab/workos-go/v4/pkg/workos_errors"
 
 	"github.com/omi-lab/workos-go/v4/internal/workos"
+	"github.com/omi-lab/workos-go/v4/pkg/common"
 )
 
 // ResponseLimit is the default number of records to limit a response to.
@@ -36,12 +39,26 @@ type Client struct {
 	// to http.Client.
 	HTTPClient *http.Client
 
+	// The policy used to retry requests that fail with a transient error.
+	// Defaults to common.DefaultRetryPolicy.
+	RetryPolicy *common.RetryPolicy
+
+	// The middlewares that requests are sent through, outermost first.
+	Middlewares []common.Middleware
+
+	// The endpoint to WorkOS API. Defaults to https://api.workos.com.
+	Endpoint string
+
 	// The endpoint used to request WorkOS AuditLog events creation endpoint.
-	// Defaults to https://api.workos.com/audit_logs/events.
+	// Defaults to Endpoint + /audit_logs/events.
+	//
+	// Deprecated: Use Endpoint instead.
 	EventsEndpoint string
 
 	// The endpoint used to request WorkOS AuditLog events creation endpoint.
-	// Defaults to https://api.workos.com/audit_logs/exports.
+	// Defaults to Endpoint + /audit_logs/exports.
+	//
+	// Deprecated: Use Endpoint instead.
 	ExportsEndpoint string
 
 	// The function used to encode in JSON. Defaults to json.Marshal.
@@ -67,12 +84,24 @@ type CreateExportOpts struct {
 	// Organization identifier
 	OrganizationID string `json:"organization_id"`
 
-	// ISO-8601 start datetime the date range filter
+	// ISO-8601 start datetime the date range filter. Prefer RangeStartTime.
+	//
+	// REQUIRED, unless RangeStartTime is set.
 	RangeStart string `json:"range_start"`
 
-	// ISO-8601 start datetime the date range filter
+	// ISO-8601 end datetime the date range filter. Prefer RangeEndTime.
+	//
+	// REQUIRED, unless RangeEndTime is set.
 	RangeEnd string `json:"range_end"`
 
+	// Start datetime the date range filter. It must not be set along with
+	// RangeStart.
+	RangeStartTime time.Time `json:"-"`
+
+	// End datetime the date range filter. It must not be set along with
+	// RangeEnd.
+	RangeEndTime time.Time `json:"-"`
+
 	// Optional list of actions to filter
 	Actions []string `json:"actions,omitempty"`
 
@@ -98,12 +127,17 @@ func (c *Client) init() {
 		c.HTTPClient = &http.Client{Timeout: 10 * time.Second}
 	}
 
+	if c.Endpoint == "" {
+		c.Endpoint = "https://api.workos.com"
+	}
+	c.Endpoint = strings.TrimSuffix(c.Endpoint, "/")
+
 	if c.EventsEndpoint == "" {
-		c.EventsEndpoint = "https://api.workos.com/audit_logs/events"
+		c.EventsEndpoint = c.Endpoint + "/audit_logs/events"
 	}
 
 	if c.ExportsEndpoint == "" {
-		c.ExportsEndpoint = "https://api.workos.com/audit_logs/exports"
+		c.ExportsEndpoint = c.Endpoint + "/audit_logs/exports"
 	}
 
 	if c.JSONEncode == nil {
@@ -111,8 +145,17 @@ func (c *Client) init() {
 	}
 }
 
+func (c *Client) do(req *http.Request, reqOpts ...common.RequestOption) (*http.Response, error) {
+	return workos.Transport{
+		HTTPClient:  c.HTTPClient,
+		APIKey:      c.APIKey,
+		RetryPolicy: c.RetryPolicy,
+		Middlewares: c.Middlewares,
+	}.Do(req, reqOpts...)
+}
+
 // CreateEvent creates an Audit Log event.
-func (c *Client) CreateEvent(ctx context.Context, e CreateEventOpts) error {
+func (c *Client) CreateEvent(ctx context.Context, e CreateEventOpts, reqOpts ...common.RequestOption) error {
 	c.once.Do(c.init)
 
 	e.Event.OccurredAt = defaultTime(e.Event.OccurredAt)
@@ -127,15 +170,12 @@ func (c *Client) CreateEvent(ctx context.Context, e CreateEventOpts) error {
 		return err
 	}
 	req = req.WithContext(ctx)
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+c.APIKey)
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
 
 	if e.IdempotencyKey != "" {
 		req.Header.Set("Idempotency-Key", e.IdempotencyKey)
 	}
 
-	res, err := c.HTTPClient.Do(req)
+	res, err := c.do(req, reqOpts...)
 	if err != nil {
 		return err
 	}
@@ -145,9 +185,21 @@ func (c *Client) CreateEvent(ctx context.Context, e CreateEventOpts) error {
 }
 
 // CreateExport creates an export of Audit Log events. You can specify some filters.
-func (c *Client) CreateExport(ctx context.Context, e CreateExportOpts) (models.AuditLogExport, error) {
+//
+// The date range is required. A missing or invalid date range returns an
+// error wrapping common.ErrInvalidDateRange before any request is sent.
+func (c *Client) CreateExport(ctx context.Context, e CreateExportOpts, reqOpts ...common.RequestOption) (models.AuditLogExport, error) {
 	c.once.Do(c.init)
 
+	var err error
+	e.RangeStart, e.RangeEnd, err = workos.ResolveDateRange(e.RangeStart, e.RangeStartTime, e.RangeEnd, e.RangeEndTime)
+	if err != nil {
+		return models.AuditLogExport{}, err
+	}
+	if e.RangeStart == "" || e.RangeEnd == "" {
+		return models.AuditLogExport{}, fmt.Errorf("%w: the start and the end are required", common.ErrInvalidDateRange)
+	}
+
 	data, err := c.JSONEncode(e)
 	if err != nil {
 		return models.AuditLogExport{}, err
@@ -158,11 +210,8 @@ func (c *Client) CreateExport(ctx context.Context, e CreateExportOpts) (models.A
 		return models.AuditLogExport{}, err
 	}
 	req = req.WithContext(ctx)
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+c.APIKey)
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
 
-	res, err := c.HTTPClient.Do(req)
+	res, err := c.do(req, reqOpts...)
 	if err != nil {
 		return models.AuditLogExport{}, err
 	}
@@ -179,7 +228,7 @@ func (c *Client) CreateExport(ctx context.Context, e CreateExportOpts) (models.A
 }
 
 // GetExport retrieves an export of Audit Log events
-func (c *Client) GetExport(ctx context.Context, e GetExportOpts) (models.AuditLogExport, error) {
+func (c *Client) GetExport(ctx context.Context, e GetExportOpts, reqOpts ...common.RequestOption) (models.AuditLogExport, error) {
 	c.once.Do(c.init)
 
 	req, err := http.NewRequest(http.MethodGet, c.ExportsEndpoint+"/"+e.ExportID, nil)
@@ -187,11 +236,8 @@ func (c *Client) GetExport(ctx context.Context, e GetExportOpts) (models.AuditLo
 		return models.AuditLogExport{}, err
 	}
 	req = req.WithContext(ctx)
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+c.APIKey)
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
 
-	res, err := c.HTTPClient.Do(req)
+	res, err := c.do(req, reqOpts...)
 	if err != nil {
 		return models.AuditLogExport{}, err
 	}
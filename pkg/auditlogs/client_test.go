@@ -3,11 +3,13 @@ package auditlogs
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 	"time"
 
+	"github.com/omi-lab/workos-go/v4/pkg/common"
 	"github.com/omi-lab/workos-go/v4/pkg/models"
 	"github.com/omi-lab/workos-go/v4/pkg/workos_errors"
 
@@ -176,7 +178,10 @@ func TestCreateExports(t *testing.T) {
 		}
 		SetAPIKey("test")
 
-		body, err := CreateExport(context.TODO(), CreateExportOpts{})
+		body, err := CreateExport(context.TODO(), CreateExportOpts{
+			RangeStart: "2024-01-01T00:00:00Z",
+			RangeEnd:   "2024-01-02T00:00:00Z",
+		})
 		require.Equal(t, body, models.AuditLogExport{
 			ID: "test",
 		})
@@ -211,6 +216,8 @@ func TestCreateExports(t *testing.T) {
 		SetAPIKey("test")
 
 		body, err := CreateExport(context.TODO(), CreateExportOpts{
+			RangeStart: "2024-01-01T00:00:00Z",
+			RangeEnd:   "2024-01-02T00:00:00Z",
 			Actions:    []string{"create-user"},
 			Targets:    []string{"user"},
 			Actors:     []string{"Jon", "Smith"},
@@ -235,9 +242,68 @@ func TestCreateExports(t *testing.T) {
 		}
 		SetAPIKey("test")
 
-		_, err := CreateExport(context.TODO(), CreateExportOpts{})
+		_, err := CreateExport(context.TODO(), CreateExportOpts{
+			RangeStart: "2024-01-01T00:00:00Z",
+			RangeEnd:   "2024-01-02T00:00:00Z",
+		})
 		require.Error(t, err)
 	})
+	t.Run("Call succeeds with a date range given as times", func(t *testing.T) {
+		handlerFunc := func(w http.ResponseWriter, r *http.Request) {
+			var opts CreateExportOpts
+			dec := json.NewDecoder(r.Body)
+			dec.Decode(&opts)
+
+			require.Equal(t, "2024-01-01T12:00:00Z", opts.RangeStart)
+			require.Equal(t, "2024-01-02T12:00:00.5Z", opts.RangeEnd)
+
+			body, _ := json.Marshal(models.AuditLogExport{
+				ID: "test123",
+			})
+			w.Write(body)
+		}
+		server := httptest.NewServer(http.HandlerFunc(handlerFunc))
+		defer server.Close()
+
+		DefaultClient = &Client{
+			HTTPClient:      server.Client(),
+			ExportsEndpoint: server.URL,
+		}
+		SetAPIKey("test")
+
+		start := time.Date(2024, time.January, 1, 12, 0, 0, 0, time.UTC)
+		_, err := CreateExport(context.TODO(), CreateExportOpts{
+			RangeStartTime: start,
+			RangeEndTime:   start.Add(24*time.Hour + 500*time.Millisecond),
+		})
+		require.NoError(t, err)
+	})
+	t.Run("Invalid date ranges return an error before sending the request", func(t *testing.T) {
+		handlerFunc := func(w http.ResponseWriter, r *http.Request) {
+			t.Error("unexpected request")
+		}
+		server := httptest.NewServer(http.HandlerFunc(handlerFunc))
+		defer server.Close()
+
+		DefaultClient = &Client{
+			HTTPClient:      server.Client(),
+			ExportsEndpoint: server.URL,
+		}
+		SetAPIKey("test")
+
+		start := time.Date(2024, time.January, 1, 12, 0, 0, 0, time.UTC)
+		for _, opts := range []CreateExportOpts{
+			{RangeStartTime: start, RangeEndTime: start},
+			{RangeStartTime: start, RangeEnd: start.Add(-time.Hour).Format(time.RFC3339)},
+			{RangeStart: start.Format(time.RFC3339), RangeStartTime: start},
+			{RangeStart: start.String(), RangeEndTime: start.Add(time.Hour)},
+			{RangeStartTime: start},
+			{},
+		} {
+			_, err := CreateExport(context.TODO(), opts)
+			require.True(t, errors.Is(err, common.ErrInvalidDateRange), "unexpected error: %v", err)
+		}
+	})
 }
 
 func TestGetExports(t *testing.T) {
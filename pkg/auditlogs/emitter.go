@@ -0,0 +1,303 @@
+package auditlogs
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/omi-lab/workos-go/v4/internal/workos"
+	"github.com/omi-lab/workos-go/v4/pkg/common"
+	"github.com/omi-lab/workos-go/v4/pkg/workos_errors"
+)
+
+const (
+	// DefaultEmitterWorkers is the default number of events an Emitter sends
+	// concurrently.
+	DefaultEmitterWorkers = 4
+
+	// DefaultEmitterQueueSize is the default number of events an Emitter
+	// holds before they are sent.
+	DefaultEmitterQueueSize = 1000
+)
+
+// DefaultEmitterRetryPolicy is the default policy an Emitter retries the
+// events that failed with a transient error with.
+var DefaultEmitterRetryPolicy = common.RetryPolicy{
+	MaxRetries: 5,
+	MinBackoff: time.Second,
+	MaxBackoff: time.Minute,
+}
+
+var (
+	// ErrEmitterClosed is returned by Emitter.Emit once the Emitter is closed.
+	ErrEmitterClosed = errors.New("auditlogs: emitter is closed")
+
+	// ErrQueueFull is returned by Emitter.Emit when the queue is full and
+	// the Emitter drops the events that do not fit.
+	ErrQueueFull = errors.New("auditlogs: emitter queue is full")
+)
+
+// OverflowPolicy defines what an Emitter does with an event when its queue
+// is full.
+type OverflowPolicy int
+
+// Constants that enumerate the available overflow policies.
+const (
+	// OverflowBlock makes Emit wait until there is room in the queue, or
+	// until its context is done or the Emitter is closed.
+	OverflowBlock OverflowPolicy = iota
+
+	// OverflowDrop makes Emit drop the event and return ErrQueueFull.
+	OverflowDrop
+)
+
+// EmitterMetrics contains the counters of an Emitter.
+type EmitterMetrics struct {
+	// The number of events that were queued.
+	Queued int64
+
+	// The number of events that were created.
+	Sent int64
+
+	// The number of times an event was sent again after a transient error.
+	Retried int64
+
+	// The number of events that were dropped because the queue was full.
+	Dropped int64
+
+	// The number of events that could not be created.
+	Failed int64
+
+	// The number of queued events that are not yet created or failed.
+	Pending int64
+}
+
+// Emitter creates Audit Log events asynchronously, so that the latency of
+// the WorkOS API does not add to the one of the caller.
+//
+// Events are queued in memory and sent by a pool of workers. The events that
+// fail with a transient error are sent again with the same idempotency key,
+// so that they are not created twice.
+//
+//	emitter := &auditlogs.Emitter{Client: client}
+//	defer emitter.Close(ctx)
+//
+//	err := emitter.Emit(ctx, auditlogs.CreateEventOpts{...})
+//
+// Queued events are lost if the process exits before they are sent: call
+// Close to flush them.
+//
+// Events are not batched: the Audit Logs API creates a single event per
+// request, so grouping events would only delay them. Throughput is tuned with
+// Workers instead.
+type Emitter struct {
+	// The client used to create events. Defaults to DefaultClient.
+	Client *Client
+
+	// The number of events sent concurrently. Defaults to
+	// DefaultEmitterWorkers.
+	Workers int
+
+	// The number of events held before they are sent. Defaults to
+	// DefaultEmitterQueueSize.
+	QueueSize int
+
+	// What Emit does when the queue is full. Defaults to OverflowBlock.
+	Overflow OverflowPolicy
+
+	// The policy the events that fail with a transient error are retried
+	// with, on top of the retry policy of the Client. Defaults to
+	// DefaultEmitterRetryPolicy.
+	RetryPolicy *common.RetryPolicy
+
+	// The function the events that could not be created are reported to.
+	// Defaults to logging them with the standard logger.
+	OnError func(e CreateEventOpts, err error)
+
+	// Options applied to each request to the Audit Logs API.
+	RequestOptions []common.RequestOption
+
+	once      sync.Once
+	closeOnce sync.Once
+	queue     chan CreateEventOpts
+	workers   sync.WaitGroup
+	ctx       context.Context
+	abort     context.CancelFunc
+
+	// closing is closed when Close is called, so that the Emit calls waiting
+	// for room in the queue return.
+	closing chan struct{}
+
+	// mu guards closed, so that no event is queued once the queue is closed.
+	mu     sync.RWMutex
+	closed bool
+
+	metricsMu sync.Mutex
+	metrics   EmitterMetrics
+}
+
+func (e *Emitter) init() {
+	if e.Client == nil {
+		e.Client = DefaultClient
+	}
+
+	if e.Workers <= 0 {
+		e.Workers = DefaultEmitterWorkers
+	}
+
+	if e.QueueSize <= 0 {
+		e.QueueSize = DefaultEmitterQueueSize
+	}
+
+	if e.RetryPolicy == nil {
+		e.RetryPolicy = &DefaultEmitterRetryPolicy
+	}
+
+	e.queue = make(chan CreateEventOpts, e.QueueSize)
+	e.closing = make(chan struct{})
+	e.ctx, e.abort = context.WithCancel(context.Background())
+
+	e.workers.Add(e.Workers)
+	for i := 0; i < e.Workers; i++ {
+		go e.work()
+	}
+}
+
+// Emit queues an event to be created. An idempotency key is generated for
+// events that have none, and the occurrence time of the events that have
+// none is set to now.
+//
+// When the queue is full, Emit waits until there is room, the context is done
+// or the Emitter is closed, or returns ErrQueueFull, according to the
+// Overflow policy.
+func (e *Emitter) Emit(ctx context.Context, event CreateEventOpts) error {
+	e.once.Do(e.init)
+
+	if event.IdempotencyKey == "" {
+		key, err := workos.NewIdempotencyKey()
+		if err != nil {
+			return err
+		}
+		event.IdempotencyKey = key
+	}
+	event.Event.OccurredAt = defaultTime(event.Event.OccurredAt)
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	if e.closed {
+		return ErrEmitterClosed
+	}
+
+	if e.Overflow == OverflowDrop {
+		select {
+		case e.queue <- event:
+		default:
+			e.count(func(m *EmitterMetrics) { m.Dropped++ })
+			return ErrQueueFull
+		}
+	} else {
+		select {
+		case e.queue <- event:
+		case <-e.closing:
+			return ErrEmitterClosed
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	e.count(func(m *EmitterMetrics) { m.Queued++ })
+	return nil
+}
+
+// Close stops accepting events and waits until the queued events are sent.
+//
+// When the context is done first, the events being sent are canceled, the
+// remaining ones are reported to OnError and Close returns the error of the
+// context.
+func (e *Emitter) Close(ctx context.Context) error {
+	e.once.Do(e.init)
+
+	e.closeOnce.Do(func() {
+		// Wake up the Emit calls waiting for room, which hold mu.
+		close(e.closing)
+
+		e.mu.Lock()
+		e.closed = true
+		close(e.queue)
+		e.mu.Unlock()
+	})
+
+	done := make(chan struct{})
+	go func() {
+		e.workers.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		e.abort()
+		<-done
+		return ctx.Err()
+	}
+}
+
+// Metrics returns a snapshot of the counters of the Emitter.
+func (e *Emitter) Metrics() EmitterMetrics {
+	e.metricsMu.Lock()
+	defer e.metricsMu.Unlock()
+
+	m := e.metrics
+	m.Pending = m.Queued - m.Sent - m.Failed
+	return m
+}
+
+func (e *Emitter) work() {
+	defer e.workers.Done()
+
+	for event := range e.queue {
+		if err := e.send(event); err != nil {
+			e.count(func(m *EmitterMetrics) { m.Failed++ })
+			e.reportError(event, err)
+			continue
+		}
+		e.count(func(m *EmitterMetrics) { m.Sent++ })
+	}
+}
+
+// send creates an event, retrying transient errors.
+func (e *Emitter) send(event CreateEventOpts) error {
+	for retry := 0; ; retry++ {
+		err := e.Client.CreateEvent(e.ctx, event, e.RequestOptions...)
+		if err == nil || !workos_errors.IsRetryable(err) || retry >= e.RetryPolicy.MaxRetries {
+			return err
+		}
+
+		timer := time.NewTimer(e.RetryPolicy.Backoff(retry))
+		select {
+		case <-timer.C:
+		case <-e.ctx.Done():
+			timer.Stop()
+			return err
+		}
+		e.count(func(m *EmitterMetrics) { m.Retried++ })
+	}
+}
+
+func (e *Emitter) count(f func(m *EmitterMetrics)) {
+	e.metricsMu.Lock()
+	f(&e.metrics)
+	e.metricsMu.Unlock()
+}
+
+func (e *Emitter) reportError(event CreateEventOpts, err error) {
+	if e.OnError != nil {
+		e.OnError(event, err)
+		return
+	}
+	log.Printf("auditlogs: creating %s event failed: %v", event.Event.Action, err)
+}
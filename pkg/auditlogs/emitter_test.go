@@ -0,0 +1,213 @@
+package auditlogs
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/omi-lab/workos-go/v4/pkg/common"
+	"github.com/omi-lab/workos-go/v4/pkg/workos_errors"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestEmitter returns an Emitter that sends events to a server calling
+// the given handler.
+func newTestEmitter(handler http.HandlerFunc) (*Emitter, *httptest.Server) {
+	server := httptest.NewServer(handler)
+
+	emitter := &Emitter{
+		Client: &Client{
+			APIKey:      "test",
+			HTTPClient:  server.Client(),
+			Endpoint:    server.URL,
+			RetryPolicy: &common.NoRetry,
+		},
+		RetryPolicy: &common.RetryPolicy{MaxRetries: 3, MinBackoff: time.Millisecond},
+	}
+	return emitter, server
+}
+
+func TestEmitterSendsQueuedEvents(t *testing.T) {
+	var mu sync.Mutex
+	var keys []string
+	emitter, server := newTestEmitter(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		keys = append(keys, r.Header.Get("Idempotency-Key"))
+		mu.Unlock()
+		w.WriteHeader(http.StatusCreated)
+	})
+	defer server.Close()
+
+	e := event
+	e.IdempotencyKey = ""
+	for i := 0; i < 10; i++ {
+		require.NoError(t, emitter.Emit(context.Background(), e))
+	}
+	require.NoError(t, emitter.Close(context.Background()))
+
+	require.Len(t, keys, 10)
+	for _, key := range keys {
+		require.NotEmpty(t, key)
+	}
+	require.Equal(t, EmitterMetrics{Queued: 10, Sent: 10}, emitter.Metrics())
+}
+
+func TestEmitterRetriesTransientErrors(t *testing.T) {
+	var mu sync.Mutex
+	var keys []string
+	emitter, server := newTestEmitter(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		keys = append(keys, r.Header.Get("Idempotency-Key"))
+		if len(keys) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	})
+	defer server.Close()
+
+	require.NoError(t, emitter.Emit(context.Background(), event))
+	require.NoError(t, emitter.Close(context.Background()))
+
+	require.Equal(t, []string{event.IdempotencyKey, event.IdempotencyKey}, keys)
+	require.Equal(t, EmitterMetrics{Queued: 1, Sent: 1, Retried: 1}, emitter.Metrics())
+}
+
+func TestEmitterReportsPermanentErrors(t *testing.T) {
+	requests := 0
+	emitter, server := newTestEmitter(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusBadRequest)
+	})
+	defer server.Close()
+
+	var failed []CreateEventOpts
+	emitter.OnError = func(e CreateEventOpts, err error) {
+		require.True(t, workos_errors.IsBadRequest(err), "unexpected error: %v", err)
+		failed = append(failed, e)
+	}
+
+	require.NoError(t, emitter.Emit(context.Background(), event))
+	require.NoError(t, emitter.Close(context.Background()))
+
+	require.Equal(t, 1, requests)
+	require.Len(t, failed, 1)
+	require.Equal(t, event.IdempotencyKey, failed[0].IdempotencyKey)
+	require.Equal(t, EmitterMetrics{Queued: 1, Failed: 1}, emitter.Metrics())
+}
+
+func TestEmitterDropsEventsWhenFull(t *testing.T) {
+	received := make(chan struct{}, 2)
+	release := make(chan struct{})
+	emitter, server := newTestEmitter(func(w http.ResponseWriter, r *http.Request) {
+		received <- struct{}{}
+		<-release
+		w.WriteHeader(http.StatusCreated)
+	})
+	defer server.Close()
+	emitter.Workers = 1
+	emitter.QueueSize = 1
+	emitter.Overflow = OverflowDrop
+
+	ctx := context.Background()
+	require.NoError(t, emitter.Emit(ctx, event))
+	<-received
+	require.NoError(t, emitter.Emit(ctx, event))
+	require.Equal(t, ErrQueueFull, emitter.Emit(ctx, event))
+
+	close(release)
+	require.NoError(t, emitter.Close(ctx))
+
+	require.Equal(t, EmitterMetrics{Queued: 2, Sent: 2, Dropped: 1}, emitter.Metrics())
+}
+
+func TestEmitterBlocksWhenFull(t *testing.T) {
+	received := make(chan struct{}, 2)
+	release := make(chan struct{})
+	emitter, server := newTestEmitter(func(w http.ResponseWriter, r *http.Request) {
+		received <- struct{}{}
+		<-release
+		w.WriteHeader(http.StatusCreated)
+	})
+	defer server.Close()
+	emitter.Workers = 1
+	emitter.QueueSize = 1
+
+	require.NoError(t, emitter.Emit(context.Background(), event))
+	<-received
+	require.NoError(t, emitter.Emit(context.Background(), event))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	require.Equal(t, context.DeadlineExceeded, emitter.Emit(ctx, event))
+
+	close(release)
+	require.NoError(t, emitter.Close(context.Background()))
+	require.Equal(t, EmitterMetrics{Queued: 2, Sent: 2}, emitter.Metrics())
+}
+
+func TestEmitterCloseTimeout(t *testing.T) {
+	release := make(chan struct{})
+	emitter, server := newTestEmitter(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	})
+	defer server.Close()
+	defer close(release)
+	emitter.Workers = 1
+
+	var mu sync.Mutex
+	failed := 0
+	emitter.OnError = func(e CreateEventOpts, err error) {
+		mu.Lock()
+		failed++
+		mu.Unlock()
+	}
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, emitter.Emit(context.Background(), event))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	require.Equal(t, context.DeadlineExceeded, emitter.Close(ctx))
+
+	require.Equal(t, 3, failed)
+	require.Equal(t, EmitterMetrics{Queued: 3, Failed: 3}, emitter.Metrics())
+	require.Equal(t, ErrEmitterClosed, emitter.Emit(context.Background(), event))
+}
+
+func TestEmitterCloseWithBlockedEmit(t *testing.T) {
+	received := make(chan struct{}, 1)
+	release := make(chan struct{})
+	emitter, server := newTestEmitter(func(w http.ResponseWriter, r *http.Request) {
+		received <- struct{}{}
+		<-release
+	})
+	defer server.Close()
+	defer close(release)
+	emitter.Workers = 1
+	emitter.QueueSize = 1
+	emitter.OnError = func(e CreateEventOpts, err error) {}
+
+	require.NoError(t, emitter.Emit(context.Background(), event))
+	<-received
+	require.NoError(t, emitter.Emit(context.Background(), event))
+
+	blocked := make(chan error)
+	go func() {
+		blocked <- emitter.Emit(context.Background(), event)
+	}()
+	time.Sleep(10 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	require.Equal(t, context.DeadlineExceeded, emitter.Close(ctx))
+	require.Less(t, int64(time.Since(start)), int64(time.Second))
+	require.Equal(t, ErrEmitterClosed, <-blocked)
+}
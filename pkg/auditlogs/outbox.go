@@ -0,0 +1,558 @@
+package auditlogs
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/omi-lab/workos-go/v4/internal/workos"
+	"github.com/omi-lab/workos-go/v4/pkg/common"
+	"github.com/omi-lab/workos-go/v4/pkg/workos_errors"
+)
+
+// DefaultOutboxSegmentSize is the default size above which an Outbox starts
+// a new segment.
+const DefaultOutboxSegmentSize = 16 << 20
+
+// DefaultOutboxRetryPolicy is the default policy an Outbox retries the
+// events that failed with a transient error with.
+var DefaultOutboxRetryPolicy = common.RetryPolicy{
+	MaxRetries: 10,
+	MinBackoff: time.Second,
+	MaxBackoff: time.Minute,
+}
+
+// ErrOutboxClosed is returned by Outbox.Append once the Outbox is closed.
+var ErrOutboxClosed = errors.New("auditlogs: outbox is closed")
+
+// OutboxOpts contains the options of an Outbox.
+type OutboxOpts struct {
+	// The client used to create events. Defaults to DefaultClient.
+	Client *Client
+
+	// The number of events sent concurrently. Defaults to
+	// DefaultEmitterWorkers.
+	Workers int
+
+	// The size in bytes above which a new segment is started. Defaults to
+	// DefaultOutboxSegmentSize.
+	SegmentSize int64
+
+	// The policy the events that fail with a transient error are retried
+	// with, on top of the retry policy of the Client. Defaults to
+	// DefaultOutboxRetryPolicy.
+	RetryPolicy *common.RetryPolicy
+
+	// The function the events that could not be created are reported to.
+	// Defaults to logging them with the standard logger.
+	//
+	// Events that failed with a permanent error, such as a 400, are then
+	// acknowledged. Events that still failed with a transient error after
+	// the retries are kept in the log and queued again once the maximum
+	// backoff of the RetryPolicy elapsed, so that they are sent when the
+	// outage ends.
+	OnError func(e CreateEventOpts, err error)
+
+	// Options applied to each request to the Audit Logs API.
+	RequestOptions []common.RequestOption
+}
+
+// Outbox is a write-ahead log of Audit Log events, for the events that must
+// not be lost when the process crashes before they are created.
+//
+// Events are appended to a segment file in a directory and synced to disk
+// before Append returns. They are then sent to WorkOS in the background, with
+// their idempotency key, and acknowledged in the log once they are created.
+// Segments are deleted once all their events are acknowledged.
+//
+// When an Outbox is opened, the events that were not acknowledged are sent
+// again. They keep their idempotency key, so an event that was created but
+// not yet acknowledged is not created twice.
+//
+//	outbox, err := auditlogs.NewOutbox("/var/lib/app/audit-outbox", auditlogs.OutboxOpts{
+//		Client: client,
+//	})
+//	if err != nil {
+//		// Handle error.
+//	}
+//	defer outbox.Close(ctx)
+//
+//	err = outbox.Append(auditlogs.CreateEventOpts{...})
+//
+// A directory must be used by a single Outbox at a time.
+type Outbox struct {
+	dir  string
+	opts OutboxOpts
+
+	ctx     context.Context
+	abort   context.CancelFunc
+	workers sync.WaitGroup
+
+	// mu guards the fields below. Appends and acknowledgements are written
+	// while it is held, so that they are ordered in the log.
+	mu      sync.Mutex
+	cond    *sync.Cond
+	closed  bool
+	file    *os.File
+	size    int64
+	broken  bool
+	segment uint64
+	seq     uint64
+	queue   []outboxEntry
+
+	// unacked counts the events of each segment that are not acknowledged.
+	unacked map[uint64]int
+	// segments maps the sequence number of the events to their segment.
+	segments map[uint64]uint64
+}
+
+// outboxRecord is a line of a segment. It either holds an event or
+// acknowledges the event with the same sequence number.
+type outboxRecord struct {
+	Seq            uint64           `json:"seq"`
+	Event          *CreateEventOpts `json:"event,omitempty"`
+	IdempotencyKey string           `json:"idempotency_key,omitempty"`
+	Ack            bool             `json:"ack,omitempty"`
+}
+
+type outboxEntry struct {
+	seq   uint64
+	event CreateEventOpts
+}
+
+// NewOutbox opens the Outbox stored in a directory, creating the directory
+// when it does not exist, and starts sending the events that were not
+// acknowledged.
+func NewOutbox(dir string, opts OutboxOpts) (*Outbox, error) {
+	if opts.Client == nil {
+		opts.Client = DefaultClient
+	}
+	if opts.Workers <= 0 {
+		opts.Workers = DefaultEmitterWorkers
+	}
+	if opts.SegmentSize <= 0 {
+		opts.SegmentSize = DefaultOutboxSegmentSize
+	}
+	if opts.RetryPolicy == nil {
+		opts.RetryPolicy = &DefaultOutboxRetryPolicy
+	}
+
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+
+	o := &Outbox{
+		dir:      dir,
+		opts:     opts,
+		unacked:  make(map[uint64]int),
+		segments: make(map[uint64]uint64),
+	}
+	o.cond = sync.NewCond(&o.mu)
+
+	if err := o.recover(); err != nil {
+		return nil, err
+	}
+	if err := o.rotate(); err != nil {
+		return nil, err
+	}
+
+	o.ctx, o.abort = context.WithCancel(context.Background())
+	o.workers.Add(opts.Workers)
+	for i := 0; i < opts.Workers; i++ {
+		go o.work()
+	}
+	return o, nil
+}
+
+// recover loads the events that were not acknowledged from the segments, in
+// order. Lines truncated by a crash are skipped.
+func (o *Outbox) recover() error {
+	segments, err := o.listSegments()
+	if err != nil {
+		return err
+	}
+
+	pending := make(map[uint64]CreateEventOpts)
+	for _, segment := range segments {
+		o.segment = segment
+		o.unacked[segment] = 0
+
+		err := o.readSegment(segment, func(record outboxRecord) {
+			if record.Seq > o.seq {
+				o.seq = record.Seq
+			}
+
+			switch {
+			case record.Ack:
+				if s, ok := o.segments[record.Seq]; ok {
+					o.unacked[s]--
+					delete(o.segments, record.Seq)
+					delete(pending, record.Seq)
+				}
+			case record.Event != nil:
+				event := *record.Event
+				event.IdempotencyKey = record.IdempotencyKey
+				pending[record.Seq] = event
+				o.segments[record.Seq] = segment
+				o.unacked[segment]++
+			}
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	for seq, event := range pending {
+		o.queue = append(o.queue, outboxEntry{seq: seq, event: event})
+	}
+	sort.Slice(o.queue, func(i, j int) bool {
+		return o.queue[i].seq < o.queue[j].seq
+	})
+
+	return o.deleteSegments()
+}
+
+func (o *Outbox) readSegment(segment uint64, f func(record outboxRecord)) error {
+	file, err := os.Open(o.segmentPath(segment))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	r := bufio.NewReader(file)
+	for {
+		line, err := r.ReadBytes('\n')
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		var record outboxRecord
+		if json.Unmarshal(line, &record) == nil && record.Seq != 0 {
+			f(record)
+		}
+	}
+}
+
+// listSegments returns the segments of the directory, in order.
+func (o *Outbox) listSegments() ([]uint64, error) {
+	files, err := ioutil.ReadDir(o.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var segments []uint64
+	for _, file := range files {
+		name := file.Name()
+		if file.IsDir() || !strings.HasSuffix(name, ".log") {
+			continue
+		}
+		segment, err := strconv.ParseUint(strings.TrimSuffix(name, ".log"), 10, 64)
+		if err != nil {
+			continue
+		}
+		segments = append(segments, segment)
+	}
+
+	sort.Slice(segments, func(i, j int) bool {
+		return segments[i] < segments[j]
+	})
+	return segments, nil
+}
+
+func (o *Outbox) segmentPath(segment uint64) string {
+	return filepath.Join(o.dir, fmt.Sprintf("%020d.log", segment))
+}
+
+// rotate closes the current segment, if any, and starts a new one.
+func (o *Outbox) rotate() error {
+	file, err := os.OpenFile(o.segmentPath(o.segment+1), os.O_CREATE|os.O_EXCL|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	if err = syncDir(o.dir); err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return err
+	}
+
+	if o.file != nil {
+		// The events of the previous segment were synced when appended.
+		o.file.Close()
+	}
+
+	o.file = file
+	o.size = 0
+	o.broken = false
+	o.segment++
+	o.unacked[o.segment] = 0
+	return o.deleteSegments()
+}
+
+// deleteSegments deletes the oldest segments whose events are all
+// acknowledged. Segments are deleted in order because they can hold the
+// acknowledgements of the events of the previous ones.
+func (o *Outbox) deleteSegments() error {
+	for {
+		oldest, ok := uint64(0), false
+		for segment := range o.unacked {
+			if !ok || segment < oldest {
+				oldest, ok = segment, true
+			}
+		}
+		if !ok || oldest == o.segment || o.unacked[oldest] > 0 {
+			return nil
+		}
+
+		if err := os.Remove(o.segmentPath(oldest)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		delete(o.unacked, oldest)
+	}
+}
+
+// Append writes an event to the log, then queues it to be created. An
+// idempotency key is generated for events that have none, and the
+// occurrence time of the events that have none is set to now.
+//
+// The event is synced to disk before Append returns, so it is sent even if
+// the process crashes before it is created. Append returns an error when the
+// event could not be synced.
+func (o *Outbox) Append(event CreateEventOpts) error {
+	if event.IdempotencyKey == "" {
+		key, err := workos.NewIdempotencyKey()
+		if err != nil {
+			return err
+		}
+		event.IdempotencyKey = key
+	}
+	event.Event.OccurredAt = defaultTime(event.Event.OccurredAt)
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.closed {
+		return ErrOutboxClosed
+	}
+
+	seq := o.seq + 1
+	if err := o.write(outboxRecord{Seq: seq, Event: &event, IdempotencyKey: event.IdempotencyKey}, true); err != nil {
+		return err
+	}
+
+	// The event is durable from now on: no error must be returned, or the
+	// caller could append it again.
+	o.seq = seq
+	o.segments[seq] = o.segment
+	o.unacked[o.segment]++
+	o.queue = append(o.queue, outboxEntry{seq: seq, event: event})
+	o.cond.Signal()
+	return nil
+}
+
+// write appends a record to the current segment, starting a new one first
+// when the current one is full or a previous write failed. Acknowledgements
+// are not synced: losing one only makes its event be sent again with the
+// same idempotency key.
+func (o *Outbox) write(record outboxRecord, durable bool) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	if o.broken || o.size >= o.opts.SegmentSize {
+		if err = o.rotate(); err != nil {
+			return err
+		}
+	}
+
+	n, err := o.file.Write(append(line, '\n'))
+	o.size += int64(n)
+	if err == nil && durable {
+		err = o.file.Sync()
+	}
+	if err != nil {
+		// Start a new segment before the next write, so that the next
+		// records do not follow a partial line.
+		o.broken = true
+	}
+	return err
+}
+
+// ack acknowledges an event and deletes the segments that are no longer
+// needed.
+func (o *Outbox) ack(seq uint64) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if err := o.write(outboxRecord{Seq: seq, Ack: true}, false); err != nil {
+		return err
+	}
+
+	o.unacked[o.segments[seq]]--
+	delete(o.segments, seq)
+	return o.deleteSegments()
+}
+
+// Pending returns the number of events that are not yet acknowledged.
+func (o *Outbox) Pending() int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	return len(o.segments)
+}
+
+// Close stops accepting events and waits until the pending events are sent.
+//
+// When the context is done first, the events being sent are canceled and
+// Close returns the error of the context. The events that were not sent are
+// sent again when the Outbox is opened again.
+func (o *Outbox) Close(ctx context.Context) error {
+	o.mu.Lock()
+	o.closed = true
+	o.cond.Broadcast()
+	o.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		o.workers.Wait()
+		close(done)
+	}()
+
+	var err error
+	select {
+	case <-done:
+	case <-ctx.Done():
+		o.abort()
+		<-done
+		err = ctx.Err()
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.file == nil {
+		return err
+	}
+	if closeErr := o.file.Close(); err == nil {
+		err = closeErr
+	}
+	o.file = nil
+	return err
+}
+
+// next returns the next event to send, waiting for one. It returns false
+// once the Outbox is closed and has no event left to send, or is aborted.
+func (o *Outbox) next() (outboxEntry, bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	for len(o.queue) == 0 && !o.closed {
+		o.cond.Wait()
+	}
+	if len(o.queue) == 0 || o.ctx.Err() != nil {
+		return outboxEntry{}, false
+	}
+
+	entry := o.queue[0]
+	o.queue = o.queue[1:]
+	return entry, true
+}
+
+func (o *Outbox) work() {
+	defer o.workers.Done()
+
+	for {
+		entry, ok := o.next()
+		if !ok {
+			return
+		}
+
+		err := o.send(entry.event)
+		if err != nil && o.ctx.Err() != nil {
+			// Aborted: the event is sent again when the Outbox is reopened.
+			return
+		}
+		if err != nil {
+			o.reportError(entry.event, err)
+			if workos_errors.IsRetryable(err) {
+				o.park(entry)
+				continue
+			}
+		}
+
+		if err := o.ack(entry.seq); err != nil {
+			log.Printf("auditlogs: acknowledging %s event failed: %v", entry.event.Event.Action, err)
+		}
+	}
+}
+
+// park queues an event that still fails after the retries again once the
+// maximum backoff elapsed. The events that are parked when the Outbox is
+// closed are sent again when it is reopened.
+func (o *Outbox) park(entry outboxEntry) {
+	time.AfterFunc(o.opts.RetryPolicy.Backoff(o.opts.RetryPolicy.MaxRetries), func() {
+		o.mu.Lock()
+		defer o.mu.Unlock()
+
+		if o.closed {
+			return
+		}
+		o.queue = append(o.queue, entry)
+		o.cond.Signal()
+	})
+}
+
+// send creates an event, retrying transient errors.
+func (o *Outbox) send(event CreateEventOpts) error {
+	for retry := 0; ; retry++ {
+		err := o.opts.Client.CreateEvent(o.ctx, event, o.opts.RequestOptions...)
+		if err == nil || !workos_errors.IsRetryable(err) || retry >= o.opts.RetryPolicy.MaxRetries {
+			return err
+		}
+
+		timer := time.NewTimer(o.opts.RetryPolicy.Backoff(retry))
+		select {
+		case <-timer.C:
+		case <-o.ctx.Done():
+			timer.Stop()
+			return err
+		}
+	}
+}
+
+func (o *Outbox) reportError(event CreateEventOpts, err error) {
+	if o.opts.OnError != nil {
+		o.opts.OnError(event, err)
+		return
+	}
+	log.Printf("auditlogs: creating %s event failed: %v", event.Event.Action, err)
+}
+
+// syncDir syncs a directory, so that the files created in it survive a
+// crash.
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+
+	// Some systems, like Windows, can not sync directories.
+	d.Sync()
+	return nil
+}
@@ -0,0 +1,276 @@
+package auditlogs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/omi-lab/workos-go/v4/pkg/common"
+	"github.com/omi-lab/workos-go/v4/pkg/workos_errors"
+	"github.com/stretchr/testify/require"
+)
+
+// outboxServer records the idempotency keys of the events it creates, or
+// fails with the given status when it is not zero.
+type outboxServer struct {
+	mu     sync.Mutex
+	status int
+	keys   []string
+}
+
+func (s *outboxServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.status != 0 {
+		w.WriteHeader(s.status)
+		return
+	}
+	s.keys = append(s.keys, r.Header.Get("Idempotency-Key"))
+	w.WriteHeader(http.StatusCreated)
+}
+
+func newTestOutbox(t *testing.T, dir string, server *httptest.Server, opts OutboxOpts) *Outbox {
+	opts.Client = &Client{
+		APIKey:      "test",
+		HTTPClient:  server.Client(),
+		Endpoint:    server.URL,
+		RetryPolicy: &common.NoRetry,
+	}
+	opts.RetryPolicy = &common.RetryPolicy{MaxRetries: 2, MinBackoff: time.Millisecond, MaxBackoff: time.Millisecond}
+
+	outbox, err := NewOutbox(dir, opts)
+	require.NoError(t, err)
+	return outbox
+}
+
+func tempDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "outbox")
+	require.NoError(t, err)
+	return dir
+}
+
+func segmentFiles(t *testing.T, dir string) []string {
+	files, err := filepath.Glob(filepath.Join(dir, "*.log"))
+	require.NoError(t, err)
+	return files
+}
+
+func TestOutboxSendsEvents(t *testing.T) {
+	handler := &outboxServer{}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	outbox := newTestOutbox(t, dir, server, OutboxOpts{})
+	require.NoError(t, outbox.Append(event))
+	require.NoError(t, outbox.Close(context.Background()))
+
+	require.Equal(t, []string{event.IdempotencyKey}, handler.keys)
+	require.Equal(t, 0, outbox.Pending())
+	require.Equal(t, ErrOutboxClosed, outbox.Append(event))
+}
+
+func TestOutboxResendsUnacknowledgedEvents(t *testing.T) {
+	unavailable := httptest.NewServer(&outboxServer{status: http.StatusServiceUnavailable})
+	defer unavailable.Close()
+
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	var mu sync.Mutex
+	var failures []error
+	outbox := newTestOutbox(t, dir, unavailable, OutboxOpts{
+		Workers: 1,
+		OnError: func(e CreateEventOpts, err error) {
+			mu.Lock()
+			failures = append(failures, err)
+			mu.Unlock()
+		},
+	})
+	first := event
+	first.IdempotencyKey = ""
+	require.NoError(t, outbox.Append(first))
+	require.NoError(t, outbox.Append(event))
+
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(failures) >= 2
+	}, time.Second, time.Millisecond)
+	require.NoError(t, outbox.Close(context.Background()))
+	for _, err := range failures {
+		require.True(t, workos_errors.IsServerError(err), "unexpected error: %v", err)
+	}
+	require.Equal(t, 2, outbox.Pending())
+
+	// Simulate a crash while an event was being appended.
+	files := segmentFiles(t, dir)
+	f, err := os.OpenFile(files[len(files)-1], os.O_WRONLY|os.O_APPEND, 0600)
+	require.NoError(t, err)
+	_, err = f.WriteString(`{"seq":3,"event":{"organiz`)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	handler := &outboxServer{}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	outbox = newTestOutbox(t, dir, server, OutboxOpts{Workers: 1})
+	require.Equal(t, 2, outbox.Pending())
+	require.NoError(t, outbox.Close(context.Background()))
+
+	require.Len(t, handler.keys, 2)
+	require.NotEmpty(t, handler.keys[0])
+	require.Equal(t, event.IdempotencyKey, handler.keys[1])
+	require.Equal(t, 0, outbox.Pending())
+
+	outbox = newTestOutbox(t, dir, server, OutboxOpts{})
+	require.Equal(t, 0, outbox.Pending())
+	require.NoError(t, outbox.Close(context.Background()))
+	require.Len(t, handler.keys, 2)
+}
+
+func TestOutboxRequeuesEventsAfterTheRetries(t *testing.T) {
+	handler := &outboxServer{status: http.StatusServiceUnavailable}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	failed := make(chan error, 10)
+	outbox := newTestOutbox(t, dir, server, OutboxOpts{
+		Workers: 1,
+		OnError: func(e CreateEventOpts, err error) {
+			select {
+			case failed <- err:
+			default:
+			}
+		},
+	})
+
+	require.NoError(t, outbox.Append(event))
+	require.True(t, workos_errors.IsServerError(<-failed))
+
+	handler.mu.Lock()
+	handler.status = 0
+	handler.mu.Unlock()
+
+	require.Eventually(t, func() bool {
+		return outbox.Pending() == 0
+	}, time.Second, time.Millisecond)
+	require.NoError(t, outbox.Close(context.Background()))
+
+	require.Equal(t, []string{event.IdempotencyKey}, handler.keys)
+	require.Len(t, segmentFiles(t, dir), 1)
+}
+
+func TestOutboxDeletesAcknowledgedSegments(t *testing.T) {
+	handler := &outboxServer{}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	outbox := newTestOutbox(t, dir, server, OutboxOpts{SegmentSize: 1})
+	for i := 0; i < 5; i++ {
+		require.NoError(t, outbox.Append(event))
+	}
+	require.NoError(t, outbox.Close(context.Background()))
+
+	require.Len(t, handler.keys, 5)
+	require.Len(t, segmentFiles(t, dir), 1)
+}
+
+func TestOutboxAcknowledgesRejectedEvents(t *testing.T) {
+	handler := &outboxServer{status: http.StatusBadRequest}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	var rejected []CreateEventOpts
+	outbox := newTestOutbox(t, dir, server, OutboxOpts{
+		OnError: func(e CreateEventOpts, err error) {
+			require.True(t, workos_errors.IsBadRequest(err), "unexpected error: %v", err)
+			rejected = append(rejected, e)
+		},
+	})
+	require.NoError(t, outbox.Append(event))
+	require.NoError(t, outbox.Close(context.Background()))
+
+	require.Len(t, rejected, 1)
+	require.Equal(t, event.IdempotencyKey, rejected[0].IdempotencyKey)
+	require.Equal(t, 0, outbox.Pending())
+}
+
+func TestOutboxAcknowledgesPermanentErrors(t *testing.T) {
+	handler := &outboxServer{}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	failure := errors.New("unsupported value")
+	var failed []error
+	outbox := newTestOutbox(t, dir, server, OutboxOpts{
+		Workers: 1,
+		OnError: func(e CreateEventOpts, err error) {
+			failed = append(failed, err)
+		},
+	})
+	outbox.opts.Client.JSONEncode = func(v interface{}) ([]byte, error) {
+		return nil, failure
+	}
+
+	require.NoError(t, outbox.Append(event))
+	require.NoError(t, outbox.Close(context.Background()))
+
+	require.Equal(t, []error{failure}, failed)
+	require.Empty(t, handler.keys)
+	require.Equal(t, 0, outbox.Pending())
+}
+
+func TestOutboxRotationFailure(t *testing.T) {
+	// Hold the responses so that no acknowledgement starts a segment.
+	release := make(chan struct{})
+	handler := &outboxServer{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		handler.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	outbox := newTestOutbox(t, dir, server, OutboxOpts{SegmentSize: 1})
+	require.NoError(t, outbox.Append(event))
+
+	// Make the creation of the next segment fail.
+	next := filepath.Join(dir, fmt.Sprintf("%020d.log", 2))
+	require.NoError(t, os.Mkdir(next, 0700))
+	require.Error(t, outbox.Append(event))
+
+	require.NoError(t, os.Remove(next))
+	require.NoError(t, outbox.Append(event))
+	close(release)
+	require.NoError(t, outbox.Close(context.Background()))
+
+	require.Len(t, handler.keys, 2)
+	require.Equal(t, 0, outbox.Pending())
+}
@@ -0,0 +1,7 @@
+package common
+
+import "errors"
+
+// ErrInvalidDateRange is returned before sending a request whose date range
+// filter is invalid, e.g. when its start is after its end.
+var ErrInvalidDateRange = errors.New("invalid date range")
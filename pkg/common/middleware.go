@@ -0,0 +1,85 @@
+package common
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"time"
+)
+
+// RoundTripFunc sends a request to the WorkOS API and returns its response.
+type RoundTripFunc func(req *http.Request) (*http.Response, error)
+
+// Middleware wraps a RoundTripFunc to inspect or modify the requests sent to
+// the WorkOS API and their responses.
+//
+// Middlewares run for every attempt of a request, retries included. The
+// request they receive already carries the User-Agent, Authorization and
+// Content-Type headers set by the client.
+type Middleware func(next RoundTripFunc) RoundTripFunc
+
+// Chain composes the given middlewares into one. The first middleware is the
+// outermost one: it sees the request first and the response last.
+func Chain(middlewares ...Middleware) Middleware {
+	return func(next RoundTripFunc) RoundTripFunc {
+		for i := len(middlewares) - 1; i >= 0; i-- {
+			next = middlewares[i](next)
+		}
+		return next
+	}
+}
+
+// RequestIDHeader is the header used to identify a request.
+const RequestIDHeader = "X-Request-ID"
+
+// RequestID returns a middleware that sets the X-Request-ID header of the
+// requests that do not have one. IDs are produced by newID, or are random
+// hexadecimal strings when newID is nil.
+func RequestID(newID func() string) Middleware {
+	if newID == nil {
+		newID = randomID
+	}
+
+	return func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			if req.Header.Get(RequestIDHeader) == "" {
+				req.Header.Set(RequestIDHeader, newID())
+			}
+			return next(req)
+		}
+	}
+}
+
+func randomID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(b)
+}
+
+// SetHeaders returns a middleware that sets the given headers on every
+// request, replacing any existing value.
+func SetHeaders(headers http.Header) Middleware {
+	return func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			for k, v := range headers {
+				req.Header[http.CanonicalHeaderKey(k)] = append([]string(nil), v...)
+			}
+			return next(req)
+		}
+	}
+}
+
+// Timing returns a middleware that reports how long each request took to
+// get a response.
+func Timing(observe func(req *http.Request, res *http.Response, err error, elapsed time.Duration)) Middleware {
+	return func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			start := time.Now()
+			res, err := next(req)
+			observe(req, res, err, time.Since(start))
+			return res, err
+		}
+	}
+}
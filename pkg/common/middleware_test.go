@@ -0,0 +1,89 @@
+package common
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestChain(t *testing.T) {
+	var calls []string
+	trace := func(name string) Middleware {
+		return func(next RoundTripFunc) RoundTripFunc {
+			return func(req *http.Request) (*http.Response, error) {
+				calls = append(calls, name+" in")
+				res, err := next(req)
+				calls = append(calls, name+" out")
+				return res, err
+			}
+		}
+	}
+
+	send := Chain(trace("first"), trace("second"))(func(req *http.Request) (*http.Response, error) {
+		calls = append(calls, "send")
+		return &http.Response{StatusCode: http.StatusOK}, nil
+	})
+
+	req, err := http.NewRequest(http.MethodGet, "https://api.workos.com", nil)
+	require.NoError(t, err)
+
+	_, err = send(req)
+	require.NoError(t, err)
+	require.Equal(t, []string{"first in", "second in", "send", "second out", "first out"}, calls)
+}
+
+func TestRequestID(t *testing.T) {
+	var ids []string
+	send := RequestID(nil)(func(req *http.Request) (*http.Response, error) {
+		ids = append(ids, req.Header.Get(RequestIDHeader))
+		return &http.Response{StatusCode: http.StatusOK}, nil
+	})
+
+	req, err := http.NewRequest(http.MethodGet, "https://api.workos.com", nil)
+	require.NoError(t, err)
+	send(req)
+
+	req, err = http.NewRequest(http.MethodGet, "https://api.workos.com", nil)
+	require.NoError(t, err)
+	req.Header.Set(RequestIDHeader, "my-request-id")
+	send(req)
+
+	require.Len(t, ids[0], 32)
+	require.Equal(t, "my-request-id", ids[1])
+}
+
+func TestSetHeaders(t *testing.T) {
+	send := SetHeaders(http.Header{"x-tenant": {"foo-corp"}})(func(req *http.Request) (*http.Response, error) {
+		require.Equal(t, "foo-corp", req.Header.Get("X-Tenant"))
+		return &http.Response{StatusCode: http.StatusOK}, nil
+	})
+
+	req, err := http.NewRequest(http.MethodGet, "https://api.workos.com", nil)
+	require.NoError(t, err)
+	req.Header.Set("X-Tenant", "other")
+
+	_, err = send(req)
+	require.NoError(t, err)
+}
+
+func TestTiming(t *testing.T) {
+	var elapsed time.Duration
+	var status int
+	send := Timing(func(req *http.Request, res *http.Response, err error, d time.Duration) {
+		status = res.StatusCode
+		elapsed = d
+	})(func(req *http.Request) (*http.Response, error) {
+		time.Sleep(5 * time.Millisecond)
+		return &http.Response{StatusCode: http.StatusTeapot}, nil
+	})
+
+	req, err := http.NewRequest(http.MethodGet, "https://api.workos.com", nil)
+	require.NoError(t, err)
+
+	_, err = send(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusTeapot, status)
+	require.True(t, elapsed >= 5*time.Millisecond)
+}
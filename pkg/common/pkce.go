@@ -0,0 +1,52 @@
+package common
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+)
+
+// CodeChallengeMethodS256 is the PKCE code challenge method where the
+// challenge is the SHA-256 hash of the verifier.
+const CodeChallengeMethodS256 = "S256"
+
+// PKCE contains a Proof Key for Code Exchange (RFC 7636), which lets public
+// clients such as native or single-page apps use the authorization code flow
+// without a client secret.
+//
+// The CodeChallenge is sent with the authorization URL and the CodeVerifier
+// with the code exchange, so that only the client that started an
+// authorization request can exchange its code.
+type PKCE struct {
+	// The secret kept by the client until the code exchange.
+	CodeVerifier string
+
+	// The challenge derived from the CodeVerifier.
+	CodeChallenge string
+
+	// The method used to derive the CodeChallenge.
+	CodeChallengeMethod string
+}
+
+// NewPKCE generates a random code verifier and its S256 challenge.
+func NewPKCE() (PKCE, error) {
+	// 32 random bytes make a 43 characters verifier, the minimum length
+	// allowed by RFC 7636.
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return PKCE{}, err
+	}
+
+	verifier := base64.RawURLEncoding.EncodeToString(b)
+	return PKCE{
+		CodeVerifier:        verifier,
+		CodeChallenge:       CodeChallengeS256(verifier),
+		CodeChallengeMethod: CodeChallengeMethodS256,
+	}, nil
+}
+
+// CodeChallengeS256 returns the S256 code challenge of a code verifier.
+func CodeChallengeS256(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
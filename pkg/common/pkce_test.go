@@ -0,0 +1,27 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCodeChallengeS256(t *testing.T) {
+	// Example from RFC 7636, appendix B.
+	require.Equal(t,
+		"E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM",
+		CodeChallengeS256("dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"),
+	)
+}
+
+func TestNewPKCE(t *testing.T) {
+	pkce, err := NewPKCE()
+	require.NoError(t, err)
+	require.Len(t, pkce.CodeVerifier, 43)
+	require.Equal(t, CodeChallengeS256(pkce.CodeVerifier), pkce.CodeChallenge)
+	require.Equal(t, CodeChallengeMethodS256, pkce.CodeChallengeMethod)
+
+	other, err := NewPKCE()
+	require.NoError(t, err)
+	require.NotEqual(t, pkce.CodeVerifier, other.CodeVerifier)
+}
@@ -0,0 +1,75 @@
+package common
+
+import (
+	"net/http"
+	"time"
+)
+
+// RequestOption customizes a single request sent to the WorkOS API without
+// changing the configuration of the client that sends it.
+type RequestOption func(*RequestSettings)
+
+// RequestSettings contains the settings that can be customized for a single
+// request.
+type RequestSettings struct {
+	// The key sent in the Idempotency-Key header. POST requests are only
+	// retried when they have one. Only the requests creating audit log
+	// events or organizations, whose endpoints honor idempotency keys, get
+	// a generated key when they have none: the other POST requests are
+	// neither given a key nor retried unless one is set.
+	IdempotencyKey string
+
+	// The maximum duration of the request, retries included. Zero means no
+	// timeout other than the one of the http.Client and the context.
+	Timeout time.Duration
+
+	// Additional headers sent with the request.
+	Header http.Header
+
+	// The API key sent in the Authorization header, replacing the one of the
+	// client.
+	APIKey string
+}
+
+// NewRequestSettings returns the settings resulting from the given options.
+func NewRequestSettings(opts ...RequestOption) RequestSettings {
+	var s RequestSettings
+	for _, opt := range opts {
+		opt(&s)
+	}
+	return s
+}
+
+// WithIdempotencyKey sets the key sent in the Idempotency-Key header. It makes
+// POST requests retryable, so it should only be used with the endpoints that
+// honor idempotency keys.
+func WithIdempotencyKey(key string) RequestOption {
+	return func(s *RequestSettings) {
+		s.IdempotencyKey = key
+	}
+}
+
+// WithTimeout sets the maximum duration of the request, retries included.
+func WithTimeout(timeout time.Duration) RequestOption {
+	return func(s *RequestSettings) {
+		s.Timeout = timeout
+	}
+}
+
+// WithHeader sets a header on the request.
+func WithHeader(key, value string) RequestOption {
+	return func(s *RequestSettings) {
+		if s.Header == nil {
+			s.Header = make(http.Header)
+		}
+		s.Header.Set(key, value)
+	}
+}
+
+// WithAPIKey sets the API key sent in the Authorization header, replacing
+// the one of the client.
+func WithAPIKey(apiKey string) RequestOption {
+	return func(s *RequestSettings) {
+		s.APIKey = apiKey
+	}
+}
@@ -0,0 +1,83 @@
+package common
+
+import (
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// DefaultRetryPolicy is the policy used by clients that do not configure
+// their own.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxRetries: 3,
+	MinBackoff: 500 * time.Millisecond,
+	MaxBackoff: 30 * time.Second,
+}
+
+// NoRetry is a policy that disables retries.
+var NoRetry = RetryPolicy{}
+
+// RetryPolicy describes how requests to the WorkOS API are retried when they
+// fail with a network error, a 408, a 429 or a 5xx status other than 501.
+//
+// Only idempotent requests are retried: GET, HEAD, OPTIONS, PUT and DELETE
+// requests, and POST requests sent with an Idempotency-Key header.
+type RetryPolicy struct {
+	// Maximum number of retries after the first attempt. Zero disables
+	// retries.
+	MaxRetries int
+
+	// Delay before the first retry. It is doubled on each subsequent retry
+	// and randomized with jitter.
+	MinBackoff time.Duration
+
+	// Upper bound of the delay between two attempts. A Retry-After header
+	// asking to wait longer than MaxBackoff stops the retries.
+	//
+	// Zero means no upper bound.
+	MaxBackoff time.Duration
+}
+
+// Backoff returns the delay to wait before the given retry, starting at 0.
+// The delay grows exponentially and half of it is randomized so that
+// concurrent clients do not retry in lockstep.
+func (p RetryPolicy) Backoff(retry int) time.Duration {
+	d := p.MinBackoff
+	if d <= 0 {
+		return 0
+	}
+	for i := 0; i < retry && d < math.MaxInt64/2; i++ {
+		d *= 2
+	}
+	if p.MaxBackoff > 0 && d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+
+	half := d / 2
+	return half + time.Duration(rand.Int63n(int64(d-half)+1))
+}
+
+// ParseRetryAfter parses the value of a Retry-After header, expressed either
+// in seconds or as an HTTP date, into the delay to wait from now.
+func ParseRetryAfter(v string, now time.Time) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+
+	if secs, err := strconv.Atoi(v); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+
+	if t, err := http.ParseTime(v); err == nil {
+		if d := t.Sub(now); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
@@ -0,0 +1,27 @@
+package common
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	d, ok := ParseRetryAfter("3", now)
+	require.True(t, ok)
+	require.Equal(t, 3*time.Second, d)
+
+	d, ok = ParseRetryAfter(now.Add(5*time.Second).Format(http.TimeFormat), now)
+	require.True(t, ok)
+	require.Equal(t, 5*time.Second, d)
+
+	_, ok = ParseRetryAfter("", now)
+	require.False(t, ok)
+
+	_, ok = ParseRetryAfter("soon", now)
+	require.False(t, ok)
+}
@@ -37,6 +37,13 @@ type Client struct {
 	// Defaults to http.Client.
 	HTTPClient *http.Client
 
+	// The policy used to retry requests that fail with a transient error.
+	// Defaults to common.DefaultRetryPolicy.
+	RetryPolicy *common.RetryPolicy
+
+	// The middlewares that requests are sent through, outermost first.
+	Middlewares []common.Middleware
+
 	// The endpoint to WorkOS API. Defaults to https://api.workos.com.
 	Endpoint string
 
@@ -53,6 +60,15 @@ func (c *Client) init() {
 	}
 }
 
+func (c *Client) do(req *http.Request, reqOpts ...common.RequestOption) (*http.Response, error) {
+	return workos.Transport{
+		HTTPClient:  c.HTTPClient,
+		APIKey:      c.APIKey,
+		RetryPolicy: c.RetryPolicy,
+		Middlewares: c.Middlewares,
+	}.Do(req, reqOpts...)
+}
+
 // ListUsersOpts contains the options to request provisioned Directory Users.
 type ListUsersOpts struct {
 	// Directory unique identifier.
@@ -88,6 +104,7 @@ type ListUsersResponse struct {
 func (c *Client) ListUsers(
 	ctx context.Context,
 	opts ListUsersOpts,
+	reqOpts ...common.RequestOption,
 ) (ListUsersResponse, error) {
 	c.once.Do(c.init)
 
@@ -102,9 +119,6 @@ func (c *Client) ListUsers(
 	}
 
 	req = req.WithContext(ctx)
-	req.Header.Set("Authorization", "Bearer "+c.APIKey)
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
 	if opts.Limit == 0 {
 		opts.Limit = ResponseLimit
 	}
@@ -119,7 +133,7 @@ func (c *Client) ListUsers(
 	}
 
 	req.URL.RawQuery = v.Encode()
-	res, err := c.HTTPClient.Do(req)
+	res, err := c.do(req, reqOpts...)
 	if err != nil {
 		return ListUsersResponse{}, err
 	}
@@ -170,6 +184,7 @@ type ListGroupsResponse struct {
 func (c *Client) ListGroups(
 	ctx context.Context,
 	opts ListGroupsOpts,
+	reqOpts ...common.RequestOption,
 ) (ListGroupsResponse, error) {
 	c.once.Do(c.init)
 
@@ -184,9 +199,6 @@ func (c *Client) ListGroups(
 	}
 
 	req = req.WithContext(ctx)
-	req.Header.Set("Authorization", "Bearer "+c.APIKey)
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
 
 	if opts.Limit == 0 {
 		opts.Limit = ResponseLimit
@@ -202,7 +214,7 @@ func (c *Client) ListGroups(
 	}
 
 	req.URL.RawQuery = v.Encode()
-	res, err := c.HTTPClient.Do(req)
+	res, err := c.do(req, reqOpts...)
 	if err != nil {
 		return ListGroupsResponse{}, err
 	}
@@ -228,6 +240,7 @@ type GetUserOpts struct {
 func (c *Client) GetUser(
 	ctx context.Context,
 	opts GetUserOpts,
+	reqOpts ...common.RequestOption,
 ) (models.DirectoryUser, error) {
 	c.once.Do(c.init)
 
@@ -246,11 +259,8 @@ func (c *Client) GetUser(
 	}
 
 	req = req.WithContext(ctx)
-	req.Header.Set("Authorization", "Bearer "+c.APIKey)
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
 
-	res, err := c.HTTPClient.Do(req)
+	res, err := c.do(req, reqOpts...)
 	if err != nil {
 		return models.DirectoryUser{}, err
 	}
@@ -276,6 +286,7 @@ type GetGroupOpts struct {
 func (c *Client) GetGroup(
 	ctx context.Context,
 	opts GetGroupOpts,
+	reqOpts ...common.RequestOption,
 ) (models.DirectoryGroup, error) {
 	c.once.Do(c.init)
 
@@ -294,11 +305,8 @@ func (c *Client) GetGroup(
 	}
 
 	req = req.WithContext(ctx)
-	req.Header.Set("Authorization", "Bearer "+c.APIKey)
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
 
-	res, err := c.HTTPClient.Do(req)
+	res, err := c.do(req, reqOpts...)
 	if err != nil {
 		return models.DirectoryGroup{}, err
 	}
@@ -352,6 +360,7 @@ type ListDirectoriesResponse struct {
 func (c *Client) ListDirectories(
 	ctx context.Context,
 	opts ListDirectoriesOpts,
+	reqOpts ...common.RequestOption,
 ) (ListDirectoriesResponse, error) {
 	c.once.Do(c.init)
 
@@ -366,9 +375,6 @@ func (c *Client) ListDirectories(
 	}
 
 	req = req.WithContext(ctx)
-	req.Header.Set("Authorization", "Bearer "+c.APIKey)
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
 	if opts.Limit == 0 {
 		opts.Limit = ResponseLimit
 	}
@@ -383,7 +389,7 @@ func (c *Client) ListDirectories(
 	}
 
 	req.URL.RawQuery = v.Encode()
-	res, err := c.HTTPClient.Do(req)
+	res, err := c.do(req, reqOpts...)
 	if err != nil {
 		return ListDirectoriesResponse{}, err
 	}
@@ -408,6 +414,7 @@ type GetDirectoryOpts struct {
 func (c *Client) GetDirectory(
 	ctx context.Context,
 	opts GetDirectoryOpts,
+	reqOpts ...common.RequestOption,
 ) (models.Directory, error) {
 	c.once.Do(c.init)
 
@@ -426,11 +433,8 @@ func (c *Client) GetDirectory(
 	}
 
 	req = req.WithContext(ctx)
-	req.Header.Set("Authorization", "Bearer "+c.APIKey)
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
 
-	res, err := c.HTTPClient.Do(req)
+	res, err := c.do(req, reqOpts...)
 	if err != nil {
 		return models.Directory{}, err
 	}
@@ -456,6 +460,7 @@ type DeleteDirectoryOpts struct {
 func (c *Client) DeleteDirectory(
 	ctx context.Context,
 	opts DeleteDirectoryOpts,
+	reqOpts ...common.RequestOption,
 ) error {
 	c.once.Do(c.init)
 
@@ -474,11 +479,8 @@ func (c *Client) DeleteDirectory(
 	}
 
 	req = req.WithContext(ctx)
-	req.Header.Set("Authorization", "Bearer "+c.APIKey)
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
 
-	res, err := c.HTTPClient.Do(req)
+	res, err := c.do(req, reqOpts...)
 	if err != nil {
 		return err
 	}
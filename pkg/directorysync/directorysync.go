@@ -4,6 +4,7 @@ package directorysync
 import (
 	"context"
 
+	"github.com/omi-lab/workos-go/v4/pkg/common"
 	"github.com/omi-lab/workos-go/v4/pkg/models"
 )
 
@@ -23,54 +24,91 @@ func SetAPIKey(apiKey string) {
 func ListUsers(
 	ctx context.Context,
 	opts ListUsersOpts,
+	reqOpts ...common.RequestOption,
 ) (ListUsersResponse, error) {
-	return DefaultClient.ListUsers(ctx, opts)
+	return DefaultClient.ListUsers(ctx, opts, reqOpts...)
 }
 
 // ListGroups gets a list of provisioned Groups for a Directory.
 func ListGroups(
 	ctx context.Context,
 	opts ListGroupsOpts,
+	reqOpts ...common.RequestOption,
 ) (ListGroupsResponse, error) {
-	return DefaultClient.ListGroups(ctx, opts)
+	return DefaultClient.ListGroups(ctx, opts, reqOpts...)
 }
 
 // GetUser gets a provisioned User for a Directory.
 func GetUser(
 	ctx context.Context,
 	opts GetUserOpts,
+	reqOpts ...common.RequestOption,
 ) (models.DirectoryUser, error) {
-	return DefaultClient.GetUser(ctx, opts)
+	return DefaultClient.GetUser(ctx, opts, reqOpts...)
 }
 
 // GetGroup gets a provisioned Group for a Directory.
 func GetGroup(
 	ctx context.Context,
 	opts GetGroupOpts,
+	reqOpts ...common.RequestOption,
 ) (models.DirectoryGroup, error) {
-	return DefaultClient.GetGroup(ctx, opts)
+	return DefaultClient.GetGroup(ctx, opts, reqOpts...)
 }
 
 // ListDirectories gets details of a Project's Directories.
 func ListDirectories(
 	ctx context.Context,
 	opts ListDirectoriesOpts,
+	reqOpts ...common.RequestOption,
 ) (ListDirectoriesResponse, error) {
-	return DefaultClient.ListDirectories(ctx, opts)
+	return DefaultClient.ListDirectories(ctx, opts, reqOpts...)
 }
 
 // GetDirectory gets a Directory.
 func GetDirectory(
 	ctx context.Context,
 	opts GetDirectoryOpts,
+	reqOpts ...common.RequestOption,
 ) (models.Directory, error) {
-	return DefaultClient.GetDirectory(ctx, opts)
+	return DefaultClient.GetDirectory(ctx, opts, reqOpts...)
 }
 
 // DeleteDirectory deletes a directory
 func DeleteDirectory(
 	ctx context.Context,
 	opts DeleteDirectoryOpts,
+	reqOpts ...common.RequestOption,
 ) error {
-	return DefaultClient.DeleteDirectory(ctx, opts)
+	return DefaultClient.DeleteDirectory(ctx, opts, reqOpts...)
+}
+
+// IterateUsers returns an iterator over the Directory Users matching the given
+// options.
+func IterateUsers(
+	ctx context.Context,
+	opts ListUsersOpts,
+	reqOpts ...common.RequestOption,
+) *UserIterator {
+	return DefaultClient.IterateUsers(ctx, opts, reqOpts...)
+}
+
+// IterateGroups returns an iterator over the Directory Groups matching the given
+// options.
+func IterateGroups(
+	ctx context.Context,
+	opts ListGroupsOpts,
+	reqOpts ...common.RequestOption,
+) *GroupIterator {
+	return DefaultClient.IterateGroups(ctx, opts, reqOpts...)
+}
+
+// IterateDirectories returns an iterator over the Directories matching the given
+// options.
+func IterateDirectories(
+	ctx context.Context,
+	opts ListDirectoriesOpts,
+	reqOpts ...common.RequestOption,
+) *DirectoryIterator {
+	return DefaultClient.IterateDirectories(ctx, opts, reqOpts...)
 }
@@ -0,0 +1,165 @@
+package directorysync
+
+import (
+	"context"
+
+	"github.com/omi-lab/workos-go/v4/internal/workos"
+	"github.com/omi-lab/workos-go/v4/pkg/common"
+	"github.com/omi-lab/workos-go/v4/pkg/models"
+)
+
+// UserIterator iterates over Directory Users, fetching pages as needed.
+//
+//	it := client.IterateUsers(ctx, opts)
+//	for it.Next() {
+//	    v := it.Value()
+//	}
+//	if err := it.Err(); err != nil {
+//	    // Handle error.
+//	}
+type UserIterator struct {
+	pager *workos.Pager
+}
+
+// Next advances the iterator to the next Directory User. It returns false
+// when there are no more Directory Users, when a request fails or when the
+// context is done.
+func (it *UserIterator) Next() bool {
+	return it.pager.Next()
+}
+
+// Value returns the current Directory User.
+func (it *UserIterator) Value() models.DirectoryUser {
+	return it.pager.Value().(models.DirectoryUser)
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *UserIterator) Err() error {
+	return it.pager.Err()
+}
+
+// CollectAll returns the remaining Directory Users, up to max when max is
+// positive.
+func (it *UserIterator) CollectAll(max int) ([]models.DirectoryUser, error) {
+	var all []models.DirectoryUser
+	err := it.pager.CollectAll(max, &all)
+	return all, err
+}
+
+// IterateUsers returns an iterator over the Directory Users matching the
+// given options.
+//
+// The iterator follows the After cursor of each page, or the Before cursor
+// when opts only sets Before.
+func (c *Client) IterateUsers(ctx context.Context, opts ListUsersOpts, reqOpts ...common.RequestOption) *UserIterator {
+	return &UserIterator{pager: workos.NewPager(ctx, opts.Before, opts.After, func(ctx context.Context, before, after string) (interface{}, common.ListMetadata, error) {
+		opts.Before, opts.After = before, after
+
+		res, err := c.ListUsers(ctx, opts, reqOpts...)
+		return res.Data, res.ListMetadata, err
+	})}
+}
+
+// GroupIterator iterates over Directory Groups, fetching pages as needed.
+//
+//	it := client.IterateGroups(ctx, opts)
+//	for it.Next() {
+//	    v := it.Value()
+//	}
+//	if err := it.Err(); err != nil {
+//	    // Handle error.
+//	}
+type GroupIterator struct {
+	pager *workos.Pager
+}
+
+// Next advances the iterator to the next Directory Group. It returns false
+// when there are no more Directory Groups, when a request fails or when the
+// context is done.
+func (it *GroupIterator) Next() bool {
+	return it.pager.Next()
+}
+
+// Value returns the current Directory Group.
+func (it *GroupIterator) Value() models.DirectoryGroup {
+	return it.pager.Value().(models.DirectoryGroup)
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *GroupIterator) Err() error {
+	return it.pager.Err()
+}
+
+// CollectAll returns the remaining Directory Groups, up to max when max is
+// positive.
+func (it *GroupIterator) CollectAll(max int) ([]models.DirectoryGroup, error) {
+	var all []models.DirectoryGroup
+	err := it.pager.CollectAll(max, &all)
+	return all, err
+}
+
+// IterateGroups returns an iterator over the Directory Groups matching the
+// given options.
+//
+// The iterator follows the After cursor of each page, or the Before cursor
+// when opts only sets Before.
+func (c *Client) IterateGroups(ctx context.Context, opts ListGroupsOpts, reqOpts ...common.RequestOption) *GroupIterator {
+	return &GroupIterator{pager: workos.NewPager(ctx, opts.Before, opts.After, func(ctx context.Context, before, after string) (interface{}, common.ListMetadata, error) {
+		opts.Before, opts.After = before, after
+
+		res, err := c.ListGroups(ctx, opts, reqOpts...)
+		return res.Data, res.ListMetadata, err
+	})}
+}
+
+// DirectoryIterator iterates over Directories, fetching pages as needed.
+//
+//	it := client.IterateDirectories(ctx, opts)
+//	for it.Next() {
+//	    v := it.Value()
+//	}
+//	if err := it.Err(); err != nil {
+//	    // Handle error.
+//	}
+type DirectoryIterator struct {
+	pager *workos.Pager
+}
+
+// Next advances the iterator to the next Directory. It returns false when
+// there are no more Directories, when a request fails or when the context is
+// done.
+func (it *DirectoryIterator) Next() bool {
+	return it.pager.Next()
+}
+
+// Value returns the current Directory.
+func (it *DirectoryIterator) Value() models.Directory {
+	return it.pager.Value().(models.Directory)
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *DirectoryIterator) Err() error {
+	return it.pager.Err()
+}
+
+// CollectAll returns the remaining Directories, up to max when max is
+// positive.
+func (it *DirectoryIterator) CollectAll(max int) ([]models.Directory, error) {
+	var all []models.Directory
+	err := it.pager.CollectAll(max, &all)
+	return all, err
+}
+
+// IterateDirectories returns an iterator over the Directories matching the
+// given options.
+//
+// The iterator follows the After cursor of each page, or the Before cursor
+// when opts only sets Before.
+func (c *Client) IterateDirectories(ctx context.Context, opts ListDirectoriesOpts, reqOpts ...common.RequestOption) *DirectoryIterator {
+	return &DirectoryIterator{pager: workos.NewPager(ctx, opts.Before, opts.After, func(ctx context.Context, before, after string) (interface{}, common.ListMetadata, error) {
+		opts.Before, opts.After = before, after
+
+		res, err := c.ListDirectories(ctx, opts, reqOpts...)
+		return res.Data, res.ListMetadata, err
+	})}
+}
@@ -3,6 +3,7 @@ package events
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"sync"
@@ -28,6 +29,13 @@ type Client struct {
 	// Defaults to http.Client.
 	HTTPClient *http.Client
 
+	// The policy used to retry requests that fail with a transient error.
+	// Defaults to common.DefaultRetryPolicy.
+	RetryPolicy *common.RetryPolicy
+
+	// The middlewares that requests are sent through, outermost first.
+	Middlewares []common.Middleware
+
 	// The endpoint to WorkOS API. Defaults to https://api.workos.com.
 	Endpoint string
 
@@ -44,9 +52,23 @@ func (c *Client) init() {
 	}
 }
 
+func (c *Client) do(req *http.Request, reqOpts ...common.RequestOption) (*http.Response, error) {
+	return workos.Transport{
+		HTTPClient:  c.HTTPClient,
+		APIKey:      c.APIKey,
+		RetryPolicy: c.RetryPolicy,
+		Middlewares: c.Middlewares,
+	}.Do(req, reqOpts...)
+}
+
+// ErrNoEventTypes is returned when listing Events without filtering them by
+// type, which the Events API requires.
+var ErrNoEventTypes = errors.New("events: at least one event type must be given in Events")
+
 // ListEventsOpts contains the options to request provisioned Events.
 type ListEventsOpts struct {
-	// Filter to only return Events of particular types.
+	// Filter to only return Events of particular types. At least one type is
+	// required.
 	Events []string `url:"events"`
 
 	// Maximum number of records to return.
@@ -55,15 +77,53 @@ type ListEventsOpts struct {
 	// Pagination cursor to receive records after a provided Event ID.
 	After string `url:"after,omitempty"`
 
-	// Date range start for stream of Events.
+	// Date range start for stream of Events, as an ISO-8601 date. Prefer
+	// RangeStartTime.
 	RangeStart string `url:"range_start,omitempty"`
 
-	// Date range end for stream of Events.
+	// Date range end for stream of Events, as an ISO-8601 date. Prefer
+	// RangeEndTime.
 	RangeEnd string `url:"range_end,omitempty"`
 
+	// Date range start for stream of Events. It must not be set along with
+	// RangeStart.
+	RangeStartTime time.Time `url:"-"`
+
+	// Date range end for stream of Events. It must not be set along with
+	// RangeEnd.
+	RangeEndTime time.Time `url:"-"`
+
+	// Filter to only return the Events of an Organization.
+	OrganizationID string `url:"-"`
+
+	// Deprecated: use OrganizationID instead.
 	OrganizationId string `url:"organization_id,omitempty"`
 }
 
+// validate checks the options before they are sent, and merges the fields
+// that can be given in several ways.
+func (opts *ListEventsOpts) validate() error {
+	if len(opts.Events) == 0 {
+		return ErrNoEventTypes
+	}
+	for _, event := range opts.Events {
+		if event == "" {
+			return errors.New("events: Events must not contain empty event types")
+		}
+	}
+
+	if opts.OrganizationID != "" {
+		if opts.OrganizationId != "" && opts.OrganizationId != opts.OrganizationID {
+			return errors.New("events: OrganizationID and OrganizationId are set to different organizations")
+		}
+		opts.OrganizationId = opts.OrganizationID
+	}
+
+	var err error
+	opts.RangeStart, opts.RangeEnd, err = workos.ResolveDateRange(opts.RangeStart, opts.RangeStartTime, opts.RangeEnd, opts.RangeEndTime)
+	return err
+}
+
 // GetEventsResponse describes the response structure when requesting
 // Events.
 type ListEventsResponse struct {
@@ -75,12 +135,21 @@ type ListEventsResponse struct {
 }
 
 // ListEvents gets a list of Events.
+//
+// Invalid options return an error before any request is sent: ErrNoEventTypes
+// when no event type is given, or an error wrapping common.ErrInvalidDateRange
+// when the date range is invalid.
 func (c *Client) ListEvents(
 	ctx context.Context,
 	opts ListEventsOpts,
+	reqOpts ...common.RequestOption,
 ) (ListEventsResponse, error) {
 	c.once.Do(c.init)
 
+	if err := opts.validate(); err != nil {
+		return ListEventsResponse{}, err
+	}
+
 	endpoint := fmt.Sprintf("%s/events", c.Endpoint)
 	req, err := http.NewRequest(
 		http.MethodGet,
@@ -92,9 +161,6 @@ func (c *Client) ListEvents(
 	}
 
 	req = req.WithContext(ctx)
-	req.Header.Set("Authorization", "Bearer "+c.APIKey)
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
 	if opts.Limit == 0 {
 		opts.Limit = ResponseLimit
 	}
@@ -105,7 +171,7 @@ func (c *Client) ListEvents(
 	}
 
 	req.URL.RawQuery = queryValues.Encode()
-	res, err := c.HTTPClient.Do(req)
+	res, err := c.do(req, reqOpts...)
 	if err != nil {
 		return ListEventsResponse{}, err
 	}
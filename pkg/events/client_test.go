@@ -3,6 +3,7 @@ package events
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -61,8 +62,8 @@ func TestListEvents(t *testing.T) {
 
 		params := ListEventsOpts{
 			Events:     []string{"dsync.user.created"},
-			RangeStart: rangeStart.String(),
-			RangeEnd:   rangeEnd.String(),
+			RangeStart: rangeStart.Format(time.RFC3339),
+			RangeEnd:   rangeEnd.Format(time.RFC3339),
 		}
 
 		expectedResponse := ListEventsResponse{
@@ -118,6 +119,105 @@ func TestListEvents(t *testing.T) {
 	})
 }
 
+func TestListEventsValidatesOpts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("unexpected request")
+	}))
+	defer server.Close()
+	client := &Client{
+		HTTPClient: server.Client(),
+		Endpoint:   server.URL,
+		APIKey:     "test",
+	}
+
+	start := time.Date(2024, time.January, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		scenario string
+		opts     ListEventsOpts
+		err      error
+	}{
+		{
+			scenario: "no event types",
+			opts:     ListEventsOpts{},
+			err:      ErrNoEventTypes,
+		},
+		{
+			scenario: "start after end",
+			opts: ListEventsOpts{
+				Events:         []string{"dsync.user.created"},
+				RangeStartTime: start,
+				RangeEndTime:   start.Add(-time.Hour),
+			},
+			err: common.ErrInvalidDateRange,
+		},
+		{
+			scenario: "start given twice",
+			opts: ListEventsOpts{
+				Events:         []string{"dsync.user.created"},
+				RangeStart:     start.Format(time.RFC3339),
+				RangeStartTime: start,
+			},
+			err: common.ErrInvalidDateRange,
+		},
+		{
+			scenario: "malformed start",
+			opts: ListEventsOpts{
+				Events:     []string{"dsync.user.created"},
+				RangeStart: start.String(),
+			},
+			err: common.ErrInvalidDateRange,
+		},
+		{
+			scenario: "start string after end time",
+			opts: ListEventsOpts{
+				Events:       []string{"dsync.user.created"},
+				RangeStart:   start.Format(time.RFC3339),
+				RangeEndTime: start,
+			},
+			err: common.ErrInvalidDateRange,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			_, err := client.ListEvents(context.Background(), test.opts)
+			require.True(t, errors.Is(err, test.err), "unexpected error: %v", err)
+		})
+	}
+
+	_, err := client.ListEvents(context.Background(), ListEventsOpts{
+		Events:         []string{"dsync.user.created"},
+		OrganizationID: "org_01",
+		OrganizationId: "org_02",
+	})
+	require.EqualError(t, err, "events: OrganizationID and OrganizationId are set to different organizations")
+}
+
+func TestListEventsWithTimeRange(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		require.Equal(t, "2024-01-01T12:00:00Z", q.Get("range_start"))
+		require.Equal(t, "2024-01-02T12:00:00Z", q.Get("range_end"))
+		require.Equal(t, "org_01", q.Get("organization_id"))
+		json.NewEncoder(w).Encode(ListEventsResponse{})
+	}))
+	defer server.Close()
+	client := &Client{
+		HTTPClient: server.Client(),
+		Endpoint:   server.URL,
+		APIKey:     "test",
+	}
+
+	start := time.Date(2024, time.January, 1, 12, 0, 0, 0, time.UTC)
+	_, err := client.ListEvents(context.Background(), ListEventsOpts{
+		Events:         []string{"dsync.user.created"},
+		RangeStartTime: start,
+		RangeEndTime:   start.Add(24 * time.Hour),
+		OrganizationID: "org_01",
+	})
+	require.NoError(t, err)
+}
+
 func ListEventsTestHandler(w http.ResponseWriter, r *http.Request) {
 	auth := r.Header.Get("Authorization")
 	if auth != "Bearer test" {
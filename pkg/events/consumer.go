@@ -0,0 +1,246 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/omi-lab/workos-go/v4/pkg/common"
+	"github.com/omi-lab/workos-go/v4/pkg/models"
+	"github.com/omi-lab/workos-go/v4/pkg/workos_errors"
+)
+
+const (
+	// DefaultPollInterval is the default time a Consumer waits for new
+	// Events once it has processed all of them.
+	DefaultPollInterval = 5 * time.Second
+
+	// consumerLimit is the number of Events a Consumer requests at once.
+	consumerLimit = 100
+)
+
+// DefaultConsumerBackoff is the default policy a Consumer waits with after
+// an error. Its MaxRetries is ignored: a Consumer retries until its context
+// is done.
+var DefaultConsumerBackoff = common.RetryPolicy{
+	MinBackoff: time.Second,
+	MaxBackoff: time.Minute,
+}
+
+// ErrNoDelivery is returned by Consumer.Run when the Consumer has neither a
+// Handler nor a Channel.
+var ErrNoDelivery = errors.New("events: consumer has no handler and no channel")
+
+// Consumer polls the Events API continuously and delivers each Event once,
+// oldest first, to a handler or a channel.
+//
+// The ID of the last processed Event is saved to a CursorStore, so that a
+// restarted Consumer resumes where it stopped. Events are delivered at least
+// once: an Event whose processing was interrupted by a crash is delivered
+// again.
+//
+//	consumer := &events.Consumer{
+//		Client:  client,
+//		Events:  []string{models.EventDirectoryUserCreated},
+//		Cursors: &events.FileCursorStore{Path: "events.cursor"},
+//		Handler: func(ctx context.Context, e models.Event) error {
+//			return sync(ctx, e)
+//		},
+//	}
+//	err := consumer.Run(ctx)
+type Consumer struct {
+	// The client used to list Events. Defaults to DefaultClient.
+	Client *Client
+
+	// Filter to only consume Events of particular types. At least one type
+	// is required.
+	Events []string
+
+	// Filter to only consume the Events of an Organization.
+	OrganizationID string
+
+	// The store the cursor is saved to. Defaults to a MemoryCursorStore.
+	Cursors CursorStore
+
+	// The function Events are delivered to. An Event is processed once
+	// Handler returns nil. When it returns an error, the Event is delivered
+	// again after a backoff, and the following Events wait.
+	//
+	// Handler is called with a context that is not canceled when the context
+	// given to Run is, so that the Event being processed can complete.
+	Handler func(ctx context.Context, event models.Event) error
+
+	// The channel Events are delivered to when Handler is nil. An Event is
+	// processed once it is received from the channel.
+	Channel chan<- models.Event
+
+	// The time to wait for new Events once all of them were processed.
+	// Defaults to DefaultPollInterval.
+	PollInterval time.Duration
+
+	// The policy to wait with after an error. Defaults to
+	// DefaultConsumerBackoff.
+	Backoff *common.RetryPolicy
+
+	// The function the errors that are retried are reported to. Defaults to
+	// logging them with the standard logger.
+	OnError func(err error)
+
+	// Options applied to each request to the Events API.
+	RequestOptions []common.RequestOption
+}
+
+// Run consumes Events until the context is done, then returns nil once the
+// Event being processed, if any, was processed.
+//
+// Errors listing Events, processing them or saving the cursor are reported to
+// OnError and retried with a backoff. Run returns an error when the cursor
+// can not be loaded, or when the options or the API key are rejected.
+func (c *Consumer) Run(ctx context.Context) error {
+	if c.Handler == nil && c.Channel == nil {
+		return ErrNoDelivery
+	}
+
+	opts := ListEventsOpts{
+		Events:         c.Events,
+		Limit:          consumerLimit,
+		OrganizationID: c.OrganizationID,
+	}
+	if err := opts.validate(); err != nil {
+		return err
+	}
+
+	cursors := c.Cursors
+	if cursors == nil {
+		cursors = &MemoryCursorStore{}
+	}
+
+	cursor, err := cursors.Load(ctx)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil
+		}
+		return err
+	}
+
+	failures := 0
+	fail := func(err error) {
+		c.reportError(err)
+		failures++
+		c.sleep(ctx, c.backoff().Backoff(failures-1))
+	}
+
+	for ctx.Err() == nil {
+		opts.After = cursor
+		res, err := c.client().ListEvents(ctx, opts, c.RequestOptions...)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			if isPermanent(err) {
+				return err
+			}
+			fail(err)
+			continue
+		}
+
+		for _, event := range res.Data {
+			if err = c.deliver(ctx, event); err != nil {
+				break
+			}
+			if err = cursors.Save(detach(ctx), event.ID); err != nil {
+				break
+			}
+			cursor = event.ID
+		}
+		if err != nil {
+			if ctx.Err() == nil {
+				fail(err)
+			}
+			continue
+		}
+
+		failures = 0
+		if len(res.Data) < consumerLimit {
+			c.sleep(ctx, c.pollInterval())
+		}
+	}
+	return nil
+}
+
+// deliver delivers an Event to the handler or the channel.
+func (c *Consumer) deliver(ctx context.Context, event models.Event) error {
+	if c.Handler != nil {
+		return c.Handler(detach(ctx), event)
+	}
+
+	select {
+	case c.Channel <- event:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (c *Consumer) client() *Client {
+	if c.Client != nil {
+		return c.Client
+	}
+	return DefaultClient
+}
+
+func (c *Consumer) pollInterval() time.Duration {
+	if c.PollInterval > 0 {
+		return c.PollInterval
+	}
+	return DefaultPollInterval
+}
+
+func (c *Consumer) backoff() *common.RetryPolicy {
+	if c.Backoff != nil {
+		return c.Backoff
+	}
+	return &DefaultConsumerBackoff
+}
+
+func (c *Consumer) reportError(err error) {
+	if c.OnError != nil {
+		c.OnError(err)
+		return
+	}
+	log.Printf("events: consumer error: %v", err)
+}
+
+// sleep waits for the given duration, or until the context is done.
+func (c *Consumer) sleep(ctx context.Context, d time.Duration) {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+}
+
+// isPermanent reports whether listing Events failed because of the API key
+// or the options, which retrying does not fix.
+func isPermanent(err error) bool {
+	return workos_errors.IsBadRequest(err) ||
+		workos_errors.IsUnauthorized(err) ||
+		workos_errors.IsForbidden(err) ||
+		workos_errors.IsNotFound(err)
+}
+
+// detachedContext carries the values of its parent but is never canceled.
+type detachedContext struct {
+	context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) { return time.Time{}, false }
+func (detachedContext) Done() <-chan struct{}       { return nil }
+func (detachedContext) Err() error                  { return nil }
+
+func detach(ctx context.Context) context.Context {
+	return detachedContext{ctx}
+}
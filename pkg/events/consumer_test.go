@@ -0,0 +1,173 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/omi-lab/workos-go/v4/pkg/common"
+	"github.com/omi-lab/workos-go/v4/pkg/models"
+	"github.com/omi-lab/workos-go/v4/pkg/workos_errors"
+	"github.com/omi-lab/workos-go/v4/pkg/workostest"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestConsumer(server *workostest.Server, apiKey string) *Consumer {
+	return &Consumer{
+		Client: &Client{
+			APIKey:      apiKey,
+			Endpoint:    server.URL,
+			RetryPolicy: &common.NoRetry,
+		},
+		Events:       []string{models.EventUserCreated, models.EventUserDeleted},
+		PollInterval: 10 * time.Millisecond,
+		Backoff:      &common.RetryPolicy{MinBackoff: time.Millisecond},
+	}
+}
+
+// consumeN runs a consumer until it processed n Events, and returns their
+// IDs.
+func consumeN(t *testing.T, consumer *Consumer, n int) []string {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var ids []string
+	handler := consumer.Handler
+	consumer.Handler = func(ctx context.Context, e models.Event) error {
+		if handler != nil {
+			if err := handler(ctx, e); err != nil {
+				return err
+			}
+		}
+		ids = append(ids, e.ID)
+		if len(ids) == n {
+			cancel()
+		}
+		return nil
+	}
+
+	require.NoError(t, consumer.Run(ctx))
+	require.Len(t, ids, n)
+	return ids
+}
+
+func TestConsumerResumesAfterRestart(t *testing.T) {
+	server := workostest.NewServer("test")
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "events")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	cursors := &FileCursorStore{Path: filepath.Join(dir, "cursor")}
+
+	first := server.AddEvent(models.EventUserCreated, models.User{ID: "user_01"})
+	second := server.AddEvent(models.EventUserCreated, models.User{ID: "user_02"})
+
+	consumer := newTestConsumer(server, "test")
+	consumer.Cursors = cursors
+	require.Equal(t, []string{first.ID, second.ID}, consumeN(t, consumer, 2))
+
+	cursor, err := cursors.Load(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, second.ID, cursor)
+
+	third := server.AddEvent(models.EventUserDeleted, models.User{ID: "user_01"})
+
+	consumer = newTestConsumer(server, "test")
+	consumer.Cursors = cursors
+	require.Equal(t, []string{third.ID}, consumeN(t, consumer, 1))
+}
+
+func TestConsumerRetriesFailedEvents(t *testing.T) {
+	server := workostest.NewServer("test")
+	defer server.Close()
+
+	first := server.AddEvent(models.EventUserCreated, models.User{ID: "user_01"})
+	second := server.AddEvent(models.EventUserCreated, models.User{ID: "user_02"})
+
+	failure := errors.New("database unavailable")
+	failed := false
+	var reported []error
+
+	consumer := newTestConsumer(server, "test")
+	consumer.OnError = func(err error) {
+		reported = append(reported, err)
+	}
+	consumer.Handler = func(ctx context.Context, e models.Event) error {
+		if e.ID == second.ID && !failed {
+			failed = true
+			return failure
+		}
+		return nil
+	}
+
+	require.Equal(t, []string{first.ID, second.ID}, consumeN(t, consumer, 2))
+	require.Equal(t, []error{failure}, reported)
+}
+
+func TestConsumerDeliversToChannel(t *testing.T) {
+	server := workostest.NewServer("test")
+	defer server.Close()
+
+	first := server.AddEvent(models.EventUserCreated, models.User{ID: "user_01"})
+	server.AddEvent(models.EventDirectoryUserCreated, models.DirectoryUser{ID: "directory_user_01"})
+	third := server.AddEvent(models.EventUserDeleted, models.User{ID: "user_01"})
+
+	ch := make(chan models.Event)
+	consumer := newTestConsumer(server, "test")
+	consumer.Channel = ch
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- consumer.Run(ctx)
+	}()
+
+	require.Equal(t, first.ID, (<-ch).ID)
+	require.Equal(t, third.ID, (<-ch).ID)
+
+	cancel()
+	require.NoError(t, <-done)
+}
+
+func TestConsumerStopsOnPermanentErrors(t *testing.T) {
+	server := workostest.NewServer("test")
+	defer server.Close()
+
+	consumer := newTestConsumer(server, "wrong_key")
+	consumer.Handler = func(ctx context.Context, e models.Event) error {
+		return nil
+	}
+
+	err := consumer.Run(context.Background())
+	require.True(t, workos_errors.IsUnauthorized(err), "unexpected error: %v", err)
+}
+
+func TestConsumerWithoutDelivery(t *testing.T) {
+	consumer := &Consumer{}
+	require.Equal(t, ErrNoDelivery, consumer.Run(context.Background()))
+}
+
+func TestFileCursorStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "events")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	store := &FileCursorStore{Path: filepath.Join(dir, "cursor")}
+	ctx := context.Background()
+
+	cursor, err := store.Load(ctx)
+	require.NoError(t, err)
+	require.Empty(t, cursor)
+
+	require.NoError(t, store.Save(ctx, "event_01"))
+	require.NoError(t, store.Save(ctx, "event_02"))
+
+	cursor, err = (&FileCursorStore{Path: store.Path}).Load(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "event_02", cursor)
+}
@@ -0,0 +1,98 @@
+package events
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// CursorStore persists the ID of the last Event processed by a Consumer, so
+// that it resumes after it when it is restarted.
+//
+// A CursorStore must be safe for concurrent use.
+type CursorStore interface {
+	// Load returns the saved cursor, or an empty string when there is none.
+	Load(ctx context.Context) (string, error)
+
+	// Save replaces the saved cursor.
+	Save(ctx context.Context, cursor string) error
+}
+
+// MemoryCursorStore is a CursorStore that keeps the cursor in memory. The
+// zero value is ready to use.
+type MemoryCursorStore struct {
+	mu     sync.Mutex
+	cursor string
+}
+
+// Load implements CursorStore.
+func (s *MemoryCursorStore) Load(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.cursor, nil
+}
+
+// Save implements CursorStore.
+func (s *MemoryCursorStore) Save(ctx context.Context, cursor string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.cursor = cursor
+	return nil
+}
+
+// FileCursorStore is a CursorStore that keeps the cursor in a file. The file
+// is replaced atomically, so a crash leaves either the previous or the new
+// cursor.
+type FileCursorStore struct {
+	// The path of the file.
+	Path string
+
+	mu sync.Mutex
+}
+
+// Load implements CursorStore. It returns an empty cursor when the file does
+// not exist.
+func (s *FileCursorStore) Load(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, err := ioutil.ReadFile(s.Path)
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(b)), nil
+}
+
+// Save implements CursorStore. The cursor is synced to disk before it
+// returns.
+func (s *FileCursorStore) Save(ctx context.Context, cursor string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tmp, err := ioutil.TempFile(filepath.Dir(s.Path), filepath.Base(s.Path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err = tmp.WriteString(cursor + "\n"); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.Path)
+}
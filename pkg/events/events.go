@@ -3,6 +3,8 @@ package events
 
 import (
 	"context"
+
+	"github.com/omi-lab/workos-go/v4/pkg/common"
 )
 
 // DefaultClient is the client used by SetAPIKey and Event functions.
@@ -21,6 +23,17 @@ func SetAPIKey(apiKey string) {
 func ListEvents(
 	ctx context.Context,
 	opts ListEventsOpts,
+	reqOpts ...common.RequestOption,
 ) (ListEventsResponse, error) {
-	return DefaultClient.ListEvents(ctx, opts)
+	return DefaultClient.ListEvents(ctx, opts, reqOpts...)
+}
+
+// IterateEvents returns an iterator over the Events matching the given
+// options.
+func IterateEvents(
+	ctx context.Context,
+	opts ListEventsOpts,
+	reqOpts ...common.RequestOption,
+) *EventIterator {
+	return DefaultClient.IterateEvents(ctx, opts, reqOpts...)
 }
@@ -0,0 +1,58 @@
+package events
+
+import (
+	"context"
+
+	"github.com/omi-lab/workos-go/v4/internal/workos"
+	"github.com/omi-lab/workos-go/v4/pkg/common"
+	"github.com/omi-lab/workos-go/v4/pkg/models"
+)
+
+// EventIterator iterates over Events, fetching pages as needed.
+//
+//	it := client.IterateEvents(ctx, opts)
+//	for it.Next() {
+//	    v := it.Value()
+//	}
+//	if err := it.Err(); err != nil {
+//	    // Handle error.
+//	}
+type EventIterator struct {
+	pager *workos.Pager
+}
+
+// Next advances the iterator to the next Event. It returns false when there
+// are no more Events, when a request fails or when the context is done.
+func (it *EventIterator) Next() bool {
+	return it.pager.Next()
+}
+
+// Value returns the current Event.
+func (it *EventIterator) Value() models.Event {
+	return it.pager.Value().(models.Event)
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *EventIterator) Err() error {
+	return it.pager.Err()
+}
+
+// CollectAll returns the remaining Events, up to max when max is positive.
+func (it *EventIterator) CollectAll(max int) ([]models.Event, error) {
+	var all []models.Event
+	err := it.pager.CollectAll(max, &all)
+	return all, err
+}
+
+// IterateEvents returns an iterator over the Events matching the given
+// options.
+//
+// The iterator follows the After cursor of each page.
+func (c *Client) IterateEvents(ctx context.Context, opts ListEventsOpts, reqOpts ...common.RequestOption) *EventIterator {
+	return &EventIterator{pager: workos.NewPager(ctx, "", opts.After, func(ctx context.Context, before, after string) (interface{}, common.ListMetadata, error) {
+		opts.After = after
+
+		res, err := c.ListEvents(ctx, opts, reqOpts...)
+		return res.Data, res.ListMetadata, err
+	})}
+}
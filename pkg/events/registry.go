@@ -0,0 +1,115 @@
+package events
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/omi-lab/workos-go/v4/pkg/models"
+	"github.com/omi-lab/workos-go/v4/pkg/webhooks"
+)
+
+// EventHandler processes Events, whether they were received as webhooks or
+// listed from the Events API.
+type EventHandler interface {
+	HandleEvent(ctx context.Context, event models.Event) error
+}
+
+// EventHandlerFunc is a function that implements EventHandler.
+type EventHandlerFunc func(ctx context.Context, event models.Event) error
+
+// HandleEvent calls f.
+func (f EventHandlerFunc) HandleEvent(ctx context.Context, event models.Event) error {
+	return f(ctx, event)
+}
+
+// Registry dispatches Events to the EventHandlers registered for their type.
+// It can be fed by webhooks, with WebhookHandler, and by the Events API, with
+// a Consumer, so that the business logic does not depend on how Events are
+// delivered:
+//
+//	registry := events.NewRegistry()
+//	registry.HandleFunc(models.EventDirectoryUserCreated, func(ctx context.Context, e models.Event) error {
+//		user, err := e.AsDirectoryUser()
+//		...
+//	})
+//
+//	http.Handle("/webhooks", registry.WebhookHandler(secret))
+//	consumer := &events.Consumer{
+//		Events:  []string{models.EventDirectoryUserCreated},
+//		Handler: registry.HandleEvent,
+//	}
+//
+// When both are used, set a DedupeStore so that each Event is processed once.
+//
+// Handlers must be registered before the Registry is used to dispatch Events.
+type Registry struct {
+	handlers map[string]EventHandler
+	fallback EventHandler
+	dedupe   webhooks.DedupeStore
+}
+
+// NewRegistry creates an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{handlers: make(map[string]EventHandler)}
+}
+
+// Handle registers the handler of an Event type, replacing the previous one.
+func (r *Registry) Handle(event string, h EventHandler) {
+	if r.handlers == nil {
+		r.handlers = make(map[string]EventHandler)
+	}
+	r.handlers[event] = h
+}
+
+// HandleFunc registers a function as the handler of an Event type.
+func (r *Registry) HandleFunc(event string, f func(ctx context.Context, event models.Event) error) {
+	r.Handle(event, EventHandlerFunc(f))
+}
+
+// Fallback registers the handler of the Events that have no handler. Events
+// are ignored when no fallback is registered.
+func (r *Registry) Fallback(h EventHandler) {
+	r.fallback = h
+}
+
+// SetDedupeStore sets the store used to process each Event once, by ID, when
+// it is delivered several times, e.g. both as a webhook and by the Events
+// API. Events are not deduplicated by default.
+func (r *Registry) SetDedupeStore(store webhooks.DedupeStore) {
+	r.dedupe = store
+}
+
+// HandleEvent implements EventHandler by calling the handler of the type of
+// the Event. Duplicate Events are ignored.
+//
+// The Event is settled in the DedupeStore with webhooks.Settle, like the
+// handler of webhooks does: it is completed once the handler succeeded, and
+// released when the handler fails so that its next delivery is processed,
+// unless the error was marked with webhooks.Permanent.
+func (r *Registry) HandleEvent(ctx context.Context, event models.Event) error {
+	h, ok := r.handlers[event.Event]
+	if !ok {
+		h = r.fallback
+	}
+	if h == nil {
+		return nil
+	}
+
+	if r.dedupe == nil || event.ID == "" {
+		return h.HandleEvent(ctx, event)
+	}
+
+	claimed, err := r.dedupe.Claim(ctx, event.ID)
+	if err != nil || !claimed {
+		return err
+	}
+
+	return webhooks.Settle(r.dedupe, event.ID, h.HandleEvent(ctx, event))
+}
+
+// WebhookHandler returns an http.Handler that verifies the webhooks signed
+// with the given secret, like webhooks.Handler, and dispatches their Event to
+// the Registry.
+func (r *Registry) WebhookHandler(secret string, opts ...webhooks.HandlerOption) http.Handler {
+	return webhooks.EventsHandler(secret, r.HandleEvent, opts...)
+}
@@ -0,0 +1,109 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/omi-lab/workos-go/v4/pkg/models"
+	"github.com/omi-lab/workos-go/v4/pkg/webhooks"
+	"github.com/omi-lab/workos-go/v4/pkg/workostest"
+	"github.com/stretchr/testify/require"
+)
+
+func deliverWebhook(t *testing.T, h http.Handler, secret string, event models.Event) int {
+	body, err := json.Marshal(event)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/webhooks", strings.NewReader(string(body)))
+	req.Header.Set(webhooks.SignatureHeader, webhooks.Sign(secret, string(body), time.Now()))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec.Code
+}
+
+func TestRegistry(t *testing.T) {
+	var users, fallback []string
+
+	registry := NewRegistry()
+	registry.HandleFunc(models.EventUserCreated, func(ctx context.Context, e models.Event) error {
+		user, err := e.AsUser()
+		users = append(users, user.ID)
+		return err
+	})
+
+	ctx := context.Background()
+	require.NoError(t, registry.HandleEvent(ctx, models.Event{
+		ID:    "event_01",
+		Event: models.EventUserCreated,
+		Data:  json.RawMessage(`{"id": "user_01"}`),
+	}))
+	require.NoError(t, registry.HandleEvent(ctx, models.Event{ID: "event_02", Event: models.EventUserDeleted}))
+	require.Equal(t, []string{"user_01"}, users)
+
+	registry.Fallback(EventHandlerFunc(func(ctx context.Context, e models.Event) error {
+		fallback = append(fallback, e.Event)
+		return nil
+	}))
+	require.NoError(t, registry.HandleEvent(ctx, models.Event{ID: "event_02", Event: models.EventUserDeleted}))
+	require.Equal(t, []string{models.EventUserDeleted}, fallback)
+}
+
+func TestRegistryWithDedupeStore(t *testing.T) {
+	failure := errors.New("database unavailable")
+	handlerErr := failure
+	handled := 0
+
+	registry := NewRegistry()
+	registry.SetDedupeStore(webhooks.NewMemoryDedupeStore(time.Hour))
+	registry.HandleFunc(models.EventUserCreated, func(ctx context.Context, e models.Event) error {
+		handled++
+		return handlerErr
+	})
+
+	ctx := context.Background()
+	event := models.Event{ID: "event_01", Event: models.EventUserCreated}
+
+	// A failed Event is processed again.
+	require.Equal(t, failure, registry.HandleEvent(ctx, event))
+	handlerErr = nil
+	require.NoError(t, registry.HandleEvent(ctx, event))
+	require.Equal(t, 2, handled)
+
+	// A processed Event is not.
+	require.NoError(t, registry.HandleEvent(ctx, event))
+	require.Equal(t, 2, handled)
+}
+
+func TestRegistryFedByWebhooksAndConsumer(t *testing.T) {
+	server := workostest.NewServer("test")
+	defer server.Close()
+
+	first := server.AddEvent(models.EventUserCreated, models.User{ID: "user_01"})
+	second := server.AddEvent(models.EventUserCreated, models.User{ID: "user_02"})
+
+	var users []string
+	registry := NewRegistry()
+	registry.SetDedupeStore(webhooks.NewMemoryDedupeStore(time.Hour))
+	registry.HandleFunc(models.EventUserCreated, func(ctx context.Context, e models.Event) error {
+		user, err := e.AsUser()
+		users = append(users, user.ID)
+		return err
+	})
+
+	// The first Event is received as a webhook, then both are listed.
+	h := registry.WebhookHandler("secret")
+	require.Equal(t, http.StatusOK, deliverWebhook(t, h, "secret", first))
+
+	consumer := newTestConsumer(server, "test")
+	consumer.Handler = registry.HandleEvent
+	require.Equal(t, []string{first.ID, second.ID}, consumeN(t, consumer, 2))
+
+	require.Equal(t, []string{"user_01", "user_02"}, users)
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/omi-lab/workos-go/v4/pkg/workos_errors"
 
 	"github.com/omi-lab/workos-go/v4/internal/workos"
+	"github.com/omi-lab/workos-go/v4/pkg/common"
 )
 
 // This represents the list of errors that could be raised when using the mfa package
@@ -34,6 +35,13 @@ type Client struct {
 	// Defaults to http.Client.
 	HTTPClient *http.Client
 
+	// The policy used to retry requests that fail with a transient error.
+	// Defaults to common.DefaultRetryPolicy.
+	RetryPolicy *common.RetryPolicy
+
+	// The middlewares that requests are sent through, outermost first.
+	Middlewares []common.Middleware
+
 	// The endpoint to WorkOS API. Defaults to https://api.workos.com.
 	Endpoint string
 
@@ -58,6 +66,15 @@ func (c *Client) init() {
 	}
 }
 
+func (c *Client) do(req *http.Request, reqOpts ...common.RequestOption) (*http.Response, error) {
+	return workos.Transport{
+		HTTPClient:  c.HTTPClient,
+		APIKey:      c.APIKey,
+		RetryPolicy: c.RetryPolicy,
+		Middlewares: c.Middlewares,
+	}.Do(req, reqOpts...)
+}
+
 // EnrollFactorOpts contains the options to create an Authentication Factor.
 type EnrollFactorOpts struct {
 
@@ -125,6 +142,7 @@ type GetFactorOpts struct {
 func (c *Client) EnrollFactor(
 	ctx context.Context,
 	opts EnrollFactorOpts,
+	reqOpts ...common.RequestOption,
 ) (models.Factor, error) {
 	c.once.Do(c.init)
 
@@ -153,10 +171,7 @@ func (c *Client) EnrollFactor(
 	if err != nil {
 		return models.Factor{}, err
 	}
-	req.Header.Add("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+c.APIKey)
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
-	resp, err := c.HTTPClient.Do(req)
+	resp, err := c.do(req, reqOpts...)
 	if err != nil {
 		return models.Factor{}, err
 	}
@@ -176,6 +191,7 @@ func (c *Client) EnrollFactor(
 func (c *Client) ChallengeFactor(
 	ctx context.Context,
 	opts ChallengeFactorOpts,
+	reqOpts ...common.RequestOption,
 ) (models.Challenge, error) {
 	c.once.Do(c.init)
 
@@ -193,11 +209,8 @@ func (c *Client) ChallengeFactor(
 	if err != nil {
 		return models.Challenge{}, err
 	}
-	req.Header.Add("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+c.APIKey)
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
 
-	resp, err := c.HTTPClient.Do(req)
+	resp, err := c.do(req, reqOpts...)
 	if err != nil {
 		return models.Challenge{}, err
 	}
@@ -215,8 +228,8 @@ func (c *Client) ChallengeFactor(
 }
 
 // Deprecated: Use VerifyChallenge instead.
-func (c *Client) VerifyFactor(ctx context.Context, opts VerifyChallengeOpts) (interface{}, error) {
-	return c.VerifyChallenge(ctx, opts)
+func (c *Client) VerifyFactor(ctx context.Context, opts VerifyChallengeOpts, reqOpts ...common.RequestOption) (interface{}, error) {
+	return c.VerifyChallenge(ctx, opts, reqOpts...)
 }
 
 type VerificationResponseError struct {
@@ -232,6 +245,7 @@ func (r VerificationResponseError) Error() string {
 func (c *Client) VerifyChallenge(
 	ctx context.Context,
 	opts VerifyChallengeOpts,
+	reqOpts ...common.RequestOption,
 ) (VerifyChallengeResponse, error) {
 	c.once.Do(c.init)
 
@@ -249,10 +263,7 @@ func (c *Client) VerifyChallenge(
 	if err != nil {
 		return VerifyChallengeResponse{}, err
 	}
-	req.Header.Add("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+c.APIKey)
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
-	resp, err := c.HTTPClient.Do(req)
+	resp, err := c.do(req, reqOpts...)
 	if err != nil {
 		return VerifyChallengeResponse{}, err
 	}
@@ -275,6 +286,7 @@ func (c *Client) VerifyChallenge(
 func (c *Client) DeleteFactor(
 	ctx context.Context,
 	opts DeleteFactorOpts,
+	reqOpts ...common.RequestOption,
 ) error {
 	c.once.Do(c.init)
 
@@ -293,11 +305,7 @@ func (c *Client) DeleteFactor(
 		return err
 	}
 
-	req.Header.Set("Authorization", "Bearer "+c.APIKey)
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
-
-	res, err := c.HTTPClient.Do(req)
+	res, err := c.do(req, reqOpts...)
 	if err != nil {
 		return err
 	}
@@ -310,6 +318,7 @@ func (c *Client) DeleteFactor(
 func (c *Client) GetFactor(
 	ctx context.Context,
 	opts GetFactorOpts,
+	reqOpts ...common.RequestOption,
 ) (models.Factor, error) {
 	c.once.Do(c.init)
 
@@ -318,11 +327,8 @@ func (c *Client) GetFactor(
 	if err != nil {
 		return models.Factor{}, err
 	}
-	req.Header.Set("Authorization", "Bearer "+c.APIKey)
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
 
-	res, err := c.HTTPClient.Do(req)
+	res, err := c.do(req, reqOpts...)
 	if err != nil {
 		return models.Factor{}, err
 	}
@@ -3,6 +3,7 @@ package mfa
 import (
 	"context"
 
+	"github.com/omi-lab/workos-go/v4/pkg/common"
 	"github.com/omi-lab/workos-go/v4/pkg/models"
 )
 
@@ -22,46 +23,52 @@ func SetAPIKey(apiKey string) {
 func EnrollFactor(
 	ctx context.Context,
 	opts EnrollFactorOpts,
+	reqOpts ...common.RequestOption,
 ) (models.Factor, error) {
-	return DefaultClient.EnrollFactor(ctx, opts)
+	return DefaultClient.EnrollFactor(ctx, opts, reqOpts...)
 }
 
 // ChallengeFactor Initiates the authentication process for the newly created MFA authorization factor.
 func ChallengeFactor(
 	ctx context.Context,
 	opts ChallengeFactorOpts,
+	reqOpts ...common.RequestOption,
 ) (models.Challenge, error) {
-	return DefaultClient.ChallengeFactor(ctx, opts)
+	return DefaultClient.ChallengeFactor(ctx, opts, reqOpts...)
 }
 
 // VerifyChallenge verifies the one time password provided by the end-user.
 func VerifyChallenge(
 	ctx context.Context,
 	opts VerifyChallengeOpts,
+	reqOpts ...common.RequestOption,
 ) (VerifyChallengeResponse, error) {
-	return DefaultClient.VerifyChallenge(ctx, opts)
+	return DefaultClient.VerifyChallenge(ctx, opts, reqOpts...)
 }
 
 // Deprecated: Use VerifyChallenge instead
 func VerifyFactor(
 	ctx context.Context,
 	opts VerifyChallengeOpts,
+	reqOpts ...common.RequestOption,
 ) (interface{}, error) {
-	return DefaultClient.VerifyFactor(ctx, opts)
+	return DefaultClient.VerifyFactor(ctx, opts, reqOpts...)
 }
 
 // DeleteFactor deletes a factor by ID.
 func DeleteFactor(
 	ctx context.Context,
 	opts DeleteFactorOpts,
+	reqOpts ...common.RequestOption,
 ) error {
-	return DefaultClient.DeleteFactor(ctx, opts)
+	return DefaultClient.DeleteFactor(ctx, opts, reqOpts...)
 }
 
 // GetFactor gets a factor by ID.
 func GetFactor(
 	ctx context.Context,
 	opts GetFactorOpts,
+	reqOpts ...common.RequestOption,
 ) (models.Factor, error) {
-	return DefaultClient.GetFactor(ctx, opts)
+	return DefaultClient.GetFactor(ctx, opts, reqOpts...)
 }
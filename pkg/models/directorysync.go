@@ -197,3 +197,16 @@ type Directory struct {
 	// The timestamp of when the Directory was updated.
 	UpdatedAt time.Time `json:"updated_at"`
 }
+
+// DirectoryGroupMembership describes a Directory User added to or removed
+// from a Directory Group.
+type DirectoryGroupMembership struct {
+	// The identifier of the Directory.
+	DirectoryID string `json:"directory_id"`
+
+	// The Directory User.
+	User DirectoryUser `json:"user"`
+
+	// The Directory Group.
+	Group DirectoryGroup `json:"group"`
+}
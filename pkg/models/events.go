@@ -2,13 +2,16 @@ package models
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
 	"time"
 )
 
 const (
 	// Connection Events
 	EventConnectionActivated   = "connection.activated"
-	EventConnectionDeactivated = "connection.deactived"
+	EventConnectionDeactivated = "connection.deactivated"
 	EventConnectionDeleted     = "connection.deleted"
 	// Directory Events
 	EventDirectoryActivated = "dsync.activated"
@@ -22,7 +25,12 @@ const (
 	EventDirectoryGroupUpdated     = "dsync.group.updated"
 	EventDirectoryGroupDeleted     = "dsync.group.deleted"
 	EventDirectoryGroupUserAdded   = "dsync.group.user_added"
-	EventDirectroyGroupUserRemoved = "dsync.group.user_removed"
+	EventDirectoryGroupUserRemoved = "dsync.group.user_removed"
+	EventDirectroyGroupUserRemoved = EventDirectoryGroupUserRemoved // Deprecated: use EventDirectoryGroupUserRemoved instead
+	// Organization Events
+	EventOrganizationCreated = "organization.created"
+	EventOrganizationUpdated = "organization.updated"
+	EventOrganizationDeleted = "organization.deleted"
 	// User Management Events
 	EventUserCreated                   = "user.created"
 	EventUserUpdated                   = "user.updated"
@@ -53,3 +61,180 @@ type Event struct {
 	// The Event's created at date.
 	CreatedAt time.Time `json:"created_at"`
 }
+
+// Errors returned when decoding the data of an Event.
+var (
+	ErrUnknownEvent   = errors.New("unknown event type")
+	ErrWrongEventType = errors.New("event data has another type")
+)
+
+// eventDataTypes maps the Events to the type of their data.
+var eventDataTypes = map[string]reflect.Type{
+	EventConnectionActivated:           reflect.TypeOf(Connection{}),
+	EventConnectionDeactivated:         reflect.TypeOf(Connection{}),
+	EventConnectionDeleted:             reflect.TypeOf(Connection{}),
+	EventDirectoryActivated:            reflect.TypeOf(Directory{}),
+	EventDirectoryDeleted:              reflect.TypeOf(Directory{}),
+	EventDirectoryUserCreated:          reflect.TypeOf(DirectoryUser{}),
+	EventDirectoryUserUpdated:          reflect.TypeOf(DirectoryUser{}),
+	EventDirectoryUserDeleted:          reflect.TypeOf(DirectoryUser{}),
+	EventDirectoryGroupCreated:         reflect.TypeOf(DirectoryGroup{}),
+	EventDirectoryGroupUpdated:         reflect.TypeOf(DirectoryGroup{}),
+	EventDirectoryGroupDeleted:         reflect.TypeOf(DirectoryGroup{}),
+	EventDirectoryGroupUserAdded:       reflect.TypeOf(DirectoryGroupMembership{}),
+	EventDirectoryGroupUserRemoved:     reflect.TypeOf(DirectoryGroupMembership{}),
+	EventOrganizationCreated:           reflect.TypeOf(Organization{}),
+	EventOrganizationUpdated:           reflect.TypeOf(Organization{}),
+	EventOrganizationDeleted:           reflect.TypeOf(Organization{}),
+	EventUserCreated:                   reflect.TypeOf(User{}),
+	EventUserUpdated:                   reflect.TypeOf(User{}),
+	EventUserDeleted:                   reflect.TypeOf(User{}),
+	EventOrganizationMembershipAdded:   reflect.TypeOf(OrganizationMembership{}),
+	EventOrganizationMembershipCreated: reflect.TypeOf(OrganizationMembership{}),
+	EventOrganizationMembershipDeleted: reflect.TypeOf(OrganizationMembership{}),
+	EventOrganizationMembershipUpdated: reflect.TypeOf(OrganizationMembership{}),
+	EventOrganizationMembershipRemoved: reflect.TypeOf(OrganizationMembership{}),
+	EventSessionCreated:                reflect.TypeOf(UserSession{}),
+	EventEmailVerificationCreated:      reflect.TypeOf(EmailVerification{}),
+	EventInvitationCreated:             reflect.TypeOf(Invitation{}),
+	EventMagicAuthCreated:              reflect.TypeOf(MagicAuth{}),
+	EventPasswordResetCreated:          reflect.TypeOf(PasswordReset{}),
+}
+
+// Decode decodes the data of the Event into the model it is about, e.g. a
+// DirectoryUser for a dsync.user.created Event. The model is returned as a
+// value.
+//
+// It returns an error wrapping ErrUnknownEvent when the type of the Event has
+// no model.
+func (e Event) Decode() (interface{}, error) {
+	typ, err := e.dataType()
+	if err != nil {
+		return nil, err
+	}
+
+	v := reflect.New(typ)
+	if err := json.Unmarshal(e.Data, v.Interface()); err != nil {
+		return nil, err
+	}
+	return v.Elem().Interface(), nil
+}
+
+func (e Event) dataType() (reflect.Type, error) {
+	typ, ok := eventDataTypes[e.Event]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownEvent, e.Event)
+	}
+	return typ, nil
+}
+
+// decodeAs decodes the data of the Event into v, which must point to the
+// model of the Event.
+func (e Event) decodeAs(v interface{}) error {
+	typ, err := e.dataType()
+	if err != nil {
+		return err
+	}
+	if want := reflect.TypeOf(v).Elem(); typ != want {
+		return fmt.Errorf("%w: %s data is %s, not %s", ErrWrongEventType, e.Event, typ, want)
+	}
+	return json.Unmarshal(e.Data, v)
+}
+
+// AsConnection decodes the data of the connection.* Events. The accessors
+// return an error wrapping ErrWrongEventType when the Event is about another
+// model, and ErrUnknownEvent when its type has no model.
+func (e Event) AsConnection() (Connection, error) {
+	var v Connection
+	err := e.decodeAs(&v)
+	return v, err
+}
+
+// AsDirectory decodes the data of the dsync.activated and dsync.deleted
+// Events.
+func (e Event) AsDirectory() (Directory, error) {
+	var v Directory
+	err := e.decodeAs(&v)
+	return v, err
+}
+
+// AsDirectoryUser decodes the data of the dsync.user.* Events.
+func (e Event) AsDirectoryUser() (DirectoryUser, error) {
+	var v DirectoryUser
+	err := e.decodeAs(&v)
+	return v, err
+}
+
+// AsDirectoryGroup decodes the data of the dsync.group.created,
+// dsync.group.updated and dsync.group.deleted Events.
+func (e Event) AsDirectoryGroup() (DirectoryGroup, error) {
+	var v DirectoryGroup
+	err := e.decodeAs(&v)
+	return v, err
+}
+
+// AsDirectoryGroupMembership decodes the data of the dsync.group.user_added
+// and dsync.group.user_removed Events.
+func (e Event) AsDirectoryGroupMembership() (DirectoryGroupMembership, error) {
+	var v DirectoryGroupMembership
+	err := e.decodeAs(&v)
+	return v, err
+}
+
+// AsOrganization decodes the data of the organization.* Events.
+func (e Event) AsOrganization() (Organization, error) {
+	var v Organization
+	err := e.decodeAs(&v)
+	return v, err
+}
+
+// AsUser decodes the data of the user.* Events.
+func (e Event) AsUser() (User, error) {
+	var v User
+	err := e.decodeAs(&v)
+	return v, err
+}
+
+// AsOrganizationMembership decodes the data of the organization_membership.*
+// Events.
+func (e Event) AsOrganizationMembership() (OrganizationMembership, error) {
+	var v OrganizationMembership
+	err := e.decodeAs(&v)
+	return v, err
+}
+
+// AsUserSession decodes the data of the session.created Events.
+func (e Event) AsUserSession() (UserSession, error) {
+	var v UserSession
+	err := e.decodeAs(&v)
+	return v, err
+}
+
+// AsEmailVerification decodes the data of the email_verification.created
+// Events.
+func (e Event) AsEmailVerification() (EmailVerification, error) {
+	var v EmailVerification
+	err := e.decodeAs(&v)
+	return v, err
+}
+
+// AsInvitation decodes the data of the invitation.created Events.
+func (e Event) AsInvitation() (Invitation, error) {
+	var v Invitation
+	err := e.decodeAs(&v)
+	return v, err
+}
+
+// AsMagicAuth decodes the data of the magic_auth.created Events.
+func (e Event) AsMagicAuth() (MagicAuth, error) {
+	var v MagicAuth
+	err := e.decodeAs(&v)
+	return v, err
+}
+
+// AsPasswordReset decodes the data of the password_reset.created Events.
+func (e Event) AsPasswordReset() (PasswordReset, error) {
+	var v PasswordReset
+	err := e.decodeAs(&v)
+	return v, err
+}
@@ -0,0 +1,55 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventDecode(t *testing.T) {
+	event := Event{
+		ID:    "event_01",
+		Event: EventDirectoryUserCreated,
+		Data:  json.RawMessage(`{"id": "directory_user_01", "state": "active"}`),
+	}
+
+	data, err := event.Decode()
+	require.NoError(t, err)
+	require.Equal(t, DirectoryUser{ID: "directory_user_01", State: DirectoryUserStateActive}, data)
+
+	user, err := event.AsDirectoryUser()
+	require.NoError(t, err)
+	require.Equal(t, "directory_user_01", user.ID)
+
+	_, err = event.AsOrganizationMembership()
+	require.True(t, errors.Is(err, ErrWrongEventType), "unexpected error: %v", err)
+	require.EqualError(t, err, "event data has another type: dsync.user.created data is models.DirectoryUser, not models.OrganizationMembership")
+}
+
+func TestEventDecodeUnknownEvent(t *testing.T) {
+	event := Event{
+		ID:    "event_01",
+		Event: "unknown.event",
+		Data:  json.RawMessage(`{}`),
+	}
+
+	_, err := event.Decode()
+	require.True(t, errors.Is(err, ErrUnknownEvent), "unexpected error: %v", err)
+	require.EqualError(t, err, `unknown event type: "unknown.event"`)
+
+	_, err = event.AsUser()
+	require.True(t, errors.Is(err, ErrUnknownEvent), "unexpected error: %v", err)
+}
+
+func TestEventDecodeEveryEvent(t *testing.T) {
+	for name := range eventDataTypes {
+		event := Event{Event: name, Data: json.RawMessage(`{"id": "id_01"}`)}
+
+		data, err := event.Decode()
+		require.NoError(t, err, name)
+		require.Equal(t, eventDataTypes[name], reflect.TypeOf(data), name)
+	}
+}
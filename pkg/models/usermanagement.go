@@ -133,3 +133,27 @@ type Identity struct {
 	// The type of OAuth provider for the identity.
 	Provider string `json:"provider"`
 }
+
+// UserSession contains data about a session of a User.
+type UserSession struct {
+	// The Session's unique identifier.
+	ID string `json:"id"`
+
+	// The ID of the User.
+	UserID string `json:"user_id"`
+
+	// The ID of the Organization the User signed in to, if any.
+	OrganizationID string `json:"organization_id,omitempty"`
+
+	// The IP address the User signed in from.
+	IPAddress string `json:"ip_address"`
+
+	// The user agent the User signed in with.
+	UserAgent string `json:"user_agent"`
+
+	// The timestamp of when the Session was created.
+	CreatedAt time.Time `json:"created_at"`
+
+	// The timestamp of when the Session was updated.
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -11,6 +11,30 @@ const (
 	WebhookEventNameConnectionDeactivated    WebhookEventName = "connection.deactivated"
 	WebhookEventNameConnectionDeleted        WebhookEventName = "connection.deleted"
 	WebhookEventNameInvitationCreated        WebhookEventName = "invitation.created"
+
+	WebhookEventNameDirectoryActivated            WebhookEventName = "dsync.activated"
+	WebhookEventNameDirectoryDeleted              WebhookEventName = "dsync.deleted"
+	WebhookEventNameDirectoryUserCreated          WebhookEventName = "dsync.user.created"
+	WebhookEventNameDirectoryUserUpdated          WebhookEventName = "dsync.user.updated"
+	WebhookEventNameDirectoryUserDeleted          WebhookEventName = "dsync.user.deleted"
+	WebhookEventNameDirectoryGroupCreated         WebhookEventName = "dsync.group.created"
+	WebhookEventNameDirectoryGroupUpdated         WebhookEventName = "dsync.group.updated"
+	WebhookEventNameDirectoryGroupDeleted         WebhookEventName = "dsync.group.deleted"
+	WebhookEventNameDirectoryGroupUserAdded       WebhookEventName = "dsync.group.user_added"
+	WebhookEventNameDirectoryGroupUserRemoved     WebhookEventName = "dsync.group.user_removed"
+	WebhookEventNameOrganizationCreated           WebhookEventName = "organization.created"
+	WebhookEventNameOrganizationUpdated           WebhookEventName = "organization.updated"
+	WebhookEventNameOrganizationDeleted           WebhookEventName = "organization.deleted"
+	WebhookEventNameUserCreated                   WebhookEventName = "user.created"
+	WebhookEventNameUserUpdated                   WebhookEventName = "user.updated"
+	WebhookEventNameUserDeleted                   WebhookEventName = "user.deleted"
+	WebhookEventNameOrganizationMembershipCreated WebhookEventName = "organization_membership.created"
+	WebhookEventNameOrganizationMembershipUpdated WebhookEventName = "organization_membership.updated"
+	WebhookEventNameOrganizationMembershipDeleted WebhookEventName = "organization_membership.deleted"
+	WebhookEventNameOrganizationMembershipAdded   WebhookEventName = "organization_membership.added"   // Deprecated: use WebhookEventNameOrganizationMembershipCreated instead
+	WebhookEventNameOrganizationMembershipRemoved WebhookEventName = "organization_membership.removed" // Deprecated: use WebhookEventNameOrganizationMembershipDeleted instead
+	WebhookEventNameSessionCreated                WebhookEventName = "session.created"
+	WebhookEventNameMagicAuthCreated              WebhookEventName = "magic_auth.created"
 )
 
 type WebhookEvent struct {
@@ -81,3 +105,65 @@ type WebhookEventInvitationCreated struct {
 		UpdatedAt      time.Time       `json:"updated_at"`
 	}
 }
+
+// WebhookEventDirectory is the payload of the dsync.activated and
+// dsync.deleted events.
+type WebhookEventDirectory struct {
+	WebhookEvent
+	Data Directory `json:"data"`
+}
+
+// WebhookEventDirectoryUser is the payload of the dsync.user.created,
+// dsync.user.updated and dsync.user.deleted events.
+type WebhookEventDirectoryUser struct {
+	WebhookEvent
+	Data DirectoryUser `json:"data"`
+}
+
+// WebhookEventDirectoryGroup is the payload of the dsync.group.created,
+// dsync.group.updated and dsync.group.deleted events.
+type WebhookEventDirectoryGroup struct {
+	WebhookEvent
+	Data DirectoryGroup `json:"data"`
+}
+
+// WebhookEventDirectoryGroupMembership is the payload of the
+// dsync.group.user_added and dsync.group.user_removed events.
+type WebhookEventDirectoryGroupMembership struct {
+	WebhookEvent
+	Data DirectoryGroupMembership `json:"data"`
+}
+
+// WebhookEventOrganization is the payload of the organization.created,
+// organization.updated and organization.deleted events.
+type WebhookEventOrganization struct {
+	WebhookEvent
+	Data Organization `json:"data"`
+}
+
+// WebhookEventUser is the payload of the user.created, user.updated and
+// user.deleted events.
+type WebhookEventUser struct {
+	WebhookEvent
+	Data User `json:"data"`
+}
+
+// WebhookEventOrganizationMembership is the payload of the
+// organization_membership.* events.
+type WebhookEventOrganizationMembership struct {
+	WebhookEvent
+	Data OrganizationMembership `json:"data"`
+}
+
+// WebhookEventSessionCreated is the payload of the session.created event.
+type WebhookEventSessionCreated struct {
+	WebhookEvent
+	Data UserSession `json:"data"`
+}
+
+// WebhookEventMagicAuthCreated is the payload of the magic_auth.created
+// event.
+type WebhookEventMagicAuthCreated struct {
+	WebhookEvent
+	Data MagicAuth `json:"data"`
+}
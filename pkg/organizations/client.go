@@ -38,6 +38,13 @@ type Client struct {
 	// Defaults to http.Client.
 	HTTPClient *http.Client
 
+	// The policy used to retry requests that fail with a transient error.
+	// Defaults to common.DefaultRetryPolicy.
+	RetryPolicy *common.RetryPolicy
+
+	// The middlewares that requests are sent through, outermost first.
+	Middlewares []common.Middleware
+
 	// The endpoint to WorkOS API. Defaults to https://api.workos.com.
 	Endpoint string
 
@@ -61,6 +68,15 @@ func (c *Client) init() {
 	}
 }
 
+func (c *Client) do(req *http.Request, reqOpts ...common.RequestOption) (*http.Response, error) {
+	return workos.Transport{
+		HTTPClient:  c.HTTPClient,
+		APIKey:      c.APIKey,
+		RetryPolicy: c.RetryPolicy,
+		Middlewares: c.Middlewares,
+	}.Do(req, reqOpts...)
+}
+
 // GetOrganizationOpts contains the options to request details for an Organization.
 type GetOrganizationOpts struct {
 	// Organization unique identifier.
@@ -114,8 +130,9 @@ type CreateOrganizationOpts struct {
 	// Domains of the Organization.
 	DomainData []models.OrganizationDomainData `json:"domain_data"`
 
-	// Optional unique identifier to ensure idempotency
-	IdempotencyKey string `json:"idempotency_iey,omitempty"`
+	// Optional unique identifier to ensure idempotency. It is sent in the
+	// Idempotency-Key header. A key is generated when it is empty.
+	IdempotencyKey string `json:"-"`
 }
 
 // UpdateOrganizationOpts contains the options to update an Organization.
@@ -145,6 +162,7 @@ type UpdateOrganizationOpts struct {
 func (c *Client) GetOrganization(
 	ctx context.Context,
 	opts GetOrganizationOpts,
+	reqOpts ...common.RequestOption,
 ) (models.Organization, error) {
 	c.once.Do(c.init)
 
@@ -163,11 +181,8 @@ func (c *Client) GetOrganization(
 	}
 
 	req = req.WithContext(ctx)
-	req.Header.Set("Authorization", "Bearer "+c.APIKey)
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
 
-	res, err := c.HTTPClient.Do(req)
+	res, err := c.do(req, reqOpts...)
 	if err != nil {
 		return models.Organization{}, err
 	}
@@ -187,6 +202,7 @@ func (c *Client) GetOrganization(
 func (c *Client) ListOrganizations(
 	ctx context.Context,
 	opts ListOrganizationsOpts,
+	reqOpts ...common.RequestOption,
 ) (ListOrganizationsResponse, error) {
 	c.once.Do(c.init)
 
@@ -201,9 +217,6 @@ func (c *Client) ListOrganizations(
 	}
 
 	req = req.WithContext(ctx)
-	req.Header.Set("Authorization", "Bearer "+c.APIKey)
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
 
 	if opts.Limit == 0 {
 		opts.Limit = ResponseLimit
@@ -220,7 +233,7 @@ func (c *Client) ListOrganizations(
 
 	req.URL.RawQuery = q.Encode()
 
-	res, err := c.HTTPClient.Do(req)
+	res, err := c.do(req, reqOpts...)
 	if err != nil {
 		return ListOrganizationsResponse{}, err
 	}
@@ -237,7 +250,7 @@ func (c *Client) ListOrganizations(
 }
 
 // CreateOrganization creates an Organization.
-func (c *Client) CreateOrganization(ctx context.Context, opts CreateOrganizationOpts) (models.Organization, error) {
+func (c *Client) CreateOrganization(ctx context.Context, opts CreateOrganizationOpts, reqOpts ...common.RequestOption) (models.Organization, error) {
 	c.once.Do(c.init)
 
 	data, err := c.JSONEncode(opts)
@@ -251,12 +264,11 @@ func (c *Client) CreateOrganization(ctx context.Context, opts CreateOrganization
 		return models.Organization{}, err
 	}
 	req = req.WithContext(ctx)
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+c.APIKey)
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
-	req.Header.Set("Idempotency-Key", opts.IdempotencyKey)
+	if opts.IdempotencyKey != "" {
+		req.Header.Set("Idempotency-Key", opts.IdempotencyKey)
+	}
 
-	res, err := c.HTTPClient.Do(req)
+	res, err := c.do(req, reqOpts...)
 	if err != nil {
 		return models.Organization{}, err
 	}
@@ -273,7 +285,7 @@ func (c *Client) CreateOrganization(ctx context.Context, opts CreateOrganization
 }
 
 // UpdateOrganization updates an Organization.
-func (c *Client) UpdateOrganization(ctx context.Context, opts UpdateOrganizationOpts) (models.Organization, error) {
+func (c *Client) UpdateOrganization(ctx context.Context, opts UpdateOrganizationOpts, reqOpts ...common.RequestOption) (models.Organization, error) {
 	c.once.Do(c.init)
 
 	// UpdateOrganizationChangeOpts contains the options to update an Organization minus the org ID
@@ -307,11 +319,8 @@ func (c *Client) UpdateOrganization(ctx context.Context, opts UpdateOrganization
 		return models.Organization{}, err
 	}
 	req = req.WithContext(ctx)
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+c.APIKey)
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
 
-	res, err := c.HTTPClient.Do(req)
+	res, err := c.do(req, reqOpts...)
 	if err != nil {
 		return models.Organization{}, err
 	}
@@ -337,11 +346,12 @@ type DeleteOrganizationOpts struct {
 func (c *Client) DeleteOrganization(
 	ctx context.Context,
 	opts DeleteOrganizationOpts,
+	reqOpts ...common.RequestOption,
 ) error {
 	c.once.Do(c.init)
 
 	endpoint := fmt.Sprintf(
-		"%s/Organizations/%s",
+		"%s/organizations/%s",
 		c.Endpoint,
 		opts.Organization,
 	)
@@ -355,11 +365,8 @@ func (c *Client) DeleteOrganization(
 	}
 
 	req = req.WithContext(ctx)
-	req.Header.Set("Authorization", "Bearer "+c.APIKey)
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
 
-	res, err := c.HTTPClient.Do(req)
+	res, err := c.do(req, reqOpts...)
 	if err != nil {
 		return err
 	}
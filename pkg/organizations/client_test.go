@@ -308,6 +308,40 @@ func TestCreateOrganization(t *testing.T) {
 	}
 }
 
+func TestCreateOrganizationIdempotencyKey(t *testing.T) {
+	var header http.Header
+	var body map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header
+		json.NewDecoder(r.Body).Decode(&body)
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":"organization_id"}`))
+	}))
+	defer server.Close()
+
+	client := &Client{
+		APIKey:     "test",
+		Endpoint:   server.URL,
+		HTTPClient: server.Client(),
+	}
+
+	_, err := client.CreateOrganization(context.Background(), CreateOrganizationOpts{
+		Name:           "Foo Corp",
+		IdempotencyKey: "the-idempotency-key",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "the-idempotency-key", header.Get("Idempotency-Key"))
+	require.NotContains(t, body, "idempotency_iey")
+
+	_, err = client.CreateOrganization(
+		context.Background(),
+		CreateOrganizationOpts{Name: "Foo Corp"},
+		common.WithIdempotencyKey("another-key"),
+	)
+	require.NoError(t, err)
+	require.Equal(t, "another-key", header.Get("Idempotency-Key"))
+}
+
 func createOrganizationTestHandler(w http.ResponseWriter, r *http.Request) {
 	auth := r.Header.Get("Authorization")
 	if auth != "Bearer test" {
@@ -324,7 +358,7 @@ func createOrganizationTestHandler(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	if opts.IdempotencyKey == "duplicate" {
+	if r.Header.Get("Idempotency-Key") == "duplicate" {
 		for _, domain := range opts.Domains {
 			if domain != "foo-corp.com" {
 				http.Error(w, "duplicate idempotency key", http.StatusConflict)
@@ -0,0 +1,62 @@
+package organizations
+
+import (
+	"context"
+
+	"github.com/omi-lab/workos-go/v4/internal/workos"
+	"github.com/omi-lab/workos-go/v4/pkg/common"
+	"github.com/omi-lab/workos-go/v4/pkg/models"
+)
+
+// OrganizationIterator iterates over Organizations, fetching pages as
+// needed.
+//
+//	it := client.IterateOrganizations(ctx, opts)
+//	for it.Next() {
+//	    v := it.Value()
+//	}
+//	if err := it.Err(); err != nil {
+//	    // Handle error.
+//	}
+type OrganizationIterator struct {
+	pager *workos.Pager
+}
+
+// Next advances the iterator to the next Organization. It returns false when
+// there are no more Organizations, when a request fails or when the context
+// is done.
+func (it *OrganizationIterator) Next() bool {
+	return it.pager.Next()
+}
+
+// Value returns the current Organization.
+func (it *OrganizationIterator) Value() models.Organization {
+	return it.pager.Value().(models.Organization)
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *OrganizationIterator) Err() error {
+	return it.pager.Err()
+}
+
+// CollectAll returns the remaining Organizations, up to max when max is
+// positive.
+func (it *OrganizationIterator) CollectAll(max int) ([]models.Organization, error) {
+	var all []models.Organization
+	err := it.pager.CollectAll(max, &all)
+	return all, err
+}
+
+// IterateOrganizations returns an iterator over the Organizations matching
+// the given options.
+//
+// The iterator follows the After cursor of each page, or the Before cursor
+// when opts only sets Before.
+func (c *Client) IterateOrganizations(ctx context.Context, opts ListOrganizationsOpts, reqOpts ...common.RequestOption) *OrganizationIterator {
+	return &OrganizationIterator{pager: workos.NewPager(ctx, opts.Before, opts.After, func(ctx context.Context, before, after string) (interface{}, common.ListMetadata, error) {
+		opts.Before, opts.After = before, after
+
+		res, err := c.ListOrganizations(ctx, opts, reqOpts...)
+		return res.Data, res.ListMetadata, err
+	})}
+}
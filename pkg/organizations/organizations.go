@@ -4,6 +4,7 @@ package organizations
 import (
 	"context"
 
+	"github.com/omi-lab/workos-go/v4/pkg/common"
 	"github.com/omi-lab/workos-go/v4/pkg/models"
 )
 
@@ -23,38 +24,53 @@ func SetAPIKey(apiKey string) {
 func GetOrganization(
 	ctx context.Context,
 	opts GetOrganizationOpts,
+	reqOpts ...common.RequestOption,
 ) (models.Organization, error) {
-	return DefaultClient.GetOrganization(ctx, opts)
+	return DefaultClient.GetOrganization(ctx, opts, reqOpts...)
 }
 
 // ListOrganizations gets a list of Organizations.
 func ListOrganizations(
 	ctx context.Context,
 	opts ListOrganizationsOpts,
+	reqOpts ...common.RequestOption,
 ) (ListOrganizationsResponse, error) {
-	return DefaultClient.ListOrganizations(ctx, opts)
+	return DefaultClient.ListOrganizations(ctx, opts, reqOpts...)
 }
 
 // CreateOrganization creates an Organization.
 func CreateOrganization(
 	ctx context.Context,
 	opts CreateOrganizationOpts,
+	reqOpts ...common.RequestOption,
 ) (models.Organization, error) {
-	return DefaultClient.CreateOrganization(ctx, opts)
+	return DefaultClient.CreateOrganization(ctx, opts, reqOpts...)
 }
 
 // UpdateOrganization creates an Organization.
 func UpdateOrganization(
 	ctx context.Context,
 	opts UpdateOrganizationOpts,
+	reqOpts ...common.RequestOption,
 ) (models.Organization, error) {
-	return DefaultClient.UpdateOrganization(ctx, opts)
+	return DefaultClient.UpdateOrganization(ctx, opts, reqOpts...)
 }
 
 // DeleteOrganization deletes an Organization.
 func DeleteOrganization(
 	ctx context.Context,
 	opts DeleteOrganizationOpts,
+	reqOpts ...common.RequestOption,
 ) error {
-	return DefaultClient.DeleteOrganization(ctx, opts)
+	return DefaultClient.DeleteOrganization(ctx, opts, reqOpts...)
+}
+
+// IterateOrganizations returns an iterator over the Organizations matching the given
+// options.
+func IterateOrganizations(
+	ctx context.Context,
+	opts ListOrganizationsOpts,
+	reqOpts ...common.RequestOption,
+) *OrganizationIterator {
+	return DefaultClient.IterateOrganizations(ctx, opts, reqOpts...)
 }
@@ -13,6 +13,7 @@ import (
 	"github.com/omi-lab/workos-go/v4/pkg/workos_errors"
 
 	"github.com/omi-lab/workos-go/v4/internal/workos"
+	"github.com/omi-lab/workos-go/v4/pkg/common"
 )
 
 // Client represents a client that performs Passwordless requests to the WorkOS API.
@@ -28,6 +29,13 @@ type Client struct {
 	// Defaults to http.Client.
 	HTTPClient *http.Client
 
+	// The policy used to retry requests that fail with a transient error.
+	// Defaults to common.DefaultRetryPolicy.
+	RetryPolicy *common.RetryPolicy
+
+	// The middlewares that requests are sent through, outermost first.
+	Middlewares []common.Middleware
+
 	// The endpoint to WorkOS API.
 	//
 	// Defaults to https://api.workos.com.
@@ -53,6 +61,15 @@ func (c *Client) init() {
 	}
 }
 
+func (c *Client) do(req *http.Request, reqOpts ...common.RequestOption) (*http.Response, error) {
+	return workos.Transport{
+		HTTPClient:  c.HTTPClient,
+		APIKey:      c.APIKey,
+		RetryPolicy: c.RetryPolicy,
+		Middlewares: c.Middlewares,
+	}.Do(req, reqOpts...)
+}
+
 // CreateSessionOpts contains the options to create a Passowordless Session.
 type CreateSessionOpts struct {
 	// The email of the user to authenticate.
@@ -82,7 +99,7 @@ type CreateSessionOpts struct {
 }
 
 // CreateSession creates a a PasswordlessSession.
-func (c *Client) CreateSession(ctx context.Context, opts CreateSessionOpts) (models.PasswordlessSession, error) {
+func (c *Client) CreateSession(ctx context.Context, opts CreateSessionOpts, reqOpts ...common.RequestOption) (models.PasswordlessSession, error) {
 	c.once.Do(c.init)
 
 	data, err := c.JSONEncode(opts)
@@ -96,11 +113,8 @@ func (c *Client) CreateSession(ctx context.Context, opts CreateSessionOpts) (mod
 		return models.PasswordlessSession{}, err
 	}
 	req = req.WithContext(ctx)
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+c.APIKey)
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
 
-	res, err := c.HTTPClient.Do(req)
+	res, err := c.do(req, reqOpts...)
 	if err != nil {
 		return models.PasswordlessSession{}, err
 	}
@@ -126,6 +140,7 @@ type SendSessionOpts struct {
 func (c *Client) SendSession(
 	ctx context.Context,
 	opts SendSessionOpts,
+	reqOpts ...common.RequestOption,
 ) error {
 	c.once.Do(c.init)
 
@@ -144,11 +159,8 @@ func (c *Client) SendSession(
 		return err
 	}
 	req = req.WithContext(ctx)
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+c.APIKey)
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
 
-	res, err := c.HTTPClient.Do(req)
+	res, err := c.do(req, reqOpts...)
 	if err != nil {
 		return err
 	}
@@ -4,6 +4,7 @@ package passwordless
 import (
 	"context"
 
+	"github.com/omi-lab/workos-go/v4/pkg/common"
 	"github.com/omi-lab/workos-go/v4/pkg/models"
 )
 
@@ -23,14 +24,16 @@ func SetAPIKey(apiKey string) {
 func CreateSession(
 	ctx context.Context,
 	opts CreateSessionOpts,
+	reqOpts ...common.RequestOption,
 ) (models.PasswordlessSession, error) {
-	return DefaultClient.CreateSession(ctx, opts)
+	return DefaultClient.CreateSession(ctx, opts, reqOpts...)
 }
 
 // SendSession sends a Passwordless Session via email
 func SendSession(
 	ctx context.Context,
 	opts SendSessionOpts,
+	reqOpts ...common.RequestOption,
 ) error {
-	return DefaultClient.SendSession(ctx, opts)
+	return DefaultClient.SendSession(ctx, opts, reqOpts...)
 }
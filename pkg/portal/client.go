@@ -13,6 +13,7 @@ import (
 	"github.com/omi-lab/workos-go/v4/pkg/workos_errors"
 
 	"github.com/omi-lab/workos-go/v4/internal/workos"
+	"github.com/omi-lab/workos-go/v4/pkg/common"
 )
 
 // ResponseLimit is the default number of records to limit a response to.
@@ -36,6 +37,13 @@ type Client struct {
 	// Defaults to http.Client.
 	HTTPClient *http.Client
 
+	// The policy used to retry requests that fail with a transient error.
+	// Defaults to common.DefaultRetryPolicy.
+	RetryPolicy *common.RetryPolicy
+
+	// The middlewares that requests are sent through, outermost first.
+	Middlewares []common.Middleware
+
 	// The endpoint to WorkOS API. Defaults to https://api.workos.com.
 	Endpoint string
 
@@ -59,6 +67,15 @@ func (c *Client) init() {
 	}
 }
 
+func (c *Client) do(req *http.Request, reqOpts ...common.RequestOption) (*http.Response, error) {
+	return workos.Transport{
+		HTTPClient:  c.HTTPClient,
+		APIKey:      c.APIKey,
+		RetryPolicy: c.RetryPolicy,
+		Middlewares: c.Middlewares,
+	}.Do(req, reqOpts...)
+}
+
 // GenerateLinkOpts contains the options to request Organizations.
 type GenerateLinkOpts struct {
 	// Intent of the Admin Portal
@@ -84,6 +101,7 @@ type generateLinkResponse struct {
 func (c *Client) GenerateLink(
 	ctx context.Context,
 	opts GenerateLinkOpts,
+	reqOpts ...common.RequestOption,
 ) (string, error) {
 	c.once.Do(c.init)
 
@@ -98,11 +116,8 @@ func (c *Client) GenerateLink(
 		return "", err
 	}
 	req = req.WithContext(ctx)
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+c.APIKey)
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
 
-	res, err := c.HTTPClient.Do(req)
+	res, err := c.do(req, reqOpts...)
 	if err != nil {
 		return "", err
 	}
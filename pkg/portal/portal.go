@@ -3,6 +3,8 @@ package portal
 
 import (
 	"context"
+
+	"github.com/omi-lab/workos-go/v4/pkg/common"
 )
 
 // DefaultClient is the client used by SetAPIKey and Admin Portal functions.
@@ -21,6 +23,7 @@ func SetAPIKey(apiKey string) {
 func GenerateLink(
 	ctx context.Context,
 	opts GenerateLinkOpts,
+	reqOpts ...common.RequestOption,
 ) (string, error) {
-	return DefaultClient.GenerateLink(ctx, opts)
+	return DefaultClient.GenerateLink(ctx, opts, reqOpts...)
 }
@@ -54,6 +54,13 @@ type Client struct {
 	// Defaults to http.Client.
 	HTTPClient *http.Client
 
+	// The policy used to retry requests that fail with a transient error.
+	// Defaults to common.DefaultRetryPolicy.
+	RetryPolicy *common.RetryPolicy
+
+	// The middlewares that requests are sent through, outermost first.
+	Middlewares []common.Middleware
+
 	// The function used to encode in JSON. Defaults to json.Marshal.
 	JSONEncode func(v interface{}) ([]byte, error)
 
@@ -75,6 +82,15 @@ func (c *Client) init() {
 	}
 }
 
+func (c *Client) do(req *http.Request, reqOpts ...common.RequestOption) (*http.Response, error) {
+	return workos.Transport{
+		HTTPClient:  c.HTTPClient,
+		APIKey:      c.APIKey,
+		RetryPolicy: c.RetryPolicy,
+		Middlewares: c.Middlewares,
+	}.Do(req, reqOpts...)
+}
+
 // GetLoginHandler returns an http.Handler that redirects client to the appropriate
 // login provider.
 func (c *Client) GetLoginHandler(opts GetAuthorizationURLOpts) http.Handler {
@@ -126,6 +142,19 @@ type GetAuthorizationURLOpts struct {
 	//
 	// OPTIONAL.
 	State string
+
+	// The PKCE code challenge, for clients that can not keep a secret. Its
+	// verifier must then be passed to GetProfileAndToken. See
+	// common.NewPKCE.
+	//
+	// OPTIONAL.
+	CodeChallenge string
+
+	// The method used to derive the CodeChallenge. Defaults to S256 when a
+	// CodeChallenge is set.
+	//
+	// OPTIONAL.
+	CodeChallengeMethod string
 }
 
 // GetAuthorizationURL returns an authorization url generated with the given
@@ -168,6 +197,17 @@ func (c *Client) GetAuthorizationURL(opts GetAuthorizationURLOpts) (*url.URL, er
 		query.Set("state", opts.State)
 	}
 
+	if opts.CodeChallengeMethod != "" && opts.CodeChallenge == "" {
+		return nil, errors.New("incomplete arguments: missing CodeChallenge")
+	}
+	if opts.CodeChallenge != "" {
+		if opts.CodeChallengeMethod == "" {
+			opts.CodeChallengeMethod = common.CodeChallengeMethodS256
+		}
+		query.Set("code_challenge", opts.CodeChallenge)
+		query.Set("code_challenge_method", opts.CodeChallengeMethod)
+	}
+
 	u, err := url.ParseRequestURI(c.Endpoint + "/sso/authorize")
 	if err != nil {
 		return nil, err
@@ -182,6 +222,12 @@ type GetProfileAndTokenOpts struct {
 	// An opaque string provided by the authorization server. It will be
 	// exchanged for an Access Token when the user’s profile is sent.
 	Code string
+
+	// The verifier of the PKCE code challenge sent with the authorization
+	// URL. It is required when the Client has no APIKey.
+	//
+	// OPTIONAL.
+	CodeVerifier string
 }
 
 // Profile contains information about an authenticated user.
@@ -227,14 +273,19 @@ type ProfileAndToken struct {
 
 // GetProfileAndToken returns a profile describing the user that authenticated with
 // WorkOS SSO.
-func (c *Client) GetProfileAndToken(ctx context.Context, opts GetProfileAndTokenOpts) (ProfileAndToken, error) {
+func (c *Client) GetProfileAndToken(ctx context.Context, opts GetProfileAndTokenOpts, reqOpts ...common.RequestOption) (ProfileAndToken, error) {
 	c.once.Do(c.init)
 
 	form := make(url.Values, 5)
 	form.Set("client_id", c.ClientID)
-	form.Set("client_secret", c.APIKey)
+	if c.APIKey != "" {
+		form.Set("client_secret", c.APIKey)
+	}
 	form.Set("grant_type", "authorization_code")
 	form.Set("code", opts.Code)
+	if opts.CodeVerifier != "" {
+		form.Set("code_verifier", opts.CodeVerifier)
+	}
 
 	req, err := http.NewRequest(
 		http.MethodPost,
@@ -245,11 +296,10 @@ func (c *Client) GetProfileAndToken(ctx context.Context, opts GetProfileAndToken
 		return ProfileAndToken{}, err
 	}
 	req = req.WithContext(ctx)
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
 
 	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
 
-	res, err := c.HTTPClient.Do(req)
+	res, err := c.do(req, reqOpts...)
 	if err != nil {
 		return ProfileAndToken{}, err
 	}
@@ -275,7 +325,7 @@ type GetProfileOpts struct {
 
 // GetProfile returns a profile describing the user that authenticated with
 // WorkOS SSO.
-func (c *Client) GetProfile(ctx context.Context, opts GetProfileOpts) (Profile, error) {
+func (c *Client) GetProfile(ctx context.Context, opts GetProfileOpts, reqOpts ...common.RequestOption) (Profile, error) {
 	c.once.Do(c.init)
 
 	req, err := http.NewRequest(
@@ -288,9 +338,8 @@ func (c *Client) GetProfile(ctx context.Context, opts GetProfileOpts) (Profile,
 	}
 	req = req.WithContext(ctx)
 	req.Header.Set("Authorization", "Bearer "+opts.AccessToken)
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
 
-	res, err := c.HTTPClient.Do(req)
+	res, err := c.do(req, reqOpts...)
 	if err != nil {
 		return Profile{}, err
 	}
@@ -317,6 +366,7 @@ type GetConnectionOpts struct {
 func (c *Client) GetConnection(
 	ctx context.Context,
 	opts GetConnectionOpts,
+	reqOpts ...common.RequestOption,
 ) (models.Connection, error) {
 	c.once.Do(c.init)
 
@@ -335,11 +385,8 @@ func (c *Client) GetConnection(
 	}
 
 	req = req.WithContext(ctx)
-	req.Header.Set("Authorization", "Bearer "+c.APIKey)
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
 
-	res, err := c.HTTPClient.Do(req)
+	res, err := c.do(req, reqOpts...)
 	if err != nil {
 		return models.Connection{}, err
 	}
@@ -393,6 +440,7 @@ type ListConnectionsResponse struct {
 func (c *Client) ListConnections(
 	ctx context.Context,
 	opts ListConnectionsOpts,
+	reqOpts ...common.RequestOption,
 ) (ListConnectionsResponse, error) {
 	c.once.Do(c.init)
 
@@ -407,9 +455,6 @@ func (c *Client) ListConnections(
 	}
 
 	req = req.WithContext(ctx)
-	req.Header.Set("Authorization", "Bearer "+c.APIKey)
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
 	if opts.Limit == 0 {
 		opts.Limit = ResponseLimit
 	}
@@ -424,7 +469,7 @@ func (c *Client) ListConnections(
 	}
 
 	req.URL.RawQuery = v.Encode()
-	res, err := c.HTTPClient.Do(req)
+	res, err := c.do(req, reqOpts...)
 	if err != nil {
 		return ListConnectionsResponse{}, err
 	}
@@ -450,6 +495,7 @@ type DeleteConnectionOpts struct {
 func (c *Client) DeleteConnection(
 	ctx context.Context,
 	opts DeleteConnectionOpts,
+	reqOpts ...common.RequestOption,
 ) error {
 	c.once.Do(c.init)
 
@@ -468,11 +514,8 @@ func (c *Client) DeleteConnection(
 	}
 
 	req = req.WithContext(ctx)
-	req.Header.Set("Authorization", "Bearer "+c.APIKey)
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
 
-	res, err := c.HTTPClient.Do(req)
+	res, err := c.do(req, reqOpts...)
 	if err != nil {
 		return err
 	}
@@ -94,6 +94,15 @@ func TestClientAuthorizeURL(t *testing.T) {
 			},
 			expected: "https://api.workos.com/sso/authorize?client_id=client_123&connection=connection_123&login_hint=foo%40workos.com&redirect_uri=https%3A%2F%2Fexample.com%2Fsso%2Fworkos%2Fcallback&response_type=code&state=custom+state",
 		},
+		{
+			scenario: "generate url with a PKCE code challenge",
+			options: GetAuthorizationURLOpts{
+				Connection:    "connection_123",
+				RedirectURI:   "https://example.com/sso/workos/callback",
+				CodeChallenge: "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM",
+			},
+			expected: "https://api.workos.com/sso/authorize?client_id=client_123&code_challenge=E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM&code_challenge_method=S256&connection=connection_123&redirect_uri=https%3A%2F%2Fexample.com%2Fsso%2Fworkos%2Fcallback&response_type=code",
+		},
 	}
 
 	for _, test := range tests {
@@ -165,6 +174,33 @@ func TestClientGetProfileAndToken(t *testing.T) {
 				},
 			},
 		},
+		{
+			scenario: "request without api key with a code verifier returns a profile",
+			client: &Client{
+				ClientID: "client_123",
+			},
+			options: GetProfileAndTokenOpts{
+				Code:         "authorization_code",
+				CodeVerifier: "code_verifier",
+			},
+			expected: Profile{
+				ID:             "profile_123",
+				IdpID:          "123",
+				OrganizationID: "org_123",
+				ConnectionID:   "conn_123",
+				ConnectionType: models.ConnectionTypeOktaSAML,
+				Email:          "foo@test.com",
+				FirstName:      "foo",
+				LastName:       "bar",
+				Groups:         []string{"Admins", "Developers"},
+				RawAttributes: map[string]interface{}{
+					"idp_id":     "123",
+					"email":      "foo@test.com",
+					"first_name": "foo",
+					"last_name":  "bar",
+				},
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -196,7 +232,12 @@ func profileAndTokenTestHandler(w http.ResponseWriter, r *http.Request) {
 
 	r.ParseForm()
 
-	if clientSecret := r.Form.Get("client_secret"); clientSecret != "test" {
+	// Public clients prove they started the authorization request with the
+	// PKCE code verifier instead of a client secret.
+	_, hasSecret := r.Form["client_secret"]
+	public := !hasSecret && r.Form.Get("code_verifier") == "code_verifier"
+
+	if clientSecret := r.Form.Get("client_secret"); clientSecret != "test" && !public {
 		w.WriteHeader(http.StatusForbidden)
 		return
 	}
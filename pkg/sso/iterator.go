@@ -0,0 +1,61 @@
+package sso
+
+import (
+	"context"
+
+	"github.com/omi-lab/workos-go/v4/internal/workos"
+	"github.com/omi-lab/workos-go/v4/pkg/common"
+	"github.com/omi-lab/workos-go/v4/pkg/models"
+)
+
+// ConnectionIterator iterates over Connections, fetching pages as needed.
+//
+//	it := client.IterateConnections(ctx, opts)
+//	for it.Next() {
+//	    v := it.Value()
+//	}
+//	if err := it.Err(); err != nil {
+//	    // Handle error.
+//	}
+type ConnectionIterator struct {
+	pager *workos.Pager
+}
+
+// Next advances the iterator to the next Connection. It returns false when
+// there are no more Connections, when a request fails or when the context is
+// done.
+func (it *ConnectionIterator) Next() bool {
+	return it.pager.Next()
+}
+
+// Value returns the current Connection.
+func (it *ConnectionIterator) Value() models.Connection {
+	return it.pager.Value().(models.Connection)
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *ConnectionIterator) Err() error {
+	return it.pager.Err()
+}
+
+// CollectAll returns the remaining Connections, up to max when max is
+// positive.
+func (it *ConnectionIterator) CollectAll(max int) ([]models.Connection, error) {
+	var all []models.Connection
+	err := it.pager.CollectAll(max, &all)
+	return all, err
+}
+
+// IterateConnections returns an iterator over the Connections matching the
+// given options.
+//
+// The iterator follows the After cursor of each page, or the Before cursor
+// when opts only sets Before.
+func (c *Client) IterateConnections(ctx context.Context, opts ListConnectionsOpts, reqOpts ...common.RequestOption) *ConnectionIterator {
+	return &ConnectionIterator{pager: workos.NewPager(ctx, opts.Before, opts.After, func(ctx context.Context, before, after string) (interface{}, common.ListMetadata, error) {
+		opts.Before, opts.After = before, after
+
+		res, err := c.ListConnections(ctx, opts, reqOpts...)
+		return res.Data, res.ListMetadata, err
+	})}
+}
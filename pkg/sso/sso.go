@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/url"
 
+	"github.com/omi-lab/workos-go/v4/pkg/common"
 	"github.com/omi-lab/workos-go/v4/pkg/models"
 )
 
@@ -31,14 +32,14 @@ func GetAuthorizationURL(opts GetAuthorizationURLOpts) (*url.URL, error) {
 
 // GetProfileAndToken returns a profile describing the user that authenticated with
 // WorkOS SSO.
-func GetProfileAndToken(ctx context.Context, opts GetProfileAndTokenOpts) (ProfileAndToken, error) {
-	return DefaultClient.GetProfileAndToken(ctx, opts)
+func GetProfileAndToken(ctx context.Context, opts GetProfileAndTokenOpts, reqOpts ...common.RequestOption) (ProfileAndToken, error) {
+	return DefaultClient.GetProfileAndToken(ctx, opts, reqOpts...)
 }
 
 // GetProfile returns a profile describing the user that authenticated with
 // WorkOS SSO.
-func GetProfile(ctx context.Context, opts GetProfileOpts) (Profile, error) {
-	return DefaultClient.GetProfile(ctx, opts)
+func GetProfile(ctx context.Context, opts GetProfileOpts, reqOpts ...common.RequestOption) (Profile, error) {
+	return DefaultClient.GetProfile(ctx, opts, reqOpts...)
 }
 
 // Login returns a http.Handler that redirects client to the appropriate
@@ -51,22 +52,35 @@ func Login(opts GetAuthorizationURLOpts) http.Handler {
 func GetConnection(
 	ctx context.Context,
 	opts GetConnectionOpts,
+	reqOpts ...common.RequestOption,
 ) (models.Connection, error) {
-	return DefaultClient.GetConnection(ctx, opts)
+	return DefaultClient.GetConnection(ctx, opts, reqOpts...)
 }
 
 // ListConnections gets a list of existing Connections.
 func ListConnections(
 	ctx context.Context,
 	opts ListConnectionsOpts,
+	reqOpts ...common.RequestOption,
 ) (ListConnectionsResponse, error) {
-	return DefaultClient.ListConnections(ctx, opts)
+	return DefaultClient.ListConnections(ctx, opts, reqOpts...)
 }
 
 // DeleteConnection deletes a Connection.
 func DeleteConnection(
 	ctx context.Context,
 	opts DeleteConnectionOpts,
+	reqOpts ...common.RequestOption,
 ) error {
-	return DefaultClient.DeleteConnection(ctx, opts)
+	return DefaultClient.DeleteConnection(ctx, opts, reqOpts...)
+}
+
+// IterateConnections returns an iterator over the Connections matching the given
+// options.
+func IterateConnections(
+	ctx context.Context,
+	opts ListConnectionsOpts,
+	reqOpts ...common.RequestOption,
+) *ConnectionIterator {
+	return DefaultClient.IterateConnections(ctx, opts, reqOpts...)
 }
@@ -0,0 +1,377 @@
+package usermanagement
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/omi-lab/workos-go/v4/pkg/common"
+	"github.com/omi-lab/workos-go/v4/pkg/workos_errors"
+)
+
+var (
+	// ErrInvalidAccessToken is returned when an AccessToken is malformed, is
+	// not signed by WorkOS or was not issued for the Client.
+	ErrInvalidAccessToken = errors.New("invalid access token")
+
+	// ErrAccessTokenExpired is returned when an AccessToken has expired.
+	ErrAccessTokenExpired = errors.New("access token expired")
+)
+
+const (
+	// The clock skew tolerated when checking the times of an AccessToken.
+	accessTokenLeeway = time.Minute
+
+	// The minimum time between two fetches of the JWKS, so that tokens signed
+	// with unknown keys can not be used to flood WorkOS with requests.
+	jwksRefreshInterval = time.Minute
+)
+
+// AccessTokenClaims contains the claims of a verified AccessToken.
+type AccessTokenClaims struct {
+	// The issuer of the token.
+	Issuer string
+
+	// The ID of the User the token was issued to.
+	Subject string
+
+	// The audiences the token was issued for.
+	Audience []string
+
+	// The token's unique identifier.
+	ID string
+
+	// The ID of the session the token belongs to. It is used to log the
+	// User out with GetLogoutURL or RevokeSession.
+	SessionID string
+
+	// The ID of the Organization the User is signed in to, if any.
+	OrganizationID string
+
+	// The slug of the User's role in the Organization, if any.
+	Role string
+
+	// The permissions granted by the User's role.
+	Permissions []string
+
+	// Present if the User is being impersonated.
+	Impersonator *Impersonator
+
+	// The time the token was issued at.
+	IssuedAt time.Time
+
+	// The time before which the token must not be accepted, if any.
+	NotBefore time.Time
+
+	// The time the token expires at.
+	ExpiresAt time.Time
+}
+
+// accessTokenPayload is the JSON encoded payload of an AccessToken.
+type accessTokenPayload struct {
+	Issuer         string          `json:"iss"`
+	Subject        string          `json:"sub"`
+	Audience       json.RawMessage `json:"aud"`
+	ID             string          `json:"jti"`
+	SessionID      string          `json:"sid"`
+	OrganizationID string          `json:"org_id"`
+	Role           string          `json:"role"`
+	Permissions    []string        `json:"permissions"`
+	IssuedAt       *int64          `json:"iat"`
+	NotBefore      *int64          `json:"nbf"`
+	ExpiresAt      *int64          `json:"exp"`
+	Actor          *struct {
+		Subject string `json:"sub"`
+		Reason  string `json:"reason"`
+	} `json:"act"`
+}
+
+// VerifyAccessToken verifies the signature and the claims of an AccessToken
+// returned by the authentication methods, and returns its claims.
+//
+// The token must be signed with RS256 by one of the keys of the JWKS of the
+// Client's ClientID, which are fetched from WorkOS and cached. It must have
+// been issued by the Client's Endpoint and, when it has an audience, for its
+// ClientID.
+//
+// An error wrapping ErrInvalidAccessToken is returned when the token can not
+// be trusted. ErrAccessTokenExpired is returned along with the claims when
+// the token is valid but has expired, so that the session it belongs to can
+// still be refreshed or ended.
+func (c *Client) VerifyAccessToken(ctx context.Context, token string, reqOpts ...common.RequestOption) (AccessTokenClaims, error) {
+	if c.ClientID == "" {
+		return AccessTokenClaims{}, errors.New("the Client has no ClientID")
+	}
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return AccessTokenClaims{}, fmt.Errorf("%w: malformed token", ErrInvalidAccessToken)
+	}
+
+	var header struct {
+		Algorithm string `json:"alg"`
+		KeyID     string `json:"kid"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return AccessTokenClaims{}, fmt.Errorf("%w: malformed header: %v", ErrInvalidAccessToken, err)
+	}
+	if header.Algorithm != "RS256" {
+		return AccessTokenClaims{}, fmt.Errorf("%w: unsupported algorithm %q", ErrInvalidAccessToken, header.Algorithm)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return AccessTokenClaims{}, fmt.Errorf("%w: malformed signature: %v", ErrInvalidAccessToken, err)
+	}
+
+	key, err := c.jwks.key(ctx, c, header.KeyID, reqOpts...)
+	if err != nil {
+		return AccessTokenClaims{}, err
+	}
+
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err = rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature); err != nil {
+		return AccessTokenClaims{}, fmt.Errorf("%w: bad signature", ErrInvalidAccessToken)
+	}
+
+	var payload accessTokenPayload
+	if err = decodeSegment(parts[1], &payload); err != nil {
+		return AccessTokenClaims{}, fmt.Errorf("%w: malformed payload: %v", ErrInvalidAccessToken, err)
+	}
+
+	claims, err := payload.claims()
+	if err != nil {
+		return AccessTokenClaims{}, err
+	}
+	err = c.validateClaims(claims, time.Now())
+	if err != nil && err != ErrAccessTokenExpired {
+		return AccessTokenClaims{}, err
+	}
+	return claims, err
+}
+
+func (p accessTokenPayload) claims() (AccessTokenClaims, error) {
+	claims := AccessTokenClaims{
+		Issuer:         p.Issuer,
+		Subject:        p.Subject,
+		ID:             p.ID,
+		SessionID:      p.SessionID,
+		OrganizationID: p.OrganizationID,
+		Role:           p.Role,
+		Permissions:    p.Permissions,
+	}
+
+	// The audience is either a single string or an array of strings.
+	if len(p.Audience) != 0 && string(p.Audience) != "null" {
+		var aud string
+		if err := json.Unmarshal(p.Audience, &aud); err == nil {
+			claims.Audience = []string{aud}
+		} else if err = json.Unmarshal(p.Audience, &claims.Audience); err != nil {
+			return AccessTokenClaims{}, fmt.Errorf("%w: malformed audience", ErrInvalidAccessToken)
+		}
+	}
+
+	if p.ExpiresAt == nil {
+		return AccessTokenClaims{}, fmt.Errorf("%w: missing exp claim", ErrInvalidAccessToken)
+	}
+	claims.ExpiresAt = time.Unix(*p.ExpiresAt, 0)
+
+	if p.IssuedAt != nil {
+		claims.IssuedAt = time.Unix(*p.IssuedAt, 0)
+	}
+
+	if p.NotBefore != nil {
+		claims.NotBefore = time.Unix(*p.NotBefore, 0)
+	}
+
+	if p.Actor != nil {
+		claims.Impersonator = &Impersonator{
+			Email:  p.Actor.Subject,
+			Reason: p.Actor.Reason,
+		}
+	}
+	return claims, nil
+}
+
+func (c *Client) validateClaims(claims AccessTokenClaims, now time.Time) error {
+	endpoint := strings.TrimSuffix(c.Endpoint, "/")
+	if claims.Issuer != endpoint && claims.Issuer != endpoint+"/user_management/"+c.ClientID {
+		return fmt.Errorf("%w: unexpected issuer %q", ErrInvalidAccessToken, claims.Issuer)
+	}
+
+	if len(claims.Audience) != 0 && !containsString(claims.Audience, c.ClientID) {
+		return fmt.Errorf("%w: not issued for %s", ErrInvalidAccessToken, c.ClientID)
+	}
+
+	if claims.IssuedAt.After(now.Add(accessTokenLeeway)) {
+		return fmt.Errorf("%w: issued in the future", ErrInvalidAccessToken)
+	}
+
+	if claims.NotBefore.After(now.Add(accessTokenLeeway)) {
+		return fmt.Errorf("%w: not valid yet", ErrInvalidAccessToken)
+	}
+
+	if now.After(claims.ExpiresAt.Add(accessTokenLeeway)) {
+		return ErrAccessTokenExpired
+	}
+	return nil
+}
+
+func decodeSegment(segment string, v interface{}) error {
+	b, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+func containsString(values []string, v string) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
+
+// jwksCache caches the public keys of the JWKS of a Client.
+type jwksCache struct {
+	mu        sync.Mutex
+	keys      map[string]*rsa.PublicKey
+	fetchedAt time.Time
+	fetching  *jwksFetch
+}
+
+// jwksFetch is a fetch of the JWKS, shared by the verifications that wait for
+// it.
+type jwksFetch struct {
+	done chan struct{}
+	err  error
+}
+
+// key returns the public key with the given ID. The JWKS is fetched again when
+// the key is unknown, since WorkOS may have rotated its keys.
+//
+// The lock is not held while the JWKS is fetched, so that the tokens signed
+// with cached keys are verified meanwhile. Concurrent fetches are coalesced,
+// and once keys are cached, unknown keys trigger at most one fetch every
+// jwksRefreshInterval.
+func (j *jwksCache) key(ctx context.Context, c *Client, kid string, reqOpts ...common.RequestOption) (*rsa.PublicKey, error) {
+	j.mu.Lock()
+	if key, ok := j.keys[kid]; ok {
+		j.mu.Unlock()
+		return key, nil
+	}
+
+	fetch := j.fetching
+	if fetch == nil {
+		if j.keys != nil && time.Since(j.fetchedAt) < jwksRefreshInterval {
+			j.mu.Unlock()
+			return nil, fmt.Errorf("%w: unknown key %q", ErrInvalidAccessToken, kid)
+		}
+
+		fetch = &jwksFetch{done: make(chan struct{})}
+		j.fetching = fetch
+		j.mu.Unlock()
+
+		keys, err := c.fetchJWKS(ctx, reqOpts...)
+
+		j.mu.Lock()
+		if err == nil {
+			j.keys = keys
+		}
+		j.fetchedAt = time.Now()
+		j.fetching = nil
+		fetch.err = err
+		close(fetch.done)
+	} else {
+		j.mu.Unlock()
+
+		select {
+		case <-fetch.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		j.mu.Lock()
+	}
+	defer j.mu.Unlock()
+
+	if key, ok := j.keys[kid]; ok {
+		return key, nil
+	}
+	if fetch.err != nil {
+		return nil, fetch.err
+	}
+	return nil, fmt.Errorf("%w: unknown key %q", ErrInvalidAccessToken, kid)
+}
+
+// fetchJWKS fetches the RSA public keys of the JWKS of the Client's ClientID.
+func (c *Client) fetchJWKS(ctx context.Context, reqOpts ...common.RequestOption) (map[string]*rsa.PublicKey, error) {
+	u, err := c.GetJWKSURL(c.ClientID)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Add context to the request
+	req = req.WithContext(ctx)
+
+	res, err := c.do(req, reqOpts...)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if err = workos_errors.TryGetHTTPError(res); err != nil {
+		return nil, err
+	}
+
+	var body struct {
+		Keys []struct {
+			KeyType string `json:"kty"`
+			KeyID   string `json:"kid"`
+			Use     string `json:"use"`
+			N       string `json:"n"`
+			E       string `json:"e"`
+		} `json:"keys"`
+	}
+	if err = json.NewDecoder(res.Body).Decode(&body); err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]*rsa.PublicKey, len(body.Keys))
+	for _, k := range body.Keys {
+		if k.KeyType != "RSA" || (k.Use != "" && k.Use != "sig") {
+			continue
+		}
+
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("malformed modulus of key %q: %w", k.KeyID, err)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("malformed exponent of key %q: %w", k.KeyID, err)
+		}
+
+		keys[k.KeyID] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+	return keys, nil
+}
@@ -0,0 +1,369 @@
+package usermanagement
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testClientID = "client_123"
+
+type testSigner struct {
+	kid string
+	key *rsa.PrivateKey
+}
+
+func newTestSigner(t *testing.T, kid string) testSigner {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	return testSigner{kid: kid, key: key}
+}
+
+func (s testSigner) jwk() map[string]string {
+	return map[string]string{
+		"kty": "RSA",
+		"kid": s.kid,
+		"use": "sig",
+		"alg": "RS256",
+		"n":   base64.RawURLEncoding.EncodeToString(s.key.N.Bytes()),
+		"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(s.key.E)).Bytes()),
+	}
+}
+
+func (s testSigner) sign(t *testing.T, header, claims map[string]interface{}) string {
+	if header == nil {
+		header = map[string]interface{}{"alg": "RS256", "typ": "JWT", "kid": s.kid}
+	}
+
+	h, err := json.Marshal(header)
+	require.NoError(t, err)
+	c, err := json.Marshal(claims)
+	require.NoError(t, err)
+
+	signed := base64.RawURLEncoding.EncodeToString(h) + "." + base64.RawURLEncoding.EncodeToString(c)
+	digest := sha256.Sum256([]byte(signed))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, s.key, crypto.SHA256, digest[:])
+	require.NoError(t, err)
+
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+// jwksServer serves the JWKS of the given signers and counts the requests it
+// receives.
+func jwksServer(t *testing.T, fetches *int32, signers *[]testSigner) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(fetches, 1)
+		if r.URL.Path != "/sso/jwks/"+testClientID {
+			http.Error(w, "not found", http.StatusNotFound)
+			return
+		}
+
+		var keys []map[string]string
+		for _, s := range *signers {
+			keys = append(keys, s.jwk())
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"keys": keys})
+	}))
+}
+
+func TestVerifyAccessToken(t *testing.T) {
+	signer := newTestSigner(t, "key_1")
+	other := newTestSigner(t, "key_1")
+
+	var fetches int32
+	signers := []testSigner{signer}
+	server := jwksServer(t, &fetches, &signers)
+	defer server.Close()
+
+	client := NewClient("test")
+	client.ClientID = testClientID
+	client.Endpoint = server.URL
+
+	now := time.Now()
+	validClaims := func() map[string]interface{} {
+		return map[string]interface{}{
+			"iss":         server.URL,
+			"sub":         "user_01",
+			"sid":         "session_01",
+			"jti":         "token_01",
+			"org_id":      "org_01",
+			"role":        "admin",
+			"permissions": []string{"posts:read", "posts:write"},
+			"iat":         now.Unix(),
+			"exp":         now.Add(5 * time.Minute).Unix(),
+		}
+	}
+
+	tests := []struct {
+		scenario string
+		token    func() string
+		expected AccessTokenClaims
+		err      error
+	}{
+		{
+			scenario: "valid token",
+			token: func() string {
+				return signer.sign(t, nil, validClaims())
+			},
+			expected: AccessTokenClaims{
+				Issuer:         server.URL,
+				Subject:        "user_01",
+				ID:             "token_01",
+				SessionID:      "session_01",
+				OrganizationID: "org_01",
+				Role:           "admin",
+				Permissions:    []string{"posts:read", "posts:write"},
+				IssuedAt:       time.Unix(now.Unix(), 0),
+				ExpiresAt:      time.Unix(now.Add(5*time.Minute).Unix(), 0),
+			},
+		},
+		{
+			scenario: "impersonated user with client issuer and audience",
+			token: func() string {
+				claims := validClaims()
+				claims["iss"] = server.URL + "/user_management/" + testClientID
+				claims["aud"] = testClientID
+				claims["act"] = map[string]string{"sub": "admin@foo-corp.com", "reason": "support"}
+				return signer.sign(t, nil, claims)
+			},
+			expected: AccessTokenClaims{
+				Issuer:         server.URL + "/user_management/" + testClientID,
+				Subject:        "user_01",
+				Audience:       []string{testClientID},
+				ID:             "token_01",
+				SessionID:      "session_01",
+				OrganizationID: "org_01",
+				Role:           "admin",
+				Permissions:    []string{"posts:read", "posts:write"},
+				Impersonator:   &Impersonator{Email: "admin@foo-corp.com", Reason: "support"},
+				IssuedAt:       time.Unix(now.Unix(), 0),
+				ExpiresAt:      time.Unix(now.Add(5*time.Minute).Unix(), 0),
+			},
+		},
+		{
+			scenario: "expired token",
+			token: func() string {
+				claims := validClaims()
+				claims["exp"] = now.Add(-time.Hour).Unix()
+				return signer.sign(t, nil, claims)
+			},
+			expected: AccessTokenClaims{
+				Issuer:         server.URL,
+				Subject:        "user_01",
+				ID:             "token_01",
+				SessionID:      "session_01",
+				OrganizationID: "org_01",
+				Role:           "admin",
+				Permissions:    []string{"posts:read", "posts:write"},
+				IssuedAt:       time.Unix(now.Unix(), 0),
+				ExpiresAt:      time.Unix(now.Add(-time.Hour).Unix(), 0),
+			},
+			err: ErrAccessTokenExpired,
+		},
+		{
+			scenario: "token signed with another key",
+			token: func() string {
+				return other.sign(t, nil, validClaims())
+			},
+			err: ErrInvalidAccessToken,
+		},
+		{
+			scenario: "unsigned token",
+			token: func() string {
+				token := signer.sign(t, map[string]interface{}{"alg": "none", "kid": signer.kid}, validClaims())
+				return token[:len(token)-1]
+			},
+			err: ErrInvalidAccessToken,
+		},
+		{
+			scenario: "token from another issuer",
+			token: func() string {
+				claims := validClaims()
+				claims["iss"] = "https://evil.example.com"
+				return signer.sign(t, nil, claims)
+			},
+			err: ErrInvalidAccessToken,
+		},
+		{
+			scenario: "token for another client",
+			token: func() string {
+				claims := validClaims()
+				claims["aud"] = []string{"client_456"}
+				return signer.sign(t, nil, claims)
+			},
+			err: ErrInvalidAccessToken,
+		},
+		{
+			scenario: "token issued in the future",
+			token: func() string {
+				claims := validClaims()
+				claims["iat"] = now.Add(time.Hour).Unix()
+				return signer.sign(t, nil, claims)
+			},
+			err: ErrInvalidAccessToken,
+		},
+		{
+			scenario: "token not valid yet",
+			token: func() string {
+				claims := validClaims()
+				claims["nbf"] = now.Add(time.Hour).Unix()
+				return signer.sign(t, nil, claims)
+			},
+			err: ErrInvalidAccessToken,
+		},
+		{
+			scenario: "token without expiration",
+			token: func() string {
+				claims := validClaims()
+				delete(claims, "exp")
+				return signer.sign(t, nil, claims)
+			},
+			err: ErrInvalidAccessToken,
+		},
+		{
+			scenario: "malformed token",
+			token: func() string {
+				return "not-a-token"
+			},
+			err: ErrInvalidAccessToken,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			claims, err := client.VerifyAccessToken(context.Background(), test.token())
+			if test.err != nil {
+				require.True(t, errors.Is(err, test.err), "unexpected error: %v", err)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, test.expected, claims)
+		})
+	}
+
+	require.Equal(t, int32(1), atomic.LoadInt32(&fetches))
+}
+
+func TestVerifyAccessTokenRefreshesKeys(t *testing.T) {
+	first := newTestSigner(t, "key_1")
+	second := newTestSigner(t, "key_2")
+
+	var fetches int32
+	signers := []testSigner{first}
+	server := jwksServer(t, &fetches, &signers)
+	defer server.Close()
+
+	client := NewClient("test")
+	client.ClientID = testClientID
+	client.Endpoint = server.URL
+
+	claims := map[string]interface{}{
+		"iss": server.URL,
+		"sub": "user_01",
+		"exp": time.Now().Add(time.Minute).Unix(),
+	}
+
+	_, err := client.VerifyAccessToken(context.Background(), first.sign(t, nil, claims))
+	require.NoError(t, err)
+
+	// Tokens signed with an unknown key are rejected without hitting WorkOS
+	// again right away.
+	_, err = client.VerifyAccessToken(context.Background(), second.sign(t, nil, claims))
+	require.True(t, errors.Is(err, ErrInvalidAccessToken))
+	require.Equal(t, int32(1), atomic.LoadInt32(&fetches))
+
+	// Once the keys are stale, an unknown key triggers a refresh.
+	signers = append(signers, second)
+	client.jwks.fetchedAt = time.Now().Add(-jwksRefreshInterval)
+
+	_, err = client.VerifyAccessToken(context.Background(), second.sign(t, nil, claims))
+	require.NoError(t, err)
+	require.Equal(t, int32(2), atomic.LoadInt32(&fetches))
+
+	_, err = client.VerifyAccessToken(context.Background(), first.sign(t, nil, claims))
+	require.NoError(t, err)
+	require.Equal(t, int32(2), atomic.LoadInt32(&fetches))
+}
+
+func TestVerifyAccessTokenWhileRefreshingKeys(t *testing.T) {
+	first := newTestSigner(t, "key_1")
+	second := newTestSigner(t, "key_2")
+
+	var fetches int32
+	signers := []testSigner{first, second}
+	jwks := jwksServer(t, &fetches, &signers)
+	defer jwks.Close()
+
+	// Hold the refreshes until released.
+	fetching := make(chan struct{}, 1)
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.LoadInt32(&fetches) > 0 {
+			fetching <- struct{}{}
+			<-release
+		}
+		jwks.Config.Handler.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	client := NewClient("test")
+	client.ClientID = testClientID
+	client.Endpoint = server.URL
+
+	claims := map[string]interface{}{
+		"iss": server.URL,
+		"sub": "user_01",
+		"exp": time.Now().Add(time.Minute).Unix(),
+	}
+
+	_, err := client.VerifyAccessToken(context.Background(), first.sign(t, nil, claims))
+	require.NoError(t, err)
+
+	client.jwks.mu.Lock()
+	delete(client.jwks.keys, second.kid)
+	client.jwks.fetchedAt = time.Now().Add(-jwksRefreshInterval)
+	client.jwks.mu.Unlock()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 5)
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := client.VerifyAccessToken(context.Background(), second.sign(t, nil, claims))
+			errs <- err
+		}()
+	}
+	<-fetching
+
+	// Tokens signed with cached keys are verified during the refresh.
+	_, err = client.VerifyAccessToken(context.Background(), first.sign(t, nil, claims))
+	require.NoError(t, err)
+
+	close(release)
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.NoError(t, err)
+	}
+	require.Equal(t, int32(2), atomic.LoadInt32(&fetches))
+}
+
+func TestVerifyAccessTokenWithoutClientID(t *testing.T) {
+	_, err := NewClient("test").VerifyAccessToken(context.Background(), "token")
+	require.Error(t, err)
+}
@@ -0,0 +1,393 @@
+package usermanagement
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/omi-lab/workos-go/v4/pkg/common"
+	"github.com/omi-lab/workos-go/v4/pkg/mfa"
+	"github.com/omi-lab/workos-go/v4/pkg/models"
+	"github.com/omi-lab/workos-go/v4/pkg/workos_errors"
+)
+
+// ErrAuthFlowStepNotAllowed is returned when a step that is not one of the
+// next steps of an AuthFlow is submitted.
+var ErrAuthFlowStepNotAllowed = errors.New("authentication step not allowed")
+
+// AuthFlowStepType represents a step of a multi-step authentication.
+type AuthFlowStepType string
+
+// Constants that enumerate the steps of an AuthFlow.
+const (
+	// The User must enter the code of one of their MFA factors. It is
+	// completed with a TOTPCodeStep.
+	AuthFlowStepMFAChallenge AuthFlowStepType = "mfa_challenge"
+
+	// The User must enroll an MFA factor. It is completed with an
+	// EnrollTOTPStep, which is followed by an AuthFlowStepMFAChallenge.
+	AuthFlowStepMFAEnrollment AuthFlowStepType = "mfa_enrollment"
+
+	// The User must enter the code sent to their email address. It is
+	// completed with an EmailVerificationCodeStep.
+	AuthFlowStepEmailVerification AuthFlowStepType = "email_verification"
+
+	// The User must select the Organization to sign in to. It is completed
+	// with an OrganizationSelectionStep.
+	AuthFlowStepOrganizationSelection AuthFlowStepType = "organization_selection"
+
+	// The User must sign in with one of the SSO connections of the flow.
+	// It can not be submitted: the User has to be sent to an authorization
+	// URL of one of the ConnectionIDs instead.
+	AuthFlowStepSSO AuthFlowStepType = "sso"
+)
+
+// AuthFlowStep is the input of a step submitted to an AuthFlow.
+type AuthFlowStep interface {
+	stepType() AuthFlowStepType
+}
+
+// TOTPCodeStep completes an AuthFlowStepMFAChallenge.
+type TOTPCodeStep struct {
+	// The code generated by the User's authenticator app.
+	Code string
+
+	// The ID of the factor the code was generated for. Defaults to the
+	// factor being enrolled or to the first TOTP factor of the User.
+	FactorID string
+
+	IPAddress string
+	UserAgent string
+}
+
+func (TOTPCodeStep) stepType() AuthFlowStepType { return AuthFlowStepMFAChallenge }
+
+// EnrollTOTPStep completes an AuthFlowStepMFAEnrollment by enrolling a TOTP
+// factor, whose QR code is then available in the EnrolledFactor of the flow.
+type EnrollTOTPStep struct {
+	// The issuer shown in the User's authenticator app.
+	TOTPIssuer string
+
+	// The account name shown in the User's authenticator app. Defaults to
+	// the User's email address.
+	TOTPUser string
+}
+
+func (EnrollTOTPStep) stepType() AuthFlowStepType { return AuthFlowStepMFAEnrollment }
+
+// EmailVerificationCodeStep completes an AuthFlowStepEmailVerification.
+type EmailVerificationCodeStep struct {
+	// The code sent to the User's email address.
+	Code string
+
+	IPAddress string
+	UserAgent string
+}
+
+func (EmailVerificationCodeStep) stepType() AuthFlowStepType {
+	return AuthFlowStepEmailVerification
+}
+
+// OrganizationSelectionStep completes an AuthFlowStepOrganizationSelection.
+type OrganizationSelectionStep struct {
+	// The ID of one of the Organizations of the flow.
+	OrganizationID string
+
+	IPAddress string
+	UserAgent string
+}
+
+func (OrganizationSelectionStep) stepType() AuthFlowStepType {
+	return AuthFlowStepOrganizationSelection
+}
+
+// AuthFlowFactor is an MFA factor the User can complete a challenge with.
+type AuthFlowFactor struct {
+	ID   string            `json:"id"`
+	Type models.FactorType `json:"type"`
+}
+
+// AuthFlowOrganization is an Organization the User can sign in to.
+type AuthFlowOrganization struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// AuthFlow tracks a multi-step authentication, from the first
+// authentication attempt until the User is authenticated.
+//
+// An AuthFlow can be encoded to JSON to be resumed in a later request. Since
+// it holds the pending authentication token and the secret of the factor
+// being enrolled, it must be kept server side or in an encrypted cookie.
+type AuthFlow struct {
+	// The token identifying the pending authentication.
+	PendingAuthenticationToken string `json:"pending_authentication_token,omitempty"`
+
+	// The steps the User can take next.
+	Steps []AuthFlowStepType `json:"steps,omitempty"`
+
+	// The User being authenticated, when known.
+	User models.User `json:"user"`
+
+	// The email address of the User being authenticated.
+	Email string `json:"email,omitempty"`
+
+	// The MFA factors of the User, for an AuthFlowStepMFAChallenge.
+	Factors []AuthFlowFactor `json:"factors,omitempty"`
+
+	// The factor enrolled by an EnrollTOTPStep. Its TOTP details contain the
+	// QR code to show to the User.
+	EnrolledFactor *models.Factor `json:"enrolled_factor,omitempty"`
+
+	// The ID of the pending MFA challenge.
+	ChallengeID string `json:"challenge_id,omitempty"`
+
+	// The Organizations the User can select, for an
+	// AuthFlowStepOrganizationSelection.
+	Organizations []AuthFlowOrganization `json:"organizations,omitempty"`
+
+	// The SSO connections the User can sign in with, for an AuthFlowStepSSO.
+	ConnectionIDs []string `json:"connection_ids,omitempty"`
+
+	// The result of the authentication, once it is complete.
+	Response *AuthenticateResponse `json:"response,omitempty"`
+}
+
+// NewAuthFlow starts an AuthFlow from the result of an authentication
+// method, e.g.:
+//
+//	flow, err := usermanagement.NewAuthFlow(
+//		client.AuthenticateWithPassword(ctx, opts),
+//	)
+//
+// The flow is complete right away when the authentication succeeded. Errors
+// that do not require the User to take further steps are returned as is.
+func NewAuthFlow(res AuthenticateResponse, err error) (*AuthFlow, error) {
+	flow := &AuthFlow{}
+	if err = flow.update(res, err); err != nil {
+		return nil, err
+	}
+	return flow, nil
+}
+
+// Done reports whether the User is authenticated, in which case the result
+// is in the Response of the flow.
+func (f *AuthFlow) Done() bool {
+	return f.Response != nil
+}
+
+// Next returns the steps the User can take next. It returns nil once the
+// flow is complete.
+func (f *AuthFlow) Next() []AuthFlowStepType {
+	if f.Done() {
+		return nil
+	}
+	return append([]AuthFlowStepType(nil), f.Steps...)
+}
+
+// Allows reports whether the given step is one of the next steps.
+func (f *AuthFlow) Allows(step AuthFlowStepType) bool {
+	for _, s := range f.Next() {
+		if s == step {
+			return true
+		}
+	}
+	return false
+}
+
+// Submit submits the input of one of the next steps, and advances the flow
+// to the steps that follow it.
+//
+// When the submission is rejected, e.g. because of an invalid code, the
+// error is returned and the flow is left unchanged so that the step can be
+// submitted again.
+func (f *AuthFlow) Submit(ctx context.Context, c *Client, step AuthFlowStep, reqOpts ...common.RequestOption) error {
+	if step == nil || !f.Allows(step.stepType()) {
+		return ErrAuthFlowStepNotAllowed
+	}
+
+	switch s := step.(type) {
+	case TOTPCodeStep:
+		return f.submitTOTPCode(ctx, c, s, reqOpts...)
+
+	case EnrollTOTPStep:
+		return f.enrollTOTP(ctx, c, s, reqOpts...)
+
+	case EmailVerificationCodeStep:
+		return f.update(c.AuthenticateWithEmailVerificationCode(ctx, AuthenticateWithEmailVerificationCodeOpts{
+			Code:                       s.Code,
+			PendingAuthenticationToken: f.PendingAuthenticationToken,
+			IPAddress:                  s.IPAddress,
+			UserAgent:                  s.UserAgent,
+		}, reqOpts...))
+
+	case OrganizationSelectionStep:
+		if !f.hasOrganization(s.OrganizationID) {
+			return fmt.Errorf("%w: unknown organization %q", ErrAuthFlowStepNotAllowed, s.OrganizationID)
+		}
+		return f.update(c.AuthenticateWithOrganizationSelection(ctx, AuthenticateWithOrganizationSelectionOpts{
+			PendingAuthenticationToken: f.PendingAuthenticationToken,
+			OrganizationID:             s.OrganizationID,
+			IPAddress:                  s.IPAddress,
+			UserAgent:                  s.UserAgent,
+		}, reqOpts...))
+	}
+	return ErrAuthFlowStepNotAllowed
+}
+
+func (f *AuthFlow) submitTOTPCode(ctx context.Context, c *Client, s TOTPCodeStep, reqOpts ...common.RequestOption) error {
+	factorID := s.FactorID
+	if factorID == "" {
+		factorID = f.defaultFactorID()
+	}
+	if factorID == "" {
+		return fmt.Errorf("%w: no TOTP factor", ErrAuthFlowStepNotAllowed)
+	}
+
+	// A challenge is created by enrolling a factor. Other factors are
+	// challenged when their code is submitted.
+	challengeID := f.ChallengeID
+	if f.EnrolledFactor == nil || f.EnrolledFactor.ID != factorID {
+		challenge, err := c.mfaClient().ChallengeFactor(ctx, mfa.ChallengeFactorOpts{
+			FactorID: factorID,
+		}, reqOpts...)
+		if err != nil {
+			return err
+		}
+		challengeID = challenge.ID
+	}
+
+	return f.update(c.AuthenticateWithTOTP(ctx, AuthenticateWithTOTPOpts{
+		Code:                       s.Code,
+		PendingAuthenticationToken: f.PendingAuthenticationToken,
+		AuthenticationChallengeID:  challengeID,
+		IPAddress:                  s.IPAddress,
+		UserAgent:                  s.UserAgent,
+	}, reqOpts...))
+}
+
+func (f *AuthFlow) defaultFactorID() string {
+	if f.EnrolledFactor != nil {
+		return f.EnrolledFactor.ID
+	}
+	for _, factor := range f.Factors {
+		if factor.Type == models.FactorTypeTOTP {
+			return factor.ID
+		}
+	}
+	return ""
+}
+
+func (f *AuthFlow) enrollTOTP(ctx context.Context, c *Client, s EnrollTOTPStep, reqOpts ...common.RequestOption) error {
+	totpUser := s.TOTPUser
+	if totpUser == "" {
+		totpUser = f.User.Email
+	}
+
+	res, err := c.EnrollAuthFactor(ctx, EnrollAuthFactorOpts{
+		User:       f.User.ID,
+		Type:       models.FactorTypeTOTP,
+		TOTPIssuer: s.TOTPIssuer,
+		TOTPUser:   totpUser,
+	}, reqOpts...)
+	if err != nil {
+		return err
+	}
+
+	f.EnrolledFactor = &res.Factor
+	f.ChallengeID = res.Challenge.ID
+	f.Factors = []AuthFlowFactor{{ID: res.Factor.ID, Type: res.Factor.Type}}
+	f.Steps = []AuthFlowStepType{AuthFlowStepMFAChallenge}
+	return nil
+}
+
+func (f *AuthFlow) hasOrganization(id string) bool {
+	for _, o := range f.Organizations {
+		if o.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// update advances the flow with the result of an authentication. Errors that
+// do not lead to another step are returned without changing the flow.
+func (f *AuthFlow) update(res AuthenticateResponse, err error) error {
+	if err == nil {
+		*f = AuthFlow{
+			User:     res.User,
+			Email:    res.User.Email,
+			Response: &res,
+		}
+		return nil
+	}
+
+	var (
+		mfaChallenge          *workos_errors.ErrorMFAChallenge
+		mfaEnrollment         *workos_errors.ErrorMFAEnrollment
+		emailVerification     *workos_errors.ErrorEmailVerificationRequired
+		organizationSelection *workos_errors.ErrorOrganizationSelectionRequired
+		ssoRequired           *workos_errors.ErrorSSORequired
+	)
+
+	switch {
+	case errors.As(err, &mfaChallenge):
+		next := AuthFlow{
+			PendingAuthenticationToken: mfaChallenge.PendingAuthenticationToken,
+			Steps:                      []AuthFlowStepType{AuthFlowStepMFAChallenge},
+			User:                       mfaChallenge.User,
+			Email:                      mfaChallenge.User.Email,
+		}
+		for _, factor := range mfaChallenge.AuthenticationFactors {
+			next.Factors = append(next.Factors, AuthFlowFactor{
+				ID:   factor.ID,
+				Type: models.FactorType(factor.Type),
+			})
+		}
+		*f = next
+
+	case errors.As(err, &mfaEnrollment):
+		*f = AuthFlow{
+			PendingAuthenticationToken: mfaEnrollment.PendingAuthenticationToken,
+			Steps:                      []AuthFlowStepType{AuthFlowStepMFAEnrollment},
+			User:                       mfaEnrollment.User,
+			Email:                      mfaEnrollment.User.Email,
+		}
+
+	case errors.As(err, &emailVerification):
+		*f = AuthFlow{
+			PendingAuthenticationToken: emailVerification.PendingAuthenticationToken,
+			Steps:                      []AuthFlowStepType{AuthFlowStepEmailVerification},
+			User:                       f.User,
+			Email:                      emailVerification.Email,
+		}
+
+	case errors.As(err, &organizationSelection):
+		next := AuthFlow{
+			PendingAuthenticationToken: organizationSelection.PendingAuthenticationToken,
+			Steps:                      []AuthFlowStepType{AuthFlowStepOrganizationSelection},
+			User:                       organizationSelection.User,
+			Email:                      organizationSelection.User.Email,
+		}
+		for _, o := range organizationSelection.Organizations {
+			next.Organizations = append(next.Organizations, AuthFlowOrganization{
+				ID:   o.ID,
+				Name: o.Name,
+			})
+		}
+		*f = next
+
+	case errors.As(err, &ssoRequired):
+		*f = AuthFlow{
+			PendingAuthenticationToken: ssoRequired.PendingAuthenticationToken,
+			Steps:                      []AuthFlowStepType{AuthFlowStepSSO},
+			User:                       f.User,
+			Email:                      ssoRequired.Email,
+			ConnectionIDs:              ssoRequired.ConnectionIDs,
+		}
+
+	default:
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,218 @@
+package usermanagement
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/omi-lab/workos-go/v4/pkg/models"
+	"github.com/omi-lab/workos-go/v4/pkg/workos_errors"
+)
+
+// authFlowTestServer fakes a sign-in that requires the User to verify their
+// email address, enroll an MFA factor and select an Organization.
+func authFlowTestServer(t *testing.T) *httptest.Server {
+	user := `{"id": "user_01", "email": "marcelina@foo-corp.com"}`
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		json.NewDecoder(r.Body).Decode(&body)
+
+		w.Header().Set("Content-Type", "application/json")
+		reply := func(status int, payload string) {
+			w.WriteHeader(status)
+			w.Write([]byte(payload))
+		}
+
+		switch r.URL.Path {
+		case "/user_management/users/user_01/auth_factors":
+			require.Equal(t, "totp", body["type"])
+			require.Equal(t, "marcelina@foo-corp.com", body["totp_user"])
+			reply(http.StatusCreated, `{
+				"authentication_factor": {"id": "factor_01", "type": "totp", "totp": {"qr_code": "qr_code", "secret": "secret"}},
+				"authentication_challenge": {"id": "challenge_01", "authentication_factor_id": "factor_01"}
+			}`)
+
+		case "/auth/factors/factor_02/challenge":
+			reply(http.StatusCreated, `{"id": "challenge_02", "authentication_factor_id": "factor_02"}`)
+
+		case "/user_management/authenticate":
+			switch body["grant_type"] {
+			case "password":
+				if body["password"] == "mfa" {
+					reply(http.StatusForbidden, `{
+						"code": "mfa_challenge",
+						"message": "The user must complete an MFA challenge.",
+						"pending_authentication_token": "token_mfa",
+						"authentication_factors": [{"id": "factor_02", "type": "totp"}],
+						"user": `+user+`
+					}`)
+					return
+				}
+				reply(http.StatusForbidden, `{
+					"code": "email_verification_required",
+					"message": "Email ownership must be verified before authentication.",
+					"pending_authentication_token": "token_email",
+					"email": "marcelina@foo-corp.com",
+					"email_verification_id": "email_verification_01"
+				}`)
+
+			case "urn:workos:oauth:grant-type:email-verification:code":
+				require.Equal(t, "token_email", body["pending_authentication_token"])
+				if body["code"] != "123456" {
+					reply(http.StatusBadRequest, `{"code": "invalid_one_time_code", "message": "The code is invalid."}`)
+					return
+				}
+				reply(http.StatusForbidden, `{
+					"code": "mfa_enrollment",
+					"message": "The user must enroll in MFA.",
+					"pending_authentication_token": "token_enrollment",
+					"user": `+user+`
+				}`)
+
+			case "urn:workos:oauth:grant-type:mfa-totp":
+				switch body["authentication_challenge_id"] {
+				case "challenge_01":
+					require.Equal(t, "token_enrollment", body["pending_authentication_token"])
+					reply(http.StatusForbidden, `{
+						"code": "organization_selection_required",
+						"message": "The user must choose an organization.",
+						"pending_authentication_token": "token_organization",
+						"organizations": [{"id": "org_01", "name": "Foo Corp"}, {"id": "org_02", "name": "Bar Corp"}],
+						"user": `+user+`
+					}`)
+				case "challenge_02":
+					require.Equal(t, "token_mfa", body["pending_authentication_token"])
+					reply(http.StatusOK, `{"user": `+user+`, "access_token": "access_token"}`)
+				default:
+					reply(http.StatusBadRequest, `{"code": "authentication_challenge_not_found"}`)
+				}
+
+			case "urn:workos:oauth:grant-type:organization-selection":
+				require.Equal(t, "token_organization", body["pending_authentication_token"])
+				reply(http.StatusOK, `{"user": `+user+`, "organization_id": "`+body["organization_id"]+`", "access_token": "access_token"}`)
+			}
+
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+func newAuthFlowTestClient(server *httptest.Server) *Client {
+	client := NewClient("test")
+	client.ClientID = testClientID
+	client.Endpoint = server.URL
+	return client
+}
+
+// roundTrip encodes and decodes a flow, as when it is resumed in another
+// request.
+func roundTrip(t *testing.T, flow *AuthFlow) *AuthFlow {
+	b, err := json.Marshal(flow)
+	require.NoError(t, err)
+
+	var resumed AuthFlow
+	require.NoError(t, json.Unmarshal(b, &resumed))
+	return &resumed
+}
+
+func TestAuthFlow(t *testing.T) {
+	server := authFlowTestServer(t)
+	defer server.Close()
+
+	ctx := context.Background()
+	client := newAuthFlowTestClient(server)
+
+	flow, err := NewAuthFlow(client.AuthenticateWithPassword(ctx, AuthenticateWithPasswordOpts{
+		Email:    "marcelina@foo-corp.com",
+		Password: "password",
+	}))
+	require.NoError(t, err)
+	require.False(t, flow.Done())
+	require.Equal(t, []AuthFlowStepType{AuthFlowStepEmailVerification}, flow.Next())
+	require.Equal(t, "marcelina@foo-corp.com", flow.Email)
+
+	// An invalid code leaves the flow unchanged.
+	flow = roundTrip(t, flow)
+	err = flow.Submit(ctx, client, EmailVerificationCodeStep{Code: "000000"})
+	require.True(t, workos_errors.IsBadRequest(err), "unexpected error: %v", err)
+	require.Equal(t, []AuthFlowStepType{AuthFlowStepEmailVerification}, flow.Next())
+
+	require.NoError(t, flow.Submit(ctx, client, EmailVerificationCodeStep{Code: "123456"}))
+	require.Equal(t, []AuthFlowStepType{AuthFlowStepMFAEnrollment}, flow.Next())
+	require.Equal(t, "user_01", flow.User.ID)
+
+	flow = roundTrip(t, flow)
+	require.NoError(t, flow.Submit(ctx, client, EnrollTOTPStep{TOTPIssuer: "Foo Corp"}))
+	require.Equal(t, []AuthFlowStepType{AuthFlowStepMFAChallenge}, flow.Next())
+	require.NotNil(t, flow.EnrolledFactor)
+	require.Equal(t, "qr_code", flow.EnrolledFactor.TOTP.QRCode)
+
+	flow = roundTrip(t, flow)
+	require.NoError(t, flow.Submit(ctx, client, TOTPCodeStep{Code: "123456"}))
+	require.Equal(t, []AuthFlowStepType{AuthFlowStepOrganizationSelection}, flow.Next())
+	require.Equal(t, []AuthFlowOrganization{
+		{ID: "org_01", Name: "Foo Corp"},
+		{ID: "org_02", Name: "Bar Corp"},
+	}, flow.Organizations)
+	require.Nil(t, flow.EnrolledFactor)
+
+	err = flow.Submit(ctx, client, OrganizationSelectionStep{OrganizationID: "org_03"})
+	require.True(t, errors.Is(err, ErrAuthFlowStepNotAllowed))
+
+	flow = roundTrip(t, flow)
+	require.NoError(t, flow.Submit(ctx, client, OrganizationSelectionStep{OrganizationID: "org_02"}))
+	require.True(t, flow.Done())
+	require.Nil(t, flow.Next())
+	require.Equal(t, "org_02", flow.Response.OrganizationID)
+	require.Equal(t, "access_token", flow.Response.AccessToken)
+	require.Empty(t, flow.PendingAuthenticationToken)
+}
+
+func TestAuthFlowChallengesExistingFactors(t *testing.T) {
+	server := authFlowTestServer(t)
+	defer server.Close()
+
+	ctx := context.Background()
+	client := newAuthFlowTestClient(server)
+
+	flow, err := NewAuthFlow(client.AuthenticateWithPassword(ctx, AuthenticateWithPasswordOpts{
+		Email:    "marcelina@foo-corp.com",
+		Password: "mfa",
+	}))
+	require.NoError(t, err)
+	require.Equal(t, []AuthFlowStepType{AuthFlowStepMFAChallenge}, flow.Next())
+	require.Equal(t, []AuthFlowFactor{{ID: "factor_02", Type: models.FactorTypeTOTP}}, flow.Factors)
+
+	require.NoError(t, flow.Submit(ctx, client, TOTPCodeStep{Code: "123456"}))
+	require.True(t, flow.Done())
+	require.Equal(t, "user_01", flow.Response.User.ID)
+}
+
+func TestAuthFlowRejectsUnexpectedSteps(t *testing.T) {
+	flow, err := NewAuthFlow(AuthenticateResponse{}, &workos_errors.ErrorSSORequired{
+		PendingAuthenticationToken: "token_sso",
+		Email:                      "marcelina@foo-corp.com",
+		ConnectionIDs:              []string{"conn_01"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []AuthFlowStepType{AuthFlowStepSSO}, flow.Next())
+	require.Equal(t, []string{"conn_01"}, flow.ConnectionIDs)
+
+	err = flow.Submit(context.Background(), NewClient("test"), TOTPCodeStep{Code: "123456"})
+	require.Equal(t, ErrAuthFlowStepNotAllowed, err)
+}
+
+func TestNewAuthFlowReturnsOtherErrors(t *testing.T) {
+	invalid := &workos_errors.ErrorInvalidCredentials{Message: "invalid credentials"}
+
+	flow, err := NewAuthFlow(AuthenticateResponse{}, invalid)
+	require.Nil(t, flow)
+	require.Equal(t, invalid, err)
+}
@@ -0,0 +1,331 @@
+package usermanagement
+
+import (
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/omi-lab/workos-go/v4/pkg/common"
+	"github.com/omi-lab/workos-go/v4/pkg/workos_errors"
+)
+
+// ErrInvalidState is returned by a CallbackHandler when the state of a
+// callback does not match the one of the authorization request, which means
+// that the callback was not initiated by the User's browser.
+var ErrInvalidState = errors.New("invalid authorization state")
+
+const (
+	// The default name of the cookie that stores the state of an
+	// authorization request.
+	defaultStateCookieName = "wos-auth-state"
+
+	// The default lifetime of an authorization request.
+	defaultStateMaxAge = 10 * time.Minute
+)
+
+// AuthorizationError is an error returned by WorkOS to a callback, e.g. when
+// the User denied the authorization request.
+type AuthorizationError struct {
+	// The OAuth 2.0 error code, e.g. access_denied.
+	Code string
+
+	// The description of the error.
+	Description string
+}
+
+func (e AuthorizationError) Error() string {
+	if e.Description == "" {
+		return e.Code
+	}
+	return e.Code + ": " + e.Description
+}
+
+// CallbackHandlerOpts contains the options to create a CallbackHandler.
+type CallbackHandlerOpts struct {
+	// The Client used to authenticate Users with the authorization code.
+	//
+	// REQUIRED.
+	Client *Client
+
+	// The key used to encrypt the state cookie. It must be 16, 24 or 32 bytes
+	// long to select AES-128, AES-192 or AES-256.
+	//
+	// REQUIRED.
+	StateKey []byte
+
+	// The name of the state cookie. Defaults to wos-auth-state.
+	StateCookieName string
+
+	// The time Users have to complete an authorization request. Defaults to
+	// 10 minutes.
+	StateMaxAge time.Duration
+
+	// Whether the state cookie can be sent over plain HTTP. It should only be
+	// set during development.
+	InsecureCookie bool
+
+	// Whether authorization requests use PKCE. The code verifier is kept in
+	// the state cookie until the callback, which lets public clients created
+	// without an APIKey exchange authorization codes.
+	PKCE bool
+
+	// The SessionManager that starts a session for authenticated Users before
+	// OnSuccess is called.
+	//
+	// OPTIONAL.
+	Sessions *SessionManager
+
+	// Called when a User is authenticated, with the state that was passed
+	// to AuthorizationURL.
+	//
+	// REQUIRED.
+	OnSuccess func(w http.ResponseWriter, r *http.Request, res AuthenticateResponse, state string)
+
+	// Called when the authentication fails. The error is an
+	// AuthorizationError, ErrInvalidState or an error returned by
+	// AuthenticateWithCode. Defaults to replying with 400 Bad Request or 500
+	// Internal Server Error.
+	OnError func(w http.ResponseWriter, r *http.Request, err error)
+
+	// Called when the User must complete an MFA challenge. Defaults to
+	// OnError.
+	OnMFAChallenge func(w http.ResponseWriter, r *http.Request, err *workos_errors.ErrorMFAChallenge)
+
+	// Called when the User must enroll an MFA factor. Defaults to OnError.
+	OnMFAEnrollment func(w http.ResponseWriter, r *http.Request, err *workos_errors.ErrorMFAEnrollment)
+
+	// Called when the User must verify their email address. Defaults to
+	// OnError.
+	OnEmailVerificationRequired func(w http.ResponseWriter, r *http.Request, err *workos_errors.ErrorEmailVerificationRequired)
+
+	// Called when the User must select the Organization to sign in to.
+	// Defaults to OnError.
+	OnOrganizationSelectionRequired func(w http.ResponseWriter, r *http.Request, err *workos_errors.ErrorOrganizationSelectionRequired)
+}
+
+// CallbackHandler handles the redirects of the authorization code flow. It
+// creates the authorization URLs Users are sent to, and handles the callback
+// they are redirected to by exchanging the authorization code.
+//
+// The state of authorization requests is kept in an encrypted cookie, so
+// that callbacks that were not initiated by the User's browser are rejected
+// and that the PKCE code verifier is not disclosed.
+type CallbackHandler struct {
+	opts CallbackHandlerOpts
+	aead cipher.AEAD
+}
+
+// authorizationState is the content of the state cookie.
+type authorizationState struct {
+	// The random value sent as the state of the authorization request.
+	Nonce string `json:"nonce"`
+
+	// The state of the application.
+	State string `json:"state,omitempty"`
+
+	// The PKCE code verifier, when PKCE is enabled.
+	CodeVerifier string `json:"code_verifier,omitempty"`
+}
+
+// NewCallbackHandler creates a CallbackHandler.
+func NewCallbackHandler(opts CallbackHandlerOpts) (*CallbackHandler, error) {
+	if opts.Client == nil {
+		return nil, errors.New("incomplete arguments: missing Client")
+	}
+	if len(opts.StateKey) == 0 {
+		return nil, errors.New("incomplete arguments: missing StateKey")
+	}
+	if opts.OnSuccess == nil {
+		return nil, errors.New("incomplete arguments: missing OnSuccess")
+	}
+
+	aead, err := newAEAD(opts.StateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.StateCookieName == "" {
+		opts.StateCookieName = defaultStateCookieName
+	}
+
+	if opts.StateMaxAge == 0 {
+		opts.StateMaxAge = defaultStateMaxAge
+	}
+
+	if opts.OnError == nil {
+		opts.OnError = defaultCallbackError
+	}
+
+	return &CallbackHandler{opts: opts, aead: aead}, nil
+}
+
+func defaultCallbackError(w http.ResponseWriter, r *http.Request, err error) {
+	var authErr AuthorizationError
+	if err == ErrInvalidState || errors.As(err, &authErr) || workos_errors.IsAuthenticationError(err) {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+	http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+}
+
+// AuthorizationURL returns the URL to redirect a User to in order to
+// authenticate them, and sets the state cookie that the callback will be
+// checked against. The State of the options is handed back to OnSuccess.
+func (h *CallbackHandler) AuthorizationURL(w http.ResponseWriter, opts GetAuthorizationURLOpts) (*url.URL, error) {
+	state := authorizationState{
+		Nonce: randomString(32),
+		State: opts.State,
+	}
+	opts.State = state.Nonce
+
+	if h.opts.PKCE {
+		pkce, err := common.NewPKCE()
+		if err != nil {
+			return nil, err
+		}
+		state.CodeVerifier = pkce.CodeVerifier
+		opts.CodeChallenge = pkce.CodeChallenge
+		opts.CodeChallengeMethod = pkce.CodeChallengeMethod
+	}
+
+	u, err := h.opts.Client.GetAuthorizationURL(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := json.Marshal(state)
+	if err != nil {
+		return nil, err
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     h.opts.StateCookieName,
+		Value:    seal(h.aead, h.opts.StateCookieName, b),
+		Path:     "/",
+		MaxAge:   int(h.opts.StateMaxAge / time.Second),
+		Secure:   !h.opts.InsecureCookie,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+	return u, nil
+}
+
+// ServeHTTP handles the callback of an authorization request.
+//
+// The state of the callback is verified before anything else, including the
+// errors returned by WorkOS, so that forged callbacks are rejected with
+// ErrInvalidState and do not consume the pending authorization request.
+func (h *CallbackHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	state, err := h.readState(r)
+	if err != nil || subtle.ConstantTimeCompare([]byte(state.Nonce), []byte(q.Get("state"))) != 1 {
+		h.opts.OnError(w, r, ErrInvalidState)
+		return
+	}
+	h.clearState(w)
+
+	if code := q.Get("error"); code != "" {
+		h.opts.OnError(w, r, AuthorizationError{
+			Code:        code,
+			Description: q.Get("error_description"),
+		})
+		return
+	}
+
+	code := q.Get("code")
+	if code == "" {
+		h.opts.OnError(w, r, AuthorizationError{
+			Code:        "invalid_request",
+			Description: "missing code",
+		})
+		return
+	}
+
+	res, err := h.opts.Client.AuthenticateWithCode(r.Context(), AuthenticateWithCodeOpts{
+		Code:         code,
+		CodeVerifier: state.CodeVerifier,
+		IPAddress:    clientIP(r),
+		UserAgent:    r.UserAgent(),
+	})
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	if h.opts.Sessions != nil {
+		if err = h.opts.Sessions.SetSession(w, res); err != nil {
+			h.opts.OnError(w, r, err)
+			return
+		}
+	}
+
+	h.opts.OnSuccess(w, r, res, state.State)
+}
+
+// handleError sends the errors that require the User to take further steps
+// to their dedicated callbacks.
+func (h *CallbackHandler) handleError(w http.ResponseWriter, r *http.Request, err error) {
+	var (
+		mfaChallenge          *workos_errors.ErrorMFAChallenge
+		mfaEnrollment         *workos_errors.ErrorMFAEnrollment
+		emailVerification     *workos_errors.ErrorEmailVerificationRequired
+		organizationSelection *workos_errors.ErrorOrganizationSelectionRequired
+	)
+
+	switch {
+	case h.opts.OnMFAChallenge != nil && errors.As(err, &mfaChallenge):
+		h.opts.OnMFAChallenge(w, r, mfaChallenge)
+	case h.opts.OnMFAEnrollment != nil && errors.As(err, &mfaEnrollment):
+		h.opts.OnMFAEnrollment(w, r, mfaEnrollment)
+	case h.opts.OnEmailVerificationRequired != nil && errors.As(err, &emailVerification):
+		h.opts.OnEmailVerificationRequired(w, r, emailVerification)
+	case h.opts.OnOrganizationSelectionRequired != nil && errors.As(err, &organizationSelection):
+		h.opts.OnOrganizationSelectionRequired(w, r, organizationSelection)
+	default:
+		h.opts.OnError(w, r, err)
+	}
+}
+
+func (h *CallbackHandler) readState(r *http.Request) (authorizationState, error) {
+	c, err := r.Cookie(h.opts.StateCookieName)
+	if err != nil {
+		return authorizationState{}, err
+	}
+
+	b, err := unseal(h.aead, h.opts.StateCookieName, c.Value)
+	if err != nil {
+		return authorizationState{}, err
+	}
+
+	var state authorizationState
+	err = json.Unmarshal(b, &state)
+	return state, err
+}
+
+func (h *CallbackHandler) clearState(w http.ResponseWriter) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     h.opts.StateCookieName,
+		Value:    "",
+		Path:     "/",
+		MaxAge:   -1,
+		Secure:   !h.opts.InsecureCookie,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// randomString returns a random URL-safe string encoding n random bytes.
+func randomString(n int) string {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
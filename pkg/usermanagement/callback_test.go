@@ -0,0 +1,313 @@
+package usermanagement
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/omi-lab/workos-go/v4/pkg/common"
+	"github.com/omi-lab/workos-go/v4/pkg/models"
+	"github.com/omi-lab/workos-go/v4/pkg/workos_errors"
+)
+
+// callbackTestServer fakes the code exchange of the authorization code flow.
+func callbackTestServer(t *testing.T, received *AuthenticateWithCodeOpts) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/user_management/authenticate" {
+			http.NotFound(w, r)
+			return
+		}
+
+		var body struct {
+			AuthenticateWithCodeOpts
+			GrantType string `json:"grant_type"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		require.Equal(t, "authorization_code", body.GrantType)
+		*received = body.AuthenticateWithCodeOpts
+
+		w.Header().Set("Content-Type", "application/json")
+		switch body.Code {
+		case "code_mfa":
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"code": "mfa_challenge", "message": "The user must complete an MFA challenge.", "pending_authentication_token": "pending_01", "authentication_factors": [{"id": "factor_01", "type": "totp"}], "user": {"id": "user_01"}}`))
+		case "code_invalid":
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error": "invalid_grant", "error_description": "The code is invalid."}`))
+		default:
+			json.NewEncoder(w).Encode(AuthenticateResponse{
+				User:         models.User{ID: "user_01"},
+				AccessToken:  "access_token",
+				RefreshToken: "refresh_token",
+			})
+		}
+	}))
+}
+
+type callbackResult struct {
+	res   *AuthenticateResponse
+	state string
+	err   error
+	mfa   *workos_errors.ErrorMFAChallenge
+}
+
+func newTestCallbackHandler(t *testing.T, server *httptest.Server, result *callbackResult) *CallbackHandler {
+	client := NewClient("test")
+	client.ClientID = testClientID
+	client.Endpoint = server.URL
+
+	h, err := NewCallbackHandler(CallbackHandlerOpts{
+		Client:   client,
+		StateKey: []byte("0123456789abcdef0123456789abcdef"),
+		OnSuccess: func(w http.ResponseWriter, r *http.Request, res AuthenticateResponse, state string) {
+			result.res = &res
+			result.state = state
+		},
+		OnError: func(w http.ResponseWriter, r *http.Request, err error) {
+			result.err = err
+			w.WriteHeader(http.StatusBadRequest)
+		},
+		OnMFAChallenge: func(w http.ResponseWriter, r *http.Request, err *workos_errors.ErrorMFAChallenge) {
+			result.mfa = err
+		},
+	})
+	require.NoError(t, err)
+	return h
+}
+
+// authorize starts an authorization request and returns the state sent to
+// WorkOS along with the state cookie.
+func authorize(t *testing.T, h *CallbackHandler, state string) (string, *http.Cookie) {
+	rec := httptest.NewRecorder()
+	u, err := h.AuthorizationURL(rec, GetAuthorizationURLOpts{
+		RedirectURI: "https://example.com/callback",
+		Provider:    "authkit",
+		State:       state,
+	})
+	require.NoError(t, err)
+
+	cookies := rec.Result().Cookies()
+	require.Len(t, cookies, 1)
+	require.True(t, cookies[0].HttpOnly)
+	require.True(t, cookies[0].Secure)
+	require.NotEmpty(t, u.Query().Get("state"))
+	require.NotEqual(t, state, u.Query().Get("state"))
+	return u.Query().Get("state"), cookies[0]
+}
+
+func callback(h *CallbackHandler, query url.Values, cookie *http.Cookie) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/callback?"+query.Encode(), nil)
+	req.Header.Set("User-Agent", "Mozilla/5.0")
+	req.RemoteAddr = "192.0.2.1:1234"
+	if cookie != nil {
+		req.AddCookie(cookie)
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestCallbackHandler(t *testing.T) {
+	var received AuthenticateWithCodeOpts
+	server := callbackTestServer(t, &received)
+	defer server.Close()
+
+	var result callbackResult
+	h := newTestCallbackHandler(t, server, &result)
+
+	state, cookie := authorize(t, h, "/dashboard")
+	rec := callback(h, url.Values{"code": {"code_01"}, "state": {state}}, cookie)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NoError(t, result.err)
+	require.NotNil(t, result.res)
+	require.Equal(t, "user_01", result.res.User.ID)
+	require.Equal(t, "/dashboard", result.state)
+	require.Equal(t, AuthenticateWithCodeOpts{
+		ClientID:  testClientID,
+		Code:      "code_01",
+		IPAddress: "192.0.2.1",
+		UserAgent: "Mozilla/5.0",
+	}, received)
+
+	cookies := rec.Result().Cookies()
+	require.Len(t, cookies, 1)
+	require.Equal(t, "wos-auth-state", cookies[0].Name)
+	require.Equal(t, -1, cookies[0].MaxAge)
+}
+
+func TestCallbackHandlerRejectsInvalidStates(t *testing.T) {
+	var received AuthenticateWithCodeOpts
+	server := callbackTestServer(t, &received)
+	defer server.Close()
+
+	var result callbackResult
+	h := newTestCallbackHandler(t, server, &result)
+	state, cookie := authorize(t, h, "")
+
+	tampered := *cookie
+	tampered.Value = cookie.Value[:len(cookie.Value)-2] + "AA"
+
+	tests := []struct {
+		scenario string
+		state    string
+		query    url.Values
+		cookie   *http.Cookie
+	}{
+		{
+			scenario: "without cookie",
+			state:    state,
+		},
+		{
+			scenario: "with another state",
+			state:    "other_state",
+			cookie:   cookie,
+		},
+		{
+			scenario: "with a tampered cookie",
+			state:    state,
+			cookie:   &tampered,
+		},
+		{
+			scenario: "with a forged error",
+			query:    url.Values{"error": {"access_denied"}, "state": {"other_state"}},
+			cookie:   cookie,
+		},
+		{
+			scenario: "with an error without cookie",
+			query:    url.Values{"error": {"access_denied"}, "state": {state}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			result = callbackResult{}
+			query := test.query
+			if query == nil {
+				query = url.Values{"code": {"code_01"}, "state": {test.state}}
+			}
+			rec := callback(h, query, test.cookie)
+
+			require.Equal(t, http.StatusBadRequest, rec.Code)
+			require.Equal(t, ErrInvalidState, result.err)
+			require.Nil(t, result.res)
+			require.Empty(t, rec.Result().Cookies())
+		})
+	}
+	require.Empty(t, received.Code)
+
+	// The pending authorization request is still valid.
+	result = callbackResult{}
+	callback(h, url.Values{"code": {"code_01"}, "state": {state}}, cookie)
+	require.NoError(t, result.err)
+	require.NotNil(t, result.res)
+}
+
+func TestNewCallbackHandlerWithInvalidStateKey(t *testing.T) {
+	_, err := NewCallbackHandler(CallbackHandlerOpts{
+		Client:    NewClient("test"),
+		StateKey:  []byte("short"),
+		OnSuccess: func(w http.ResponseWriter, r *http.Request, res AuthenticateResponse, state string) {},
+	})
+	require.Error(t, err)
+}
+
+func TestCallbackHandlerErrors(t *testing.T) {
+	var received AuthenticateWithCodeOpts
+	server := callbackTestServer(t, &received)
+	defer server.Close()
+
+	var result callbackResult
+	h := newTestCallbackHandler(t, server, &result)
+
+	t.Run("authorization denied", func(t *testing.T) {
+		result = callbackResult{}
+		state, cookie := authorize(t, h, "")
+		callback(h, url.Values{
+			"error":             {"access_denied"},
+			"error_description": {"The user denied the request."},
+			"state":             {state},
+		}, cookie)
+
+		require.Equal(t, AuthorizationError{
+			Code:        "access_denied",
+			Description: "The user denied the request.",
+		}, result.err)
+	})
+
+	t.Run("MFA challenge", func(t *testing.T) {
+		result = callbackResult{}
+		state, cookie := authorize(t, h, "")
+		callback(h, url.Values{"code": {"code_mfa"}, "state": {state}}, cookie)
+
+		require.NoError(t, result.err)
+		require.NotNil(t, result.mfa)
+		require.Equal(t, "pending_01", result.mfa.PendingAuthenticationToken)
+		require.Equal(t, "user_01", result.mfa.User.ID)
+	})
+
+	t.Run("invalid code", func(t *testing.T) {
+		result = callbackResult{}
+		state, cookie := authorize(t, h, "")
+		callback(h, url.Values{"code": {"code_invalid"}, "state": {state}}, cookie)
+
+		require.True(t, workos_errors.IsBadRequest(result.err), "unexpected error: %v", result.err)
+		require.Nil(t, result.res)
+	})
+}
+
+func TestCallbackHandlerStartsSessions(t *testing.T) {
+	var received AuthenticateWithCodeOpts
+	server := callbackTestServer(t, &received)
+	defer server.Close()
+
+	var result callbackResult
+	h := newTestCallbackHandler(t, server, &result)
+	h.opts.Sessions = &SessionManager{
+		Client:    h.opts.Client,
+		CookieKey: []byte("0123456789abcdef0123456789abcdef"),
+	}
+
+	state, cookie := authorize(t, h, "")
+	rec := callback(h, url.Values{"code": {"code_01"}, "state": {state}}, cookie)
+	require.NotNil(t, result.res)
+
+	var names []string
+	for _, c := range rec.Result().Cookies() {
+		names = append(names, c.Name)
+	}
+	require.Equal(t, []string{"wos-auth-state", "wos-session"}, names)
+}
+
+func TestCallbackHandlerWithPKCE(t *testing.T) {
+	var received AuthenticateWithCodeOpts
+	server := callbackTestServer(t, &received)
+	defer server.Close()
+
+	var result callbackResult
+	h := newTestCallbackHandler(t, server, &result)
+	h.opts.Client.APIKey = ""
+	h.opts.PKCE = true
+
+	rec := httptest.NewRecorder()
+	u, err := h.AuthorizationURL(rec, GetAuthorizationURLOpts{
+		RedirectURI: "https://example.com/callback",
+		Provider:    "authkit",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "S256", u.Query().Get("code_challenge_method"))
+
+	cookie := rec.Result().Cookies()[0]
+	callback(h, url.Values{"code": {"code_01"}, "state": {u.Query().Get("state")}}, cookie)
+	require.NotNil(t, result.res)
+	require.NotEmpty(t, received.CodeVerifier)
+	require.NotContains(t, cookie.Value, received.CodeVerifier)
+	require.NotContains(t, cookie.Value, base64.RawURLEncoding.EncodeToString([]byte(received.CodeVerifier)))
+	require.Equal(t, u.Query().Get("code_challenge"), common.CodeChallengeS256(received.CodeVerifier))
+}
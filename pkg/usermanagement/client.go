@@ -13,6 +13,7 @@ import (
 	"github.com/google/go-querystring/query"
 	"github.com/omi-lab/workos-go/v4/internal/workos"
 	"github.com/omi-lab/workos-go/v4/pkg/common"
+	"github.com/omi-lab/workos-go/v4/pkg/mfa"
 	"github.com/omi-lab/workos-go/v4/pkg/models"
 	"github.com/omi-lab/workos-go/v4/pkg/workos_errors"
 )
@@ -115,10 +116,11 @@ type AuthenticateWithPasswordOpts struct {
 }
 
 type AuthenticateWithCodeOpts struct {
-	ClientID  string `json:"client_id"`
-	Code      string `json:"code"`
-	IPAddress string `json:"ip_address,omitempty"`
-	UserAgent string `json:"user_agent,omitempty"`
+	ClientID     string `json:"client_id"`
+	Code         string `json:"code"`
+	CodeVerifier string `json:"code_verifier,omitempty"`
+	IPAddress    string `json:"ip_address,omitempty"`
+	UserAgent    string `json:"user_agent,omitempty"`
 }
 
 type AuthenticateWithRefreshTokenOpts struct {
@@ -212,6 +214,9 @@ type AuthenticateResponse struct {
 }
 
 type RefreshAuthenticationResponse struct {
+	// The User the tokens were refreshed for, as they are now.
+	User models.User `json:"user"`
+
 	// The AccessToken can be validated to confirm that a user has an active session.
 	AccessToken string `json:"access_token"`
 
@@ -442,8 +447,30 @@ func NewClient(apiKey string) *Client {
 	}
 }
 
+func (c *Client) do(req *http.Request, reqOpts ...common.RequestOption) (*http.Response, error) {
+	return workos.Transport{
+		HTTPClient:  c.HTTPClient,
+		APIKey:      c.APIKey,
+		RetryPolicy: c.RetryPolicy,
+		Middlewares: c.Middlewares,
+	}.Do(req, reqOpts...)
+}
+
+// mfaClient returns an MFA Client sharing the configuration of the Client,
+// used to challenge the factors of Users.
+func (c *Client) mfaClient() *mfa.Client {
+	return &mfa.Client{
+		APIKey:      c.APIKey,
+		HTTPClient:  c.HTTPClient,
+		RetryPolicy: c.RetryPolicy,
+		Middlewares: c.Middlewares,
+		Endpoint:    c.Endpoint,
+		JSONEncode:  c.JSONEncode,
+	}
+}
+
 // GetUser returns details of an existing user
-func (c *Client) GetUser(ctx context.Context, opts GetUserOpts) (models.User, error) {
+func (c *Client) GetUser(ctx context.Context, opts GetUserOpts, reqOpts ...common.RequestOption) (models.User, error) {
 	endpoint := fmt.Sprintf(
 		"%s/user_management/users/%s",
 		c.Endpoint,
@@ -459,11 +486,8 @@ func (c *Client) GetUser(ctx context.Context, opts GetUserOpts) (models.User, er
 		return models.User{}, err
 	}
 	req = req.WithContext(ctx)
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
-	req.Header.Set("Authorization", "Bearer "+c.APIKey)
-	req.Header.Set("Content-Type", "application/json")
 
-	res, err := c.HTTPClient.Do(req)
+	res, err := c.do(req, reqOpts...)
 	if err != nil {
 		return models.User{}, err
 	}
@@ -481,7 +505,7 @@ func (c *Client) GetUser(ctx context.Context, opts GetUserOpts) (models.User, er
 }
 
 // ListUsers get a list of all of your existing users matching the criteria specified.
-func (c *Client) ListUsers(ctx context.Context, opts ListUsersOpts) (ListUsersResponse, error) {
+func (c *Client) ListUsers(ctx context.Context, opts ListUsersOpts, reqOpts ...common.RequestOption) (ListUsersResponse, error) {
 	endpoint := fmt.Sprintf(
 		"%s/user_management/users",
 		c.Endpoint,
@@ -496,9 +520,6 @@ func (c *Client) ListUsers(ctx context.Context, opts ListUsersOpts) (ListUsersRe
 		return ListUsersResponse{}, err
 	}
 	req = req.WithContext(ctx)
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
-	req.Header.Set("Authorization", "Bearer "+c.APIKey)
-	req.Header.Set("Content-Type", "application/json")
 
 	if opts.Limit == 0 {
 		opts.Limit = ResponseLimit
@@ -515,7 +536,7 @@ func (c *Client) ListUsers(ctx context.Context, opts ListUsersOpts) (ListUsersRe
 
 	req.URL.RawQuery = queryValues.Encode()
 
-	res, err := c.HTTPClient.Do(req)
+	res, err := c.do(req, reqOpts...)
 	if err != nil {
 		return ListUsersResponse{}, err
 	}
@@ -534,7 +555,7 @@ func (c *Client) ListUsers(ctx context.Context, opts ListUsersOpts) (ListUsersRe
 
 // CreateUser create a new user with email password authentication.
 // Only unmanaged users can be created directly using the User Management API.
-func (c *Client) CreateUser(ctx context.Context, opts CreateUserOpts) (models.User, error) {
+func (c *Client) CreateUser(ctx context.Context, opts CreateUserOpts, reqOpts ...common.RequestOption) (models.User, error) {
 	endpoint := fmt.Sprintf(
 		"%s/user_management/users",
 		c.Endpoint,
@@ -554,11 +575,8 @@ func (c *Client) CreateUser(ctx context.Context, opts CreateUserOpts) (models.Us
 		return models.User{}, err
 	}
 	req = req.WithContext(ctx)
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
-	req.Header.Set("Authorization", "Bearer "+c.APIKey)
-	req.Header.Set("Content-Type", "application/json")
 
-	res, err := c.HTTPClient.Do(req)
+	res, err := c.do(req, reqOpts...)
 	if err != nil {
 		return models.User{}, err
 	}
@@ -576,7 +594,7 @@ func (c *Client) CreateUser(ctx context.Context, opts CreateUserOpts) (models.Us
 }
 
 // UpdateUser updates User attributes.
-func (c *Client) UpdateUser(ctx context.Context, opts UpdateUserOpts) (models.User, error) {
+func (c *Client) UpdateUser(ctx context.Context, opts UpdateUserOpts, reqOpts ...common.RequestOption) (models.User, error) {
 	endpoint := fmt.Sprintf(
 		"%s/user_management/users/%s",
 		c.Endpoint,
@@ -597,11 +615,8 @@ func (c *Client) UpdateUser(ctx context.Context, opts UpdateUserOpts) (models.Us
 		return models.User{}, err
 	}
 	req = req.WithContext(ctx)
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
-	req.Header.Set("Authorization", "Bearer "+c.APIKey)
-	req.Header.Set("Content-Type", "application/json")
 
-	res, err := c.HTTPClient.Do(req)
+	res, err := c.do(req, reqOpts...)
 	if err != nil {
 		return models.User{}, err
 	}
@@ -619,7 +634,7 @@ func (c *Client) UpdateUser(ctx context.Context, opts UpdateUserOpts) (models.Us
 }
 
 // DeleteUser delete an existing user.
-func (c *Client) DeleteUser(ctx context.Context, opts DeleteUserOpts) error {
+func (c *Client) DeleteUser(ctx context.Context, opts DeleteUserOpts, reqOpts ...common.RequestOption) error {
 	endpoint := fmt.Sprintf(
 		"%s/user_management/users/%s",
 		c.Endpoint,
@@ -635,11 +650,8 @@ func (c *Client) DeleteUser(ctx context.Context, opts DeleteUserOpts) error {
 		return err
 	}
 	req = req.WithContext(ctx)
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
-	req.Header.Set("Authorization", "Bearer "+c.APIKey)
-	req.Header.Set("Content-Type", "application/json")
 
-	res, err := c.HTTPClient.Do(req)
+	res, err := c.do(req, reqOpts...)
 	if err != nil {
 		return err
 	}
@@ -648,7 +660,7 @@ func (c *Client) DeleteUser(ctx context.Context, opts DeleteUserOpts) error {
 	return workos_errors.TryGetHTTPError(res)
 }
 
-func (c *Client) ListIdentities(ctx context.Context, opts ListIdentitiesOpts) (ListIdentitiesResult, error) {
+func (c *Client) ListIdentities(ctx context.Context, opts ListIdentitiesOpts, reqOpts ...common.RequestOption) (ListIdentitiesResult, error) {
 	endpoint := fmt.Sprintf(
 		"%s/user_management/users/%s/identities",
 		c.Endpoint,
@@ -669,11 +681,8 @@ func (c *Client) ListIdentities(ctx context.Context, opts ListIdentitiesOpts) (L
 		return ListIdentitiesResult{}, err
 	}
 	req = req.WithContext(ctx)
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
-	req.Header.Set("Authorization", "Bearer "+c.APIKey)
-	req.Header.Set("Content-Type", "application/json")
 
-	res, err := c.HTTPClient.Do(req)
+	res, err := c.do(req, reqOpts...)
 	if err != nil {
 		return ListIdentitiesResult{}, err
 	}
@@ -695,7 +704,7 @@ func (c *Client) ListIdentities(ctx context.Context, opts ListIdentitiesOpts) (L
 type GetAuthorizationURLOpts struct {
 	// Your WorkOS Project's Client ID.
 	//
-	// REQUIRED.
+	// Defaults to the ClientID of the Client.
 	ClientID string
 
 	// The callback URL where your app redirects the user after an
@@ -729,6 +738,16 @@ type GetAuthorizationURLOpts struct {
 	// ScreenHint represents the screen to redirect the user to when the provider is Authkit.
 	// OPTIONAL.
 	ScreenHint ScreenHint
+
+	// The PKCE code challenge, for clients that can not keep a secret. Its
+	// verifier must then be passed to AuthenticateWithCode. See common.NewPKCE.
+	// OPTIONAL.
+	CodeChallenge string
+
+	// The method used to derive the CodeChallenge. Defaults to S256 when a
+	// CodeChallenge is set.
+	// OPTIONAL.
+	CodeChallengeMethod string
 }
 
 // GetAuthorizationURL generates an OAuth 2.0 authorization URL.
@@ -736,6 +755,9 @@ type GetAuthorizationURLOpts struct {
 // connection_id, organization_id, or provider.
 // These connection selectors are mutually exclusive, and exactly one must be provided.
 func (c *Client) GetAuthorizationURL(opts GetAuthorizationURLOpts) (*url.URL, error) {
+	if opts.ClientID == "" {
+		opts.ClientID = c.ClientID
+	}
 
 	query := make(url.Values, 5)
 	query.Set("client_id", opts.ClientID)
@@ -777,6 +799,17 @@ func (c *Client) GetAuthorizationURL(opts GetAuthorizationURLOpts) (*url.URL, er
 		query.Set("screen_hint", string(opts.ScreenHint))
 	}
 
+	if opts.CodeChallengeMethod != "" && opts.CodeChallenge == "" {
+		return nil, errors.New("incomplete arguments: missing CodeChallenge")
+	}
+	if opts.CodeChallenge != "" {
+		if opts.CodeChallengeMethod == "" {
+			opts.CodeChallengeMethod = common.CodeChallengeMethodS256
+		}
+		query.Set("code_challenge", opts.CodeChallenge)
+		query.Set("code_challenge_method", opts.CodeChallengeMethod)
+	}
+
 	u, err := url.ParseRequestURI(c.Endpoint + "/user_management/authorize")
 	if err != nil {
 		return nil, err
@@ -787,7 +820,11 @@ func (c *Client) GetAuthorizationURL(opts GetAuthorizationURLOpts) (*url.URL, er
 }
 
 // AuthenticateWithPassword authenticates a user with Email and Password
-func (c *Client) AuthenticateWithPassword(ctx context.Context, opts AuthenticateWithPasswordOpts) (AuthenticateResponse, error) {
+func (c *Client) AuthenticateWithPassword(ctx context.Context, opts AuthenticateWithPasswordOpts, reqOpts ...common.RequestOption) (AuthenticateResponse, error) {
+	if opts.ClientID == "" {
+		opts.ClientID = c.ClientID
+	}
+
 	payload := struct {
 		AuthenticateWithPasswordOpts
 		ClientSecret string `json:"client_secret"`
@@ -813,13 +850,11 @@ func (c *Client) AuthenticateWithPassword(ctx context.Context, opts Authenticate
 		return AuthenticateResponse{}, err
 	}
 
-	// Add headers and context to the request
+	// Add context to the request
 	req = req.WithContext(ctx)
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
-	req.Header.Set("Content-Type", "application/json")
 
 	// Execute the request
-	res, err := c.HTTPClient.Do(req)
+	res, err := c.do(req, reqOpts...)
 	if err != nil {
 		return AuthenticateResponse{}, err
 	}
@@ -837,11 +872,19 @@ func (c *Client) AuthenticateWithPassword(ctx context.Context, opts Authenticate
 	return body, err
 }
 
-// AuthenticateWithCode authenticates an OAuth user or a managed SSO user that is logging in through SSO
-func (c *Client) AuthenticateWithCode(ctx context.Context, opts AuthenticateWithCodeOpts) (AuthenticateResponse, error) {
+// AuthenticateWithCode authenticates an OAuth user or a managed SSO user that is logging in through SSO.
+// Clients without an APIKey must authenticate with the CodeVerifier of the PKCE
+// used to create the authorization URL.
+func (c *Client) AuthenticateWithCode(ctx context.Context, opts AuthenticateWithCodeOpts, reqOpts ...common.RequestOption) (AuthenticateResponse, error) {
+	if opts.ClientID == "" {
+		opts.ClientID = c.ClientID
+	}
+
+	// Public clients authenticate with PKCE or their refresh tokens alone,
+	// without an API key.
 	payload := struct {
 		AuthenticateWithCodeOpts
-		ClientSecret string `json:"client_secret"`
+		ClientSecret string `json:"client_secret,omitempty"`
 		GrantType    string `json:"grant_type"`
 	}{
 		AuthenticateWithCodeOpts: opts,
@@ -864,13 +907,11 @@ func (c *Client) AuthenticateWithCode(ctx context.Context, opts AuthenticateWith
 		return AuthenticateResponse{}, err
 	}
 
-	// Add headers and context to the request
+	// Add context to the request
 	req = req.WithContext(ctx)
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
-	req.Header.Set("Content-Type", "application/json")
 
 	// Execute the request
-	res, err := c.HTTPClient.Do(req)
+	res, err := c.do(req, reqOpts...)
 	if err != nil {
 		return AuthenticateResponse{}, err
 	}
@@ -890,10 +931,16 @@ func (c *Client) AuthenticateWithCode(ctx context.Context, opts AuthenticateWith
 
 // AuthenticateWithRefreshToken obtains a new AccessToken and RefreshToken for
 // an existing session
-func (c *Client) AuthenticateWithRefreshToken(ctx context.Context, opts AuthenticateWithRefreshTokenOpts) (RefreshAuthenticationResponse, error) {
+func (c *Client) AuthenticateWithRefreshToken(ctx context.Context, opts AuthenticateWithRefreshTokenOpts, reqOpts ...common.RequestOption) (RefreshAuthenticationResponse, error) {
+	if opts.ClientID == "" {
+		opts.ClientID = c.ClientID
+	}
+
+	// Public clients authenticate with PKCE or their refresh tokens alone,
+	// without an API key.
 	payload := struct {
 		AuthenticateWithRefreshTokenOpts
-		ClientSecret string `json:"client_secret"`
+		ClientSecret string `json:"client_secret,omitempty"`
 		GrantType    string `json:"grant_type"`
 	}{
 		AuthenticateWithRefreshTokenOpts: opts,
@@ -916,13 +963,11 @@ func (c *Client) AuthenticateWithRefreshToken(ctx context.Context, opts Authenti
 		return RefreshAuthenticationResponse{}, err
 	}
 
-	// Add headers and context to the request
+	// Add context to the request
 	req = req.WithContext(ctx)
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
-	req.Header.Set("Content-Type", "application/json")
 
 	// Execute the request
-	res, err := c.HTTPClient.Do(req)
+	res, err := c.do(req, reqOpts...)
 	if err != nil {
 		return RefreshAuthenticationResponse{}, err
 	}
@@ -942,7 +987,11 @@ func (c *Client) AuthenticateWithRefreshToken(ctx context.Context, opts Authenti
 
 // AuthenticateWithMagicAuth authenticates a user by verifying a one-time code sent to the user's email address by
 // the Magic Auth Send Code endpoint.
-func (c *Client) AuthenticateWithMagicAuth(ctx context.Context, opts AuthenticateWithMagicAuthOpts) (AuthenticateResponse, error) {
+func (c *Client) AuthenticateWithMagicAuth(ctx context.Context, opts AuthenticateWithMagicAuthOpts, reqOpts ...common.RequestOption) (AuthenticateResponse, error) {
+	if opts.ClientID == "" {
+		opts.ClientID = c.ClientID
+	}
+
 	payload := struct {
 		AuthenticateWithMagicAuthOpts
 		ClientSecret string `json:"client_secret"`
@@ -968,13 +1017,11 @@ func (c *Client) AuthenticateWithMagicAuth(ctx context.Context, opts Authenticat
 		return AuthenticateResponse{}, err
 	}
 
-	// Add headers and context to the request
+	// Add context to the request
 	req = req.WithContext(ctx)
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
-	req.Header.Set("Content-Type", "application/json")
 
 	// Execute the request
-	res, err := c.HTTPClient.Do(req)
+	res, err := c.do(req, reqOpts...)
 	if err != nil {
 		return AuthenticateResponse{}, err
 	}
@@ -993,7 +1040,11 @@ func (c *Client) AuthenticateWithMagicAuth(ctx context.Context, opts Authenticat
 }
 
 // AuthenticateWithTOTP authenticates a user by verifying a time-based one-time password (TOTP)
-func (c *Client) AuthenticateWithTOTP(ctx context.Context, opts AuthenticateWithTOTPOpts) (AuthenticateResponse, error) {
+func (c *Client) AuthenticateWithTOTP(ctx context.Context, opts AuthenticateWithTOTPOpts, reqOpts ...common.RequestOption) (AuthenticateResponse, error) {
+	if opts.ClientID == "" {
+		opts.ClientID = c.ClientID
+	}
+
 	payload := struct {
 		AuthenticateWithTOTPOpts
 		ClientSecret string `json:"client_secret"`
@@ -1019,13 +1070,11 @@ func (c *Client) AuthenticateWithTOTP(ctx context.Context, opts AuthenticateWith
 		return AuthenticateResponse{}, err
 	}
 
-	// Add headers and context to the request
+	// Add context to the request
 	req = req.WithContext(ctx)
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
-	req.Header.Set("Content-Type", "application/json")
 
 	// Execute the request
-	res, err := c.HTTPClient.Do(req)
+	res, err := c.do(req, reqOpts...)
 	if err != nil {
 		return AuthenticateResponse{}, err
 	}
@@ -1044,7 +1093,11 @@ func (c *Client) AuthenticateWithTOTP(ctx context.Context, opts AuthenticateWith
 }
 
 // AuthenticateWithEmailVerificationCode authenticates a user by verifying a code sent to their email address
-func (c *Client) AuthenticateWithEmailVerificationCode(ctx context.Context, opts AuthenticateWithEmailVerificationCodeOpts) (AuthenticateResponse, error) {
+func (c *Client) AuthenticateWithEmailVerificationCode(ctx context.Context, opts AuthenticateWithEmailVerificationCodeOpts, reqOpts ...common.RequestOption) (AuthenticateResponse, error) {
+	if opts.ClientID == "" {
+		opts.ClientID = c.ClientID
+	}
+
 	payload := struct {
 		AuthenticateWithEmailVerificationCodeOpts
 		ClientSecret string `json:"client_secret"`
@@ -1070,13 +1123,11 @@ func (c *Client) AuthenticateWithEmailVerificationCode(ctx context.Context, opts
 		return AuthenticateResponse{}, err
 	}
 
-	// Add headers and context to the request
+	// Add context to the request
 	req = req.WithContext(ctx)
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
-	req.Header.Set("Content-Type", "application/json")
 
 	// Execute the request
-	res, err := c.HTTPClient.Do(req)
+	res, err := c.do(req, reqOpts...)
 	if err != nil {
 		return AuthenticateResponse{}, err
 	}
@@ -1095,7 +1146,11 @@ func (c *Client) AuthenticateWithEmailVerificationCode(ctx context.Context, opts
 }
 
 // AuthenticateWithOrganizationSelection completes authentication for a user given an organization they've selected.
-func (c *Client) AuthenticateWithOrganizationSelection(ctx context.Context, opts AuthenticateWithOrganizationSelectionOpts) (AuthenticateResponse, error) {
+func (c *Client) AuthenticateWithOrganizationSelection(ctx context.Context, opts AuthenticateWithOrganizationSelectionOpts, reqOpts ...common.RequestOption) (AuthenticateResponse, error) {
+	if opts.ClientID == "" {
+		opts.ClientID = c.ClientID
+	}
+
 	payload := struct {
 		AuthenticateWithOrganizationSelectionOpts
 		ClientSecret string `json:"client_secret"`
@@ -1121,13 +1176,11 @@ func (c *Client) AuthenticateWithOrganizationSelection(ctx context.Context, opts
 		return AuthenticateResponse{}, err
 	}
 
-	// Add headers and context to the request
+	// Add context to the request
 	req = req.WithContext(ctx)
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
-	req.Header.Set("Content-Type", "application/json")
 
 	// Execute the request
-	res, err := c.HTTPClient.Do(req)
+	res, err := c.do(req, reqOpts...)
 	if err != nil {
 		return AuthenticateResponse{}, err
 	}
@@ -1146,7 +1199,7 @@ func (c *Client) AuthenticateWithOrganizationSelection(ctx context.Context, opts
 }
 
 // GetEmailVerification fetches an EmailVerification object by its ID.
-func (c *Client) GetEmailVerification(ctx context.Context, opts GetEmailVerificationOpts) (models.EmailVerification, error) {
+func (c *Client) GetEmailVerification(ctx context.Context, opts GetEmailVerificationOpts, reqOpts ...common.RequestOption) (models.EmailVerification, error) {
 	endpoint := fmt.Sprintf("%s/user_management/email_verification/%s", c.Endpoint, opts.EmailVerification)
 
 	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
@@ -1154,11 +1207,8 @@ func (c *Client) GetEmailVerification(ctx context.Context, opts GetEmailVerifica
 		return models.EmailVerification{}, err
 	}
 	req = req.WithContext(ctx)
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
-	req.Header.Set("Authorization", "Bearer "+c.APIKey)
-	req.Header.Set("Content-Type", "application/json")
 
-	res, err := c.HTTPClient.Do(req)
+	res, err := c.do(req, reqOpts...)
 	if err != nil {
 		return models.EmailVerification{}, err
 	}
@@ -1176,7 +1226,7 @@ func (c *Client) GetEmailVerification(ctx context.Context, opts GetEmailVerifica
 }
 
 // SendVerificationEmail creates an email verification challenge and emails verification token to user.
-func (c *Client) SendVerificationEmail(ctx context.Context, opts SendVerificationEmailOpts) (UserResponse, error) {
+func (c *Client) SendVerificationEmail(ctx context.Context, opts SendVerificationEmailOpts, reqOpts ...common.RequestOption) (UserResponse, error) {
 	endpoint := fmt.Sprintf(
 		"%s/user_management/users/%s/email_verification/send",
 		c.Endpoint,
@@ -1191,11 +1241,8 @@ func (c *Client) SendVerificationEmail(ctx context.Context, opts SendVerificatio
 		return UserResponse{}, err
 	}
 	req = req.WithContext(ctx)
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
-	req.Header.Set("Authorization", "Bearer "+c.APIKey)
-	req.Header.Set("Content-Type", "application/json")
 
-	res, err := c.HTTPClient.Do(req)
+	res, err := c.do(req, reqOpts...)
 	if err != nil {
 		return UserResponse{}, err
 	}
@@ -1213,7 +1260,7 @@ func (c *Client) SendVerificationEmail(ctx context.Context, opts SendVerificatio
 }
 
 // VerifyEmail verifies a user's email using the verification token that was sent to the user.
-func (c *Client) VerifyEmail(ctx context.Context, opts VerifyEmailOpts) (UserResponse, error) {
+func (c *Client) VerifyEmail(ctx context.Context, opts VerifyEmailOpts, reqOpts ...common.RequestOption) (UserResponse, error) {
 	endpoint := fmt.Sprintf(
 		"%s/user_management/users/%s/email_verification/confirm",
 		c.Endpoint,
@@ -1234,11 +1281,8 @@ func (c *Client) VerifyEmail(ctx context.Context, opts VerifyEmailOpts) (UserRes
 		return UserResponse{}, err
 	}
 	req = req.WithContext(ctx)
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
-	req.Header.Set("Authorization", "Bearer "+c.APIKey)
-	req.Header.Set("Content-Type", "application/json")
 
-	res, err := c.HTTPClient.Do(req)
+	res, err := c.do(req, reqOpts...)
 	if err != nil {
 		return UserResponse{}, err
 	}
@@ -1256,7 +1300,7 @@ func (c *Client) VerifyEmail(ctx context.Context, opts VerifyEmailOpts) (UserRes
 }
 
 // GetPasswordReset fetches a PasswordReset object by its ID.
-func (c *Client) GetPasswordReset(ctx context.Context, opts GetPasswordResetOpts) (models.PasswordReset, error) {
+func (c *Client) GetPasswordReset(ctx context.Context, opts GetPasswordResetOpts, reqOpts ...common.RequestOption) (models.PasswordReset, error) {
 	endpoint := fmt.Sprintf("%s/user_management/password_reset/%s", c.Endpoint, opts.PasswordReset)
 
 	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
@@ -1264,11 +1308,8 @@ func (c *Client) GetPasswordReset(ctx context.Context, opts GetPasswordResetOpts
 		return models.PasswordReset{}, err
 	}
 	req = req.WithContext(ctx)
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
-	req.Header.Set("Authorization", "Bearer "+c.APIKey)
-	req.Header.Set("Content-Type", "application/json")
 
-	res, err := c.HTTPClient.Do(req)
+	res, err := c.do(req, reqOpts...)
 	if err != nil {
 		return models.PasswordReset{}, err
 	}
@@ -1286,7 +1327,7 @@ func (c *Client) GetPasswordReset(ctx context.Context, opts GetPasswordResetOpts
 }
 
 // CreatePasswordReset creates a PasswordReset token that can be emailed to the user.
-func (c *Client) CreatePasswordReset(ctx context.Context, opts CreatePasswordResetOpts) (models.PasswordReset, error) {
+func (c *Client) CreatePasswordReset(ctx context.Context, opts CreatePasswordResetOpts, reqOpts ...common.RequestOption) (models.PasswordReset, error) {
 	endpoint := fmt.Sprintf("%s/user_management/password_reset", c.Endpoint)
 
 	data, err := json.Marshal(opts)
@@ -1303,11 +1344,8 @@ func (c *Client) CreatePasswordReset(ctx context.Context, opts CreatePasswordRes
 		return models.PasswordReset{}, err
 	}
 	req = req.WithContext(ctx)
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
-	req.Header.Set("Authorization", "Bearer "+c.APIKey)
-	req.Header.Set("Content-Type", "application/json")
 
-	res, err := c.HTTPClient.Do(req)
+	res, err := c.do(req, reqOpts...)
 	if err != nil {
 		return models.PasswordReset{}, err
 	}
@@ -1325,7 +1363,7 @@ func (c *Client) CreatePasswordReset(ctx context.Context, opts CreatePasswordRes
 }
 
 // Deprecated: Use CreatePasswordReset instead. This method will be removed in a future major version.
-func (c *Client) SendPasswordResetEmail(ctx context.Context, opts SendPasswordResetEmailOpts) error {
+func (c *Client) SendPasswordResetEmail(ctx context.Context, opts SendPasswordResetEmailOpts, reqOpts ...common.RequestOption) error {
 	endpoint := fmt.Sprintf(
 		"%s/user_management/password_reset/send",
 		c.Endpoint,
@@ -1345,11 +1383,8 @@ func (c *Client) SendPasswordResetEmail(ctx context.Context, opts SendPasswordRe
 		return err
 	}
 	req = req.WithContext(ctx)
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
-	req.Header.Set("Authorization", "Bearer "+c.APIKey)
-	req.Header.Set("Content-Type", "application/json")
 
-	res, err := c.HTTPClient.Do(req)
+	res, err := c.do(req, reqOpts...)
 	if err != nil {
 		return err
 	}
@@ -1359,7 +1394,7 @@ func (c *Client) SendPasswordResetEmail(ctx context.Context, opts SendPasswordRe
 }
 
 // ResetPassword resets user password using token that was sent to the user.
-func (c *Client) ResetPassword(ctx context.Context, opts ResetPasswordOpts) (UserResponse, error) {
+func (c *Client) ResetPassword(ctx context.Context, opts ResetPasswordOpts, reqOpts ...common.RequestOption) (UserResponse, error) {
 	endpoint := fmt.Sprintf(
 		"%s/user_management/password_reset/confirm",
 		c.Endpoint,
@@ -1379,11 +1414,8 @@ func (c *Client) ResetPassword(ctx context.Context, opts ResetPasswordOpts) (Use
 		return UserResponse{}, err
 	}
 	req = req.WithContext(ctx)
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
-	req.Header.Set("Authorization", "Bearer "+c.APIKey)
-	req.Header.Set("Content-Type", "application/json")
 
-	res, err := c.HTTPClient.Do(req)
+	res, err := c.do(req, reqOpts...)
 	if err != nil {
 		return UserResponse{}, err
 	}
@@ -1401,7 +1433,7 @@ func (c *Client) ResetPassword(ctx context.Context, opts ResetPasswordOpts) (Use
 }
 
 // GetMagicAuth fetches a Magic Auth object by its ID.
-func (c *Client) GetMagicAuth(ctx context.Context, opts GetMagicAuthOpts) (models.MagicAuth, error) {
+func (c *Client) GetMagicAuth(ctx context.Context, opts GetMagicAuthOpts, reqOpts ...common.RequestOption) (models.MagicAuth, error) {
 	endpoint := fmt.Sprintf("%s/user_management/magic_auth/%s", c.Endpoint, opts.MagicAuth)
 
 	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
@@ -1409,11 +1441,8 @@ func (c *Client) GetMagicAuth(ctx context.Context, opts GetMagicAuthOpts) (model
 		return models.MagicAuth{}, err
 	}
 	req = req.WithContext(ctx)
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
-	req.Header.Set("Authorization", "Bearer "+c.APIKey)
-	req.Header.Set("Content-Type", "application/json")
 
-	res, err := c.HTTPClient.Do(req)
+	res, err := c.do(req, reqOpts...)
 	if err != nil {
 		return models.MagicAuth{}, err
 	}
@@ -1431,7 +1460,7 @@ func (c *Client) GetMagicAuth(ctx context.Context, opts GetMagicAuthOpts) (model
 }
 
 // CreateMagicAuth creates a one-time Magic Auth code that can be emailed to the user.
-func (c *Client) CreateMagicAuth(ctx context.Context, opts CreateMagicAuthOpts) (models.MagicAuth, error) {
+func (c *Client) CreateMagicAuth(ctx context.Context, opts CreateMagicAuthOpts, reqOpts ...common.RequestOption) (models.MagicAuth, error) {
 	endpoint := fmt.Sprintf("%s/user_management/magic_auth", c.Endpoint)
 
 	data, err := json.Marshal(opts)
@@ -1448,11 +1477,8 @@ func (c *Client) CreateMagicAuth(ctx context.Context, opts CreateMagicAuthOpts)
 		return models.MagicAuth{}, err
 	}
 	req = req.WithContext(ctx)
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
-	req.Header.Set("Authorization", "Bearer "+c.APIKey)
-	req.Header.Set("Content-Type", "application/json")
 
-	res, err := c.HTTPClient.Do(req)
+	res, err := c.do(req, reqOpts...)
 	if err != nil {
 		return models.MagicAuth{}, err
 	}
@@ -1470,7 +1496,7 @@ func (c *Client) CreateMagicAuth(ctx context.Context, opts CreateMagicAuthOpts)
 }
 
 // Deprecated: Use CreateMagicAuth instead. This method will be removed in a future major version.
-func (c *Client) SendMagicAuthCode(ctx context.Context, opts SendMagicAuthCodeOpts) error {
+func (c *Client) SendMagicAuthCode(ctx context.Context, opts SendMagicAuthCodeOpts, reqOpts ...common.RequestOption) error {
 	endpoint := fmt.Sprintf(
 		"%s/user_management/magic_auth/send",
 		c.Endpoint,
@@ -1490,11 +1516,8 @@ func (c *Client) SendMagicAuthCode(ctx context.Context, opts SendMagicAuthCodeOp
 		return err
 	}
 	req = req.WithContext(ctx)
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
-	req.Header.Set("Authorization", "Bearer "+c.APIKey)
-	req.Header.Set("Content-Type", "application/json")
 
-	res, err := c.HTTPClient.Do(req)
+	res, err := c.do(req, reqOpts...)
 	if err != nil {
 		return err
 	}
@@ -1504,7 +1527,7 @@ func (c *Client) SendMagicAuthCode(ctx context.Context, opts SendMagicAuthCodeOp
 }
 
 // EnrollAuthFactor enrolls an authentication factor for the user.
-func (c *Client) EnrollAuthFactor(ctx context.Context, opts EnrollAuthFactorOpts) (EnrollAuthFactorResponse, error) {
+func (c *Client) EnrollAuthFactor(ctx context.Context, opts EnrollAuthFactorOpts, reqOpts ...common.RequestOption) (EnrollAuthFactorResponse, error) {
 	endpoint := fmt.Sprintf(
 		"%s/user_management/users/%s/auth_factors",
 		c.Endpoint,
@@ -1525,11 +1548,8 @@ func (c *Client) EnrollAuthFactor(ctx context.Context, opts EnrollAuthFactorOpts
 		return EnrollAuthFactorResponse{}, err
 	}
 	req = req.WithContext(ctx)
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
-	req.Header.Set("Authorization", "Bearer "+c.APIKey)
-	req.Header.Set("Content-Type", "application/json")
 
-	res, err := c.HTTPClient.Do(req)
+	res, err := c.do(req, reqOpts...)
 	if err != nil {
 		return EnrollAuthFactorResponse{}, err
 	}
@@ -1547,7 +1567,7 @@ func (c *Client) EnrollAuthFactor(ctx context.Context, opts EnrollAuthFactorOpts
 }
 
 // ListAuthFactors lists the available authentication factors for the user.
-func (c *Client) ListAuthFactors(ctx context.Context, opts ListAuthFactorsOpts) (ListAuthFactorsResponse, error) {
+func (c *Client) ListAuthFactors(ctx context.Context, opts ListAuthFactorsOpts, reqOpts ...common.RequestOption) (ListAuthFactorsResponse, error) {
 	endpoint := fmt.Sprintf(
 		"%s/user_management/users/%s/auth_factors",
 		c.Endpoint,
@@ -1563,11 +1583,8 @@ func (c *Client) ListAuthFactors(ctx context.Context, opts ListAuthFactorsOpts)
 		return ListAuthFactorsResponse{}, err
 	}
 	req = req.WithContext(ctx)
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
-	req.Header.Set("Authorization", "Bearer "+c.APIKey)
-	req.Header.Set("Content-Type", "application/json")
 
-	res, err := c.HTTPClient.Do(req)
+	res, err := c.do(req, reqOpts...)
 	if err != nil {
 		return ListAuthFactorsResponse{}, err
 	}
@@ -1585,7 +1602,7 @@ func (c *Client) ListAuthFactors(ctx context.Context, opts ListAuthFactorsOpts)
 }
 
 // GetOrganizationMembership returns details of an existing Organization Membership
-func (c *Client) GetOrganizationMembership(ctx context.Context, opts GetOrganizationMembershipOpts) (models.OrganizationMembership, error) {
+func (c *Client) GetOrganizationMembership(ctx context.Context, opts GetOrganizationMembershipOpts, reqOpts ...common.RequestOption) (models.OrganizationMembership, error) {
 	endpoint := fmt.Sprintf(
 		"%s/user_management/organization_memberships/%s",
 		c.Endpoint,
@@ -1601,11 +1618,8 @@ func (c *Client) GetOrganizationMembership(ctx context.Context, opts GetOrganiza
 		return models.OrganizationMembership{}, err
 	}
 	req = req.WithContext(ctx)
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
-	req.Header.Set("Authorization", "Bearer "+c.APIKey)
-	req.Header.Set("Content-Type", "application/json")
 
-	res, err := c.HTTPClient.Do(req)
+	res, err := c.do(req, reqOpts...)
 	if err != nil {
 		return models.OrganizationMembership{}, err
 	}
@@ -1623,7 +1637,7 @@ func (c *Client) GetOrganizationMembership(ctx context.Context, opts GetOrganiza
 }
 
 // List Organization Memberships matching the criteria specified.
-func (c *Client) ListOrganizationMemberships(ctx context.Context, opts ListOrganizationMembershipsOpts) (ListOrganizationMembershipsResponse, error) {
+func (c *Client) ListOrganizationMemberships(ctx context.Context, opts ListOrganizationMembershipsOpts, reqOpts ...common.RequestOption) (ListOrganizationMembershipsResponse, error) {
 	endpoint := fmt.Sprintf(
 		"%s/user_management/organization_memberships",
 		c.Endpoint,
@@ -1638,9 +1652,6 @@ func (c *Client) ListOrganizationMemberships(ctx context.Context, opts ListOrgan
 		return ListOrganizationMembershipsResponse{}, err
 	}
 	req = req.WithContext(ctx)
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
-	req.Header.Set("Authorization", "Bearer "+c.APIKey)
-	req.Header.Set("Content-Type", "application/json")
 
 	if opts.Limit == 0 {
 		opts.Limit = ResponseLimit
@@ -1657,7 +1668,7 @@ func (c *Client) ListOrganizationMemberships(ctx context.Context, opts ListOrgan
 
 	req.URL.RawQuery = queryValues.Encode()
 
-	res, err := c.HTTPClient.Do(req)
+	res, err := c.do(req, reqOpts...)
 	if err != nil {
 		return ListOrganizationMembershipsResponse{}, err
 	}
@@ -1675,7 +1686,7 @@ func (c *Client) ListOrganizationMemberships(ctx context.Context, opts ListOrgan
 }
 
 // Create an Organization Membership. Adds a User to an Organization.
-func (c *Client) CreateOrganizationMembership(ctx context.Context, opts CreateOrganizationMembershipOpts) (models.OrganizationMembership, error) {
+func (c *Client) CreateOrganizationMembership(ctx context.Context, opts CreateOrganizationMembershipOpts, reqOpts ...common.RequestOption) (models.OrganizationMembership, error) {
 	endpoint := fmt.Sprintf(
 		"%s/user_management/organization_memberships",
 		c.Endpoint,
@@ -1695,11 +1706,8 @@ func (c *Client) CreateOrganizationMembership(ctx context.Context, opts CreateOr
 		return models.OrganizationMembership{}, err
 	}
 	req = req.WithContext(ctx)
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
-	req.Header.Set("Authorization", "Bearer "+c.APIKey)
-	req.Header.Set("Content-Type", "application/json")
 
-	res, err := c.HTTPClient.Do(req)
+	res, err := c.do(req, reqOpts...)
 	if err != nil {
 		return models.OrganizationMembership{}, err
 	}
@@ -1717,7 +1725,7 @@ func (c *Client) CreateOrganizationMembership(ctx context.Context, opts CreateOr
 }
 
 // Delete an Organization Membership. Removes the membership's User from its Organization.
-func (c *Client) DeleteOrganizationMembership(ctx context.Context, opts DeleteOrganizationMembershipOpts) error {
+func (c *Client) DeleteOrganizationMembership(ctx context.Context, opts DeleteOrganizationMembershipOpts, reqOpts ...common.RequestOption) error {
 	endpoint := fmt.Sprintf(
 		"%s/user_management/organization_memberships/%s",
 		c.Endpoint,
@@ -1733,11 +1741,8 @@ func (c *Client) DeleteOrganizationMembership(ctx context.Context, opts DeleteOr
 		return err
 	}
 	req = req.WithContext(ctx)
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
-	req.Header.Set("Authorization", "Bearer "+c.APIKey)
-	req.Header.Set("Content-Type", "application/json")
 
-	res, err := c.HTTPClient.Do(req)
+	res, err := c.do(req, reqOpts...)
 	if err != nil {
 		return err
 	}
@@ -1751,6 +1756,7 @@ func (c *Client) UpdateOrganizationMembership(
 	ctx context.Context,
 	organizationMembershipId string,
 	opts UpdateOrganizationMembershipOpts,
+	reqOpts ...common.RequestOption,
 ) (models.OrganizationMembership, error) {
 	endpoint := fmt.Sprintf(
 		"%s/user_management/organization_memberships/%s",
@@ -1772,11 +1778,8 @@ func (c *Client) UpdateOrganizationMembership(
 		return models.OrganizationMembership{}, err
 	}
 	req = req.WithContext(ctx)
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
-	req.Header.Set("Authorization", "Bearer "+c.APIKey)
-	req.Header.Set("Content-Type", "application/json")
 
-	res, err := c.HTTPClient.Do(req)
+	res, err := c.do(req, reqOpts...)
 	if err != nil {
 		return models.OrganizationMembership{}, err
 	}
@@ -1794,7 +1797,7 @@ func (c *Client) UpdateOrganizationMembership(
 }
 
 // DeactivateOrganizationMembership deactivates an Organization Membership
-func (c *Client) DeactivateOrganizationMembership(ctx context.Context, opts DeactivateOrganizationMembershipOpts) (models.OrganizationMembership, error) {
+func (c *Client) DeactivateOrganizationMembership(ctx context.Context, opts DeactivateOrganizationMembershipOpts, reqOpts ...common.RequestOption) (models.OrganizationMembership, error) {
 	endpoint := fmt.Sprintf(
 		"%s/user_management/organization_memberships/%s/deactivate",
 		c.Endpoint,
@@ -1810,11 +1813,8 @@ func (c *Client) DeactivateOrganizationMembership(ctx context.Context, opts Deac
 		return models.OrganizationMembership{}, err
 	}
 	req = req.WithContext(ctx)
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
-	req.Header.Set("Authorization", "Bearer "+c.APIKey)
-	req.Header.Set("Content-Type", "application/json")
 
-	res, err := c.HTTPClient.Do(req)
+	res, err := c.do(req, reqOpts...)
 	if err != nil {
 		return models.OrganizationMembership{}, err
 	}
@@ -1832,7 +1832,7 @@ func (c *Client) DeactivateOrganizationMembership(ctx context.Context, opts Deac
 }
 
 // ReactivateOrganizationMembership reactivates an Organization Membership
-func (c *Client) ReactivateOrganizationMembership(ctx context.Context, opts ReactivateOrganizationMembershipOpts) (models.OrganizationMembership, error) {
+func (c *Client) ReactivateOrganizationMembership(ctx context.Context, opts ReactivateOrganizationMembershipOpts, reqOpts ...common.RequestOption) (models.OrganizationMembership, error) {
 	endpoint := fmt.Sprintf(
 		"%s/user_management/organization_memberships/%s/reactivate",
 		c.Endpoint,
@@ -1848,11 +1848,8 @@ func (c *Client) ReactivateOrganizationMembership(ctx context.Context, opts Reac
 		return models.OrganizationMembership{}, err
 	}
 	req = req.WithContext(ctx)
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
-	req.Header.Set("Authorization", "Bearer "+c.APIKey)
-	req.Header.Set("Content-Type", "application/json")
 
-	res, err := c.HTTPClient.Do(req)
+	res, err := c.do(req, reqOpts...)
 	if err != nil {
 		return models.OrganizationMembership{}, err
 	}
@@ -1870,7 +1867,7 @@ func (c *Client) ReactivateOrganizationMembership(ctx context.Context, opts Reac
 }
 
 // GetInvitation fetches an Invitation by its ID.
-func (c *Client) GetInvitation(ctx context.Context, opts GetInvitationOpts) (models.Invitation, error) {
+func (c *Client) GetInvitation(ctx context.Context, opts GetInvitationOpts, reqOpts ...common.RequestOption) (models.Invitation, error) {
 	endpoint := fmt.Sprintf("%s/user_management/invitations/%s", c.Endpoint, opts.Invitation)
 
 	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
@@ -1878,11 +1875,8 @@ func (c *Client) GetInvitation(ctx context.Context, opts GetInvitationOpts) (mod
 		return models.Invitation{}, err
 	}
 	req = req.WithContext(ctx)
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
-	req.Header.Set("Authorization", "Bearer "+c.APIKey)
-	req.Header.Set("Content-Type", "application/json")
 
-	res, err := c.HTTPClient.Do(req)
+	res, err := c.do(req, reqOpts...)
 	if err != nil {
 		return models.Invitation{}, err
 	}
@@ -1900,7 +1894,7 @@ func (c *Client) GetInvitation(ctx context.Context, opts GetInvitationOpts) (mod
 }
 
 // FindInvitationByToken fetches an Invitation by its token.
-func (c *Client) FindInvitationByToken(ctx context.Context, opts FindInvitationByTokenOpts) (models.Invitation, error) {
+func (c *Client) FindInvitationByToken(ctx context.Context, opts FindInvitationByTokenOpts, reqOpts ...common.RequestOption) (models.Invitation, error) {
 	endpoint := fmt.Sprintf("%s/user_management/invitations/by_token/%s", c.Endpoint, opts.InvitationToken)
 
 	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
@@ -1908,11 +1902,8 @@ func (c *Client) FindInvitationByToken(ctx context.Context, opts FindInvitationB
 		return models.Invitation{}, err
 	}
 	req = req.WithContext(ctx)
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
-	req.Header.Set("Authorization", "Bearer "+c.APIKey)
-	req.Header.Set("Content-Type", "application/json")
 
-	res, err := c.HTTPClient.Do(req)
+	res, err := c.do(req, reqOpts...)
 	if err != nil {
 		return models.Invitation{}, err
 	}
@@ -1930,7 +1921,7 @@ func (c *Client) FindInvitationByToken(ctx context.Context, opts FindInvitationB
 }
 
 // ListInvitations gets a list of all of your existing Invitations matching the criteria specified.
-func (c *Client) ListInvitations(ctx context.Context, opts ListInvitationsOpts) (ListInvitationsResponse, error) {
+func (c *Client) ListInvitations(ctx context.Context, opts ListInvitationsOpts, reqOpts ...common.RequestOption) (ListInvitationsResponse, error) {
 	endpoint := fmt.Sprintf(
 		"%s/user_management/invitations",
 		c.Endpoint,
@@ -1945,9 +1936,6 @@ func (c *Client) ListInvitations(ctx context.Context, opts ListInvitationsOpts)
 		return ListInvitationsResponse{}, err
 	}
 	req = req.WithContext(ctx)
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
-	req.Header.Set("Authorization", "Bearer "+c.APIKey)
-	req.Header.Set("Content-Type", "application/json")
 
 	if opts.Limit == 0 {
 		opts.Limit = ResponseLimit
@@ -1964,7 +1952,7 @@ func (c *Client) ListInvitations(ctx context.Context, opts ListInvitationsOpts)
 
 	req.URL.RawQuery = queryValues.Encode()
 
-	res, err := c.HTTPClient.Do(req)
+	res, err := c.do(req, reqOpts...)
 	if err != nil {
 		return ListInvitationsResponse{}, err
 	}
@@ -1981,7 +1969,7 @@ func (c *Client) ListInvitations(ctx context.Context, opts ListInvitationsOpts)
 	return body, err
 }
 
-func (c *Client) SendInvitation(ctx context.Context, opts SendInvitationOpts) (models.Invitation, error) {
+func (c *Client) SendInvitation(ctx context.Context, opts SendInvitationOpts, reqOpts ...common.RequestOption) (models.Invitation, error) {
 	endpoint := fmt.Sprintf("%s/user_management/invitations", c.Endpoint)
 
 	data, err := json.Marshal(opts)
@@ -1998,11 +1986,8 @@ func (c *Client) SendInvitation(ctx context.Context, opts SendInvitationOpts) (m
 		return models.Invitation{}, err
 	}
 	req = req.WithContext(ctx)
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
-	req.Header.Set("Authorization", "Bearer "+c.APIKey)
-	req.Header.Set("Content-Type", "application/json")
 
-	res, err := c.HTTPClient.Do(req)
+	res, err := c.do(req, reqOpts...)
 	if err != nil {
 		return models.Invitation{}, err
 	}
@@ -2019,7 +2004,7 @@ func (c *Client) SendInvitation(ctx context.Context, opts SendInvitationOpts) (m
 	return body, err
 }
 
-func (c *Client) RevokeInvitation(ctx context.Context, opts RevokeInvitationOpts) (models.Invitation, error) {
+func (c *Client) RevokeInvitation(ctx context.Context, opts RevokeInvitationOpts, reqOpts ...common.RequestOption) (models.Invitation, error) {
 	endpoint := fmt.Sprintf("%s/user_management/invitations/%s/revoke", c.Endpoint, opts.Invitation)
 
 	req, err := http.NewRequest(http.MethodPost, endpoint, nil)
@@ -2027,11 +2012,8 @@ func (c *Client) RevokeInvitation(ctx context.Context, opts RevokeInvitationOpts
 		return models.Invitation{}, err
 	}
 	req = req.WithContext(ctx)
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
-	req.Header.Set("Authorization", "Bearer "+c.APIKey)
-	req.Header.Set("Content-Type", "application/json")
 
-	res, err := c.HTTPClient.Do(req)
+	res, err := c.do(req, reqOpts...)
 	if err != nil {
 		return models.Invitation{}, err
 	}
@@ -2086,7 +2068,7 @@ func (c *Client) GetLogoutURL(opts GetLogoutURLOpts) (*url.URL, error) {
 	return u, nil
 }
 
-func (c *Client) RevokeSession(ctx context.Context, opts RevokeSessionOpts) error {
+func (c *Client) RevokeSession(ctx context.Context, opts RevokeSessionOpts, reqOpts ...common.RequestOption) error {
 	jsonData, err := json.Marshal(opts)
 	if err != nil {
 		return err
@@ -2101,11 +2083,8 @@ func (c *Client) RevokeSession(ctx context.Context, opts RevokeSessionOpts) erro
 		return err
 	}
 	req = req.WithContext(ctx)
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
-	req.Header.Set("Authorization", "Bearer "+c.APIKey)
-	req.Header.Set("Content-Type", "application/json")
 
-	res, err := c.HTTPClient.Do(req)
+	res, err := c.do(req, reqOpts...)
 	if err != nil {
 		return err
 	}
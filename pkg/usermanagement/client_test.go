@@ -684,6 +684,16 @@ func TestClientAuthorizeURL(t *testing.T) {
 			},
 			expected: "https://api.workos.com/user_management/authorize?client_id=client_123&connection_id=connection_123&login_hint=foo%40workos.com&redirect_uri=https%3A%2F%2Fexample.com%2Fsso%2Fworkos%2Fcallback&response_type=code&state=custom+state",
 		},
+		{
+			scenario: "generate url with a PKCE code challenge",
+			options: GetAuthorizationURLOpts{
+				ClientID:      "client_123",
+				Provider:      "authkit",
+				RedirectURI:   "https://example.com/sso/workos/callback",
+				CodeChallenge: "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM",
+			},
+			expected: "https://api.workos.com/user_management/authorize?client_id=client_123&code_challenge=E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM&code_challenge_method=S256&provider=authkit&redirect_uri=https%3A%2F%2Fexample.com%2Fsso%2Fworkos%2Fcallback&response_type=code",
+		},
 	}
 
 	for _, test := range tests {
@@ -722,6 +732,15 @@ func TestClientAuthorizeURLInvalidOpts(t *testing.T) {
 				ConnectionID: "connection_123",
 			},
 		},
+		{
+			scenario: "with a code challenge method but no code challenge",
+			options: GetAuthorizationURLOpts{
+				ClientID:            "client_123",
+				ConnectionID:        "connection_123",
+				RedirectURI:         "https://example.com/sso/workos/callback",
+				CodeChallengeMethod: "S256",
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -841,6 +860,23 @@ func TestAuthenticateUserWithCode(t *testing.T) {
 				},
 			},
 		},
+		{
+			scenario: "Request without API Key with a code verifier returns a User",
+			client:   NewClient(""),
+			options: AuthenticateWithCodeOpts{
+				ClientID:     "project_123",
+				Code:         "test_123",
+				CodeVerifier: "test_verifier",
+			},
+			expected: AuthenticateResponse{
+				User: models.User{
+					ID:    "testUserID",
+					Email: "employee@foo-corp.com",
+				},
+				AccessToken:  "access_token",
+				RefreshToken: "refresh_token",
+			},
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.scenario, func(t *testing.T) {
@@ -1167,6 +1203,20 @@ func authenticationResponseTestHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Public clients prove they started the authorization request with the
+	// PKCE code verifier instead of a client secret.
+	if verifier, exists := payload["code_verifier"].(string); exists && verifier == "test_verifier" && r.Header.Get("Authorization") == "" {
+		json.NewEncoder(w).Encode(AuthenticateResponse{
+			User: models.User{
+				ID:    "testUserID",
+				Email: "employee@foo-corp.com",
+			},
+			AccessToken:  "access_token",
+			RefreshToken: "refresh_token",
+		})
+		return
+	}
+
 	w.WriteHeader(http.StatusUnauthorized)
 }
 
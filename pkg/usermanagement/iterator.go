@@ -0,0 +1,164 @@
+package usermanagement
+
+import (
+	"context"
+
+	"github.com/omi-lab/workos-go/v4/internal/workos"
+	"github.com/omi-lab/workos-go/v4/pkg/common"
+	"github.com/omi-lab/workos-go/v4/pkg/models"
+)
+
+// UserIterator iterates over Users, fetching pages as needed.
+//
+//	it := client.IterateUsers(ctx, opts)
+//	for it.Next() {
+//	    v := it.Value()
+//	}
+//	if err := it.Err(); err != nil {
+//	    // Handle error.
+//	}
+type UserIterator struct {
+	pager *workos.Pager
+}
+
+// Next advances the iterator to the next User. It returns false when there
+// are no more Users, when a request fails or when the context is done.
+func (it *UserIterator) Next() bool {
+	return it.pager.Next()
+}
+
+// Value returns the current User.
+func (it *UserIterator) Value() models.User {
+	return it.pager.Value().(models.User)
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *UserIterator) Err() error {
+	return it.pager.Err()
+}
+
+// CollectAll returns the remaining Users, up to max when max is positive.
+func (it *UserIterator) CollectAll(max int) ([]models.User, error) {
+	var all []models.User
+	err := it.pager.CollectAll(max, &all)
+	return all, err
+}
+
+// IterateUsers returns an iterator over the Users matching the given
+// options.
+//
+// The iterator follows the After cursor of each page, or the Before cursor
+// when opts only sets Before.
+func (c *Client) IterateUsers(ctx context.Context, opts ListUsersOpts, reqOpts ...common.RequestOption) *UserIterator {
+	return &UserIterator{pager: workos.NewPager(ctx, opts.Before, opts.After, func(ctx context.Context, before, after string) (interface{}, common.ListMetadata, error) {
+		opts.Before, opts.After = before, after
+
+		res, err := c.ListUsers(ctx, opts, reqOpts...)
+		return res.Data, res.ListMetadata, err
+	})}
+}
+
+// OrganizationMembershipIterator iterates over Organization Memberships,
+// fetching pages as needed.
+//
+//	it := client.IterateOrganizationMemberships(ctx, opts)
+//	for it.Next() {
+//	    v := it.Value()
+//	}
+//	if err := it.Err(); err != nil {
+//	    // Handle error.
+//	}
+type OrganizationMembershipIterator struct {
+	pager *workos.Pager
+}
+
+// Next advances the iterator to the next Organization Membership. It returns
+// false when there are no more Organization Memberships, when a request
+// fails or when the context is done.
+func (it *OrganizationMembershipIterator) Next() bool {
+	return it.pager.Next()
+}
+
+// Value returns the current Organization Membership.
+func (it *OrganizationMembershipIterator) Value() models.OrganizationMembership {
+	return it.pager.Value().(models.OrganizationMembership)
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *OrganizationMembershipIterator) Err() error {
+	return it.pager.Err()
+}
+
+// CollectAll returns the remaining Organization Memberships, up to max when
+// max is positive.
+func (it *OrganizationMembershipIterator) CollectAll(max int) ([]models.OrganizationMembership, error) {
+	var all []models.OrganizationMembership
+	err := it.pager.CollectAll(max, &all)
+	return all, err
+}
+
+// IterateOrganizationMemberships returns an iterator over the Organization
+// Memberships matching the given options.
+//
+// The iterator follows the After cursor of each page, or the Before cursor
+// when opts only sets Before.
+func (c *Client) IterateOrganizationMemberships(ctx context.Context, opts ListOrganizationMembershipsOpts, reqOpts ...common.RequestOption) *OrganizationMembershipIterator {
+	return &OrganizationMembershipIterator{pager: workos.NewPager(ctx, opts.Before, opts.After, func(ctx context.Context, before, after string) (interface{}, common.ListMetadata, error) {
+		opts.Before, opts.After = before, after
+
+		res, err := c.ListOrganizationMemberships(ctx, opts, reqOpts...)
+		return res.Data, res.ListMetadata, err
+	})}
+}
+
+// InvitationIterator iterates over Invitations, fetching pages as needed.
+//
+//	it := client.IterateInvitations(ctx, opts)
+//	for it.Next() {
+//	    v := it.Value()
+//	}
+//	if err := it.Err(); err != nil {
+//	    // Handle error.
+//	}
+type InvitationIterator struct {
+	pager *workos.Pager
+}
+
+// Next advances the iterator to the next Invitation. It returns false when
+// there are no more Invitations, when a request fails or when the context is
+// done.
+func (it *InvitationIterator) Next() bool {
+	return it.pager.Next()
+}
+
+// Value returns the current Invitation.
+func (it *InvitationIterator) Value() models.Invitation {
+	return it.pager.Value().(models.Invitation)
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *InvitationIterator) Err() error {
+	return it.pager.Err()
+}
+
+// CollectAll returns the remaining Invitations, up to max when max is
+// positive.
+func (it *InvitationIterator) CollectAll(max int) ([]models.Invitation, error) {
+	var all []models.Invitation
+	err := it.pager.CollectAll(max, &all)
+	return all, err
+}
+
+// IterateInvitations returns an iterator over the Invitations matching the
+// given options.
+//
+// The iterator follows the After cursor of each page, or the Before cursor
+// when opts only sets Before.
+func (c *Client) IterateInvitations(ctx context.Context, opts ListInvitationsOpts, reqOpts ...common.RequestOption) *InvitationIterator {
+	return &InvitationIterator{pager: workos.NewPager(ctx, opts.Before, opts.After, func(ctx context.Context, before, after string) (interface{}, common.ListMetadata, error) {
+		opts.Before, opts.After = before, after
+
+		res, err := c.ListInvitations(ctx, opts, reqOpts...)
+		return res.Data, res.ListMetadata, err
+	})}
+}
@@ -0,0 +1,470 @@
+package usermanagement
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/omi-lab/workos-go/v4/pkg/models"
+	"github.com/omi-lab/workos-go/v4/pkg/workos_errors"
+)
+
+var (
+	// ErrNoSession is returned when a request has no valid session cookie.
+	ErrNoSession = errors.New("no session")
+
+	// ErrSessionCookieTooLarge is returned when a session does not fit in a
+	// cookie, which browsers would silently drop.
+	ErrSessionCookieTooLarge = errors.New("session cookie too large")
+)
+
+const (
+	// The default name of the session cookie.
+	defaultSessionCookieName = "wos-session"
+
+	// The default lifetime of the session cookie.
+	defaultSessionMaxAge = 30 * 24 * time.Hour
+
+	// The time during which the result of a refresh is reused for requests
+	// that carry the same refresh token. Refresh tokens can only be used
+	// once, so concurrent requests of a User would otherwise log them out.
+	refreshReuseWindow = 10 * time.Second
+
+	// The time a refresh can take. Refreshes are shared by concurrent
+	// requests, so they do not depend on the context of any of them.
+	refreshTimeout = 30 * time.Second
+
+	// The maximum size of a cookie accepted by browsers, name and value
+	// included.
+	maxCookieSize = 4096
+)
+
+// Session is an authenticated session. It is stored in the context of the
+// requests that go through SessionManager.Middleware.
+type Session struct {
+	// The authenticated User.
+	User models.User
+
+	// The claims of the AccessToken.
+	Claims AccessTokenClaims
+
+	// The AccessToken of the session.
+	AccessToken string
+}
+
+type sessionContextKey struct{}
+
+// SessionFromContext returns the Session stored in the context by
+// SessionManager.Middleware.
+func SessionFromContext(ctx context.Context) (Session, bool) {
+	s, ok := ctx.Value(sessionContextKey{}).(Session)
+	return s, ok
+}
+
+// sessionCookie is the content of a session cookie.
+type sessionCookie struct {
+	User         models.User `json:"user"`
+	AccessToken  string      `json:"access_token"`
+	RefreshToken string      `json:"refresh_token"`
+}
+
+// SessionManager keeps the sessions of authenticated Users in encrypted
+// cookies. Access and refresh tokens are sealed with AES-GCM, and access
+// tokens are refreshed when they expire.
+type SessionManager struct {
+	// The Client used to verify and refresh access tokens, and to revoke
+	// sessions. Its ClientID must be set.
+	//
+	// REQUIRED.
+	Client *Client
+
+	// The key used to encrypt session cookies. It must be 16, 24 or 32 bytes
+	// long to select AES-128, AES-192 or AES-256.
+	//
+	// REQUIRED.
+	CookieKey []byte
+
+	// The name of the session cookie. Defaults to wos-session.
+	CookieName string
+
+	// The domain of the session cookie. Defaults to the host of the request.
+	CookieDomain string
+
+	// The path of the session cookie. Defaults to /.
+	CookiePath string
+
+	// The lifetime of the session cookie. Defaults to 30 days.
+	CookieMaxAge time.Duration
+
+	// The SameSite attribute of the session cookie. Defaults to
+	// http.SameSiteLaxMode.
+	CookieSameSite http.SameSite
+
+	// Whether the session cookie can be sent over plain HTTP. It should only
+	// be set during development.
+	InsecureCookie bool
+
+	// The handler called by Middleware when a request is not authenticated.
+	// Defaults to replying with 401 Unauthorized.
+	Unauthenticated http.Handler
+
+	once    sync.Once
+	aead    cipher.AEAD
+	initErr error
+
+	mu         sync.Mutex
+	refreshing map[string]*refreshCall
+}
+
+// refreshCall is a refresh of an access token, shared by the requests that
+// carry the same refresh token.
+type refreshCall struct {
+	done     chan struct{}
+	res      RefreshAuthenticationResponse
+	err      error
+	finished time.Time
+}
+
+func (m *SessionManager) init() {
+	if m.CookieName == "" {
+		m.CookieName = defaultSessionCookieName
+	}
+
+	if m.CookiePath == "" {
+		m.CookiePath = "/"
+	}
+
+	if m.CookieMaxAge == 0 {
+		m.CookieMaxAge = defaultSessionMaxAge
+	}
+
+	if m.CookieSameSite == 0 {
+		m.CookieSameSite = http.SameSiteLaxMode
+	}
+
+	if m.Unauthenticated == nil {
+		m.Unauthenticated = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		})
+	}
+
+	m.refreshing = make(map[string]*refreshCall)
+	m.aead, m.initErr = newAEAD(m.CookieKey)
+}
+
+// SetSession starts a session for an authenticated User by setting the
+// session cookie. It is typically called with the response of
+// AuthenticateWithCode. ErrSessionCookieTooLarge is returned when the session
+// does not fit in a cookie.
+func (m *SessionManager) SetSession(w http.ResponseWriter, auth AuthenticateResponse) error {
+	m.once.Do(m.init)
+	if m.initErr != nil {
+		return m.initErr
+	}
+
+	return m.setCookie(w, sessionCookie{
+		User:         auth.User,
+		AccessToken:  auth.AccessToken,
+		RefreshToken: auth.RefreshToken,
+	})
+}
+
+// Authenticate returns the Session of a request. When the access token has
+// expired, it is refreshed and the session cookie is rotated. ErrNoSession is
+// returned when the request has no session cookie or when it can not be
+// decrypted.
+func (m *SessionManager) Authenticate(w http.ResponseWriter, r *http.Request) (Session, error) {
+	m.once.Do(m.init)
+	if m.initErr != nil {
+		return Session{}, m.initErr
+	}
+
+	cookie, err := m.readCookie(r)
+	if err != nil {
+		return Session{}, err
+	}
+
+	claims, err := m.Client.VerifyAccessToken(r.Context(), cookie.AccessToken)
+	if err == ErrAccessTokenExpired {
+		refreshed, err := m.refresh(r, cookie.RefreshToken)
+		if err != nil {
+			return Session{}, err
+		}
+		cookie.AccessToken = refreshed.AccessToken
+		cookie.RefreshToken = refreshed.RefreshToken
+		if refreshed.User.ID != "" {
+			cookie.User = refreshed.User
+		}
+
+		if claims, err = m.Client.VerifyAccessToken(r.Context(), cookie.AccessToken); err != nil {
+			return Session{}, err
+		}
+		if err = m.setCookie(w, cookie); err != nil {
+			return Session{}, err
+		}
+	} else if err != nil {
+		return Session{}, err
+	}
+
+	return Session{
+		User:        cookie.User,
+		Claims:      claims,
+		AccessToken: cookie.AccessToken,
+	}, nil
+}
+
+// Middleware authenticates the requests sent to the next handler and stores
+// their Session in the request context. Requests without a valid session are
+// sent to the Unauthenticated handler and their session cookie is cleared.
+// Other failures, such as WorkOS being unreachable, are replied to with 500
+// Internal Server Error without ending the session.
+func (m *SessionManager) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		session, err := m.Authenticate(w, r)
+		switch {
+		case err == ErrNoSession:
+			m.Unauthenticated.ServeHTTP(w, r)
+			return
+
+		case errors.Is(err, ErrInvalidAccessToken),
+			workos_errors.IsBadRequest(err),
+			workos_errors.IsUnauthorized(err):
+			// The session can not be recovered: the access token is not
+			// trusted or the refresh token has been revoked.
+			m.ClearSession(w)
+			m.Unauthenticated.ServeHTTP(w, r)
+			return
+
+		case err != nil:
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), sessionContextKey{}, session)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// ClearSession removes the session cookie, without revoking the session.
+func (m *SessionManager) ClearSession(w http.ResponseWriter) {
+	m.once.Do(m.init)
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     m.CookieName,
+		Value:    "",
+		Domain:   m.CookieDomain,
+		Path:     m.CookiePath,
+		MaxAge:   -1,
+		Secure:   !m.InsecureCookie,
+		HttpOnly: true,
+		SameSite: m.CookieSameSite,
+	})
+}
+
+// Logout revokes the session of a request and clears its session cookie.
+// Requests without a session are ignored.
+func (m *SessionManager) Logout(w http.ResponseWriter, r *http.Request) error {
+	_, err := m.logout(w, r)
+	return err
+}
+
+// LogoutHandler returns a handler that logs out the User with Logout and
+// redirects them to the given URL. When the URL is empty, they are redirected
+// to the logout URL of their session returned by GetLogoutURL, which also
+// ends their AuthKit session.
+func (m *SessionManager) LogoutHandler(redirectURL string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sessionID, err := m.logout(w, r)
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+
+		if redirectURL == "" && sessionID != "" {
+			u, err := m.Client.GetLogoutURL(GetLogoutURLOpts{SessionID: sessionID})
+			if err != nil {
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+				return
+			}
+			http.Redirect(w, r, u.String(), http.StatusSeeOther)
+			return
+		}
+
+		if redirectURL == "" {
+			redirectURL = "/"
+		}
+		http.Redirect(w, r, redirectURL, http.StatusSeeOther)
+	})
+}
+
+// logout revokes the session of a request, clears its session cookie and
+// returns the ID of the session, if any.
+func (m *SessionManager) logout(w http.ResponseWriter, r *http.Request) (string, error) {
+	m.once.Do(m.init)
+	if m.initErr != nil {
+		return "", m.initErr
+	}
+
+	cookie, err := m.readCookie(r)
+	if err == ErrNoSession {
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+	m.ClearSession(w)
+
+	// The session can still be revoked when its access token has expired.
+	claims, err := m.Client.VerifyAccessToken(r.Context(), cookie.AccessToken)
+	if errors.Is(err, ErrInvalidAccessToken) {
+		return "", nil
+	} else if err != nil && err != ErrAccessTokenExpired {
+		return "", err
+	}
+
+	err = m.Client.RevokeSession(r.Context(), RevokeSessionOpts{
+		SessionID: claims.SessionID,
+	})
+	return claims.SessionID, err
+}
+
+// refresh exchanges a refresh token for new tokens. Concurrent and recent
+// refreshes of the same token share their result. The refresh is not bound to
+// the context of the request that started it, so that the other requests
+// waiting for it do not fail when that one is canceled.
+func (m *SessionManager) refresh(r *http.Request, refreshToken string) (RefreshAuthenticationResponse, error) {
+	m.mu.Lock()
+	for token, call := range m.refreshing {
+		if !call.finished.IsZero() && time.Since(call.finished) > refreshReuseWindow {
+			delete(m.refreshing, token)
+		}
+	}
+
+	call, ok := m.refreshing[refreshToken]
+	if !ok {
+		call = &refreshCall{done: make(chan struct{})}
+		m.refreshing[refreshToken] = call
+	}
+	m.mu.Unlock()
+
+	if !ok {
+		ctx, cancel := context.WithTimeout(context.Background(), refreshTimeout)
+		call.res, call.err = m.Client.AuthenticateWithRefreshToken(ctx, AuthenticateWithRefreshTokenOpts{
+			RefreshToken: refreshToken,
+			IPAddress:    clientIP(r),
+			UserAgent:    r.UserAgent(),
+		})
+		cancel()
+
+		m.mu.Lock()
+		call.finished = time.Now()
+		if call.err != nil {
+			delete(m.refreshing, refreshToken)
+		}
+		m.mu.Unlock()
+		close(call.done)
+		return call.res, call.err
+	}
+
+	select {
+	case <-call.done:
+		return call.res, call.err
+	case <-r.Context().Done():
+		return RefreshAuthenticationResponse{}, r.Context().Err()
+	}
+}
+
+func (m *SessionManager) setCookie(w http.ResponseWriter, cookie sessionCookie) error {
+	b, err := json.Marshal(cookie)
+	if err != nil {
+		return err
+	}
+
+	value := seal(m.aead, m.CookieName, b)
+	if len(m.CookieName)+1+len(value) > maxCookieSize {
+		return ErrSessionCookieTooLarge
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     m.CookieName,
+		Value:    value,
+		Domain:   m.CookieDomain,
+		Path:     m.CookiePath,
+		MaxAge:   int(m.CookieMaxAge / time.Second),
+		Secure:   !m.InsecureCookie,
+		HttpOnly: true,
+		SameSite: m.CookieSameSite,
+	})
+	return nil
+}
+
+func (m *SessionManager) readCookie(r *http.Request) (sessionCookie, error) {
+	c, err := r.Cookie(m.CookieName)
+	if err != nil {
+		return sessionCookie{}, ErrNoSession
+	}
+
+	b, err := unseal(m.aead, m.CookieName, c.Value)
+	if err != nil {
+		return sessionCookie{}, ErrNoSession
+	}
+
+	var cookie sessionCookie
+	if err = json.Unmarshal(b, &cookie); err != nil {
+		return sessionCookie{}, ErrNoSession
+	}
+	return cookie, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cookie key: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal encrypts and authenticates the value of the named cookie. The name is
+// authenticated too, so that the value of a cookie can not be used as the
+// value of another one.
+func seal(aead cipher.AEAD, name string, plaintext []byte) string {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		panic(err)
+	}
+
+	sealed := aead.Seal(nonce, nonce, plaintext, []byte(name))
+	return base64.RawURLEncoding.EncodeToString(sealed)
+}
+
+// unseal decrypts a value sealed by seal.
+func unseal(aead cipher.AEAD, name, value string) ([]byte, error) {
+	sealed, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < aead.NonceSize() {
+		return nil, errors.New("sealed value too short")
+	}
+
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	return aead.Open(nil, nonce, ciphertext, []byte(name))
+}
+
+// clientIP returns the IP address of the client that sent a request.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
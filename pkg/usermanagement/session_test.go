@@ -0,0 +1,309 @@
+package usermanagement
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/omi-lab/workos-go/v4/pkg/models"
+)
+
+// sessionTestServer fakes the WorkOS endpoints used by SessionManager.
+type sessionTestServer struct {
+	*httptest.Server
+
+	signer    testSigner
+	refreshes int32
+	revoked   []string
+}
+
+func newSessionTestServer(t *testing.T) *sessionTestServer {
+	s := &sessionTestServer{signer: newTestSigner(t, "key_1")}
+
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/sso/jwks/" + testClientID:
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"keys": []map[string]string{s.signer.jwk()},
+			})
+
+		case "/user_management/authenticate":
+			atomic.AddInt32(&s.refreshes, 1)
+
+			var body struct {
+				GrantType    string `json:"grant_type"`
+				RefreshToken string `json:"refresh_token"`
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+			if body.GrantType != "refresh_token" || body.RefreshToken != "refresh_token" {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"error": "invalid_grant", "error_description": "Refresh token is invalid."}`))
+				return
+			}
+
+			json.NewEncoder(w).Encode(RefreshAuthenticationResponse{
+				User:         models.User{ID: "user_01", Email: "marcelina@example.com"},
+				AccessToken:  s.token(t, "session_01", time.Now().Add(5*time.Minute)),
+				RefreshToken: "new_refresh_token",
+			})
+
+		case "/user_management/sessions/revoke":
+			var body RevokeSessionOpts
+			json.NewDecoder(r.Body).Decode(&body)
+			s.revoked = append(s.revoked, body.SessionID)
+
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	return s
+}
+
+func (s *sessionTestServer) token(t *testing.T, sessionID string, expiresAt time.Time) string {
+	return s.signer.sign(t, nil, map[string]interface{}{
+		"iss": s.URL,
+		"sub": "user_01",
+		"sid": sessionID,
+		"iat": time.Now().Unix(),
+		"exp": expiresAt.Unix(),
+	})
+}
+
+func newTestSessionManager(server *sessionTestServer) *SessionManager {
+	client := NewClient("test")
+	client.ClientID = testClientID
+	client.Endpoint = server.URL
+
+	return &SessionManager{
+		Client:    client,
+		CookieKey: []byte("0123456789abcdef0123456789abcdef"),
+	}
+}
+
+// startSession returns the session cookie set for the given response.
+func startSession(t *testing.T, m *SessionManager, auth AuthenticateResponse) *http.Cookie {
+	rec := httptest.NewRecorder()
+	require.NoError(t, m.SetSession(rec, auth))
+
+	cookies := rec.Result().Cookies()
+	require.Len(t, cookies, 1)
+	return cookies[0]
+}
+
+func serveWithSession(m *SessionManager, cookie *http.Cookie) (*httptest.ResponseRecorder, *Session) {
+	var session *Session
+	handler := m.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s, ok := SessionFromContext(r.Context())
+		if ok {
+			session = &s
+		}
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if cookie != nil {
+		req.AddCookie(cookie)
+	}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec, session
+}
+
+func TestSessionMiddleware(t *testing.T) {
+	server := newSessionTestServer(t)
+	defer server.Close()
+
+	m := newTestSessionManager(server)
+	user := models.User{ID: "user_01", Email: "marcelina@foo-corp.com"}
+
+	cookie := startSession(t, m, AuthenticateResponse{
+		User:         user,
+		AccessToken:  server.token(t, "session_01", time.Now().Add(5*time.Minute)),
+		RefreshToken: "refresh_token",
+	})
+	require.Equal(t, "wos-session", cookie.Name)
+	require.True(t, cookie.HttpOnly)
+	require.True(t, cookie.Secure)
+	require.NotContains(t, cookie.Value, "refresh_token")
+
+	rec, session := serveWithSession(m, cookie)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NotNil(t, session)
+	require.Equal(t, user, session.User)
+	require.Equal(t, "session_01", session.Claims.SessionID)
+	require.Empty(t, rec.Result().Cookies())
+	require.Equal(t, int32(0), atomic.LoadInt32(&server.refreshes))
+}
+
+func TestSessionMiddlewareRefreshesExpiredTokens(t *testing.T) {
+	server := newSessionTestServer(t)
+	defer server.Close()
+
+	m := newTestSessionManager(server)
+	cookie := startSession(t, m, AuthenticateResponse{
+		User:         models.User{ID: "user_01"},
+		AccessToken:  server.token(t, "session_01", time.Now().Add(-time.Hour)),
+		RefreshToken: "refresh_token",
+	})
+
+	rec, session := serveWithSession(m, cookie)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NotNil(t, session)
+	require.True(t, session.Claims.ExpiresAt.After(time.Now()))
+	require.Equal(t, "marcelina@example.com", session.User.Email)
+
+	rotated := rec.Result().Cookies()
+	require.Len(t, rotated, 1)
+	require.NotEqual(t, cookie.Value, rotated[0].Value)
+
+	// A request racing with the first one still carries the old cookie. It
+	// reuses the refreshed tokens instead of spending the refresh token again.
+	rec, session = serveWithSession(m, cookie)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NotNil(t, session)
+	require.Equal(t, int32(1), atomic.LoadInt32(&server.refreshes))
+
+	rec, session = serveWithSession(m, rotated[0])
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NotNil(t, session)
+	require.Empty(t, rec.Result().Cookies())
+	require.Equal(t, int32(1), atomic.LoadInt32(&server.refreshes))
+}
+
+func TestSessionRefreshOutlivesItsRequest(t *testing.T) {
+	server := newSessionTestServer(t)
+	defer server.Close()
+
+	m := newTestSessionManager(server)
+	m.once.Do(m.init)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req := httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx)
+
+	res, err := m.refresh(req, "refresh_token")
+	require.NoError(t, err)
+	require.Equal(t, "new_refresh_token", res.RefreshToken)
+
+	// The requests that wait for the refresh get its result.
+	res, err = m.refresh(httptest.NewRequest(http.MethodGet, "/", nil), "refresh_token")
+	require.NoError(t, err)
+	require.Equal(t, "new_refresh_token", res.RefreshToken)
+	require.Equal(t, int32(1), atomic.LoadInt32(&server.refreshes))
+}
+
+func TestSessionCookieTooLarge(t *testing.T) {
+	server := newSessionTestServer(t)
+	defer server.Close()
+
+	m := newTestSessionManager(server)
+	rec := httptest.NewRecorder()
+	err := m.SetSession(rec, AuthenticateResponse{
+		User:         models.User{ID: "user_01", FirstName: strings.Repeat("a", maxCookieSize)},
+		AccessToken:  server.token(t, "session_01", time.Now().Add(5*time.Minute)),
+		RefreshToken: "refresh_token",
+	})
+	require.Equal(t, ErrSessionCookieTooLarge, err)
+	require.Empty(t, rec.Result().Cookies())
+}
+
+func TestSessionMiddlewareRejectsRequests(t *testing.T) {
+	server := newSessionTestServer(t)
+	defer server.Close()
+
+	m := newTestSessionManager(server)
+
+	t.Run("without a session", func(t *testing.T) {
+		rec, session := serveWithSession(m, nil)
+		require.Equal(t, http.StatusUnauthorized, rec.Code)
+		require.Nil(t, session)
+		require.Empty(t, rec.Result().Cookies())
+	})
+
+	t.Run("with a tampered cookie", func(t *testing.T) {
+		cookie := startSession(t, m, AuthenticateResponse{
+			AccessToken: server.token(t, "session_01", time.Now().Add(time.Minute)),
+		})
+		cookie.Value = cookie.Value[:len(cookie.Value)-2] + "AA"
+
+		rec, session := serveWithSession(m, cookie)
+		require.Equal(t, http.StatusUnauthorized, rec.Code)
+		require.Nil(t, session)
+	})
+
+	t.Run("with a revoked refresh token", func(t *testing.T) {
+		cookie := startSession(t, m, AuthenticateResponse{
+			AccessToken:  server.token(t, "session_01", time.Now().Add(-time.Hour)),
+			RefreshToken: "revoked_refresh_token",
+		})
+
+		rec, session := serveWithSession(m, cookie)
+		require.Equal(t, http.StatusUnauthorized, rec.Code)
+		require.Nil(t, session)
+
+		cookies := rec.Result().Cookies()
+		require.Len(t, cookies, 1)
+		require.Equal(t, -1, cookies[0].MaxAge)
+	})
+}
+
+func TestSessionLogout(t *testing.T) {
+	server := newSessionTestServer(t)
+	defer server.Close()
+
+	m := newTestSessionManager(server)
+	cookie := startSession(t, m, AuthenticateResponse{
+		AccessToken:  server.token(t, "session_01", time.Now().Add(-time.Hour)),
+		RefreshToken: "refresh_token",
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/logout", nil)
+	req.AddCookie(cookie)
+	rec := httptest.NewRecorder()
+	m.LogoutHandler("/signed-out").ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusSeeOther, rec.Code)
+	require.Equal(t, "/signed-out", rec.Header().Get("Location"))
+	require.Equal(t, []string{"session_01"}, server.revoked)
+
+	cookies := rec.Result().Cookies()
+	require.Len(t, cookies, 1)
+	require.Equal(t, "wos-session", cookies[0].Name)
+	require.Equal(t, -1, cookies[0].MaxAge)
+}
+
+func TestSessionManagerWithInvalidKey(t *testing.T) {
+	m := &SessionManager{
+		Client:    NewClient("test"),
+		CookieKey: []byte("too short"),
+	}
+	require.Error(t, m.SetSession(httptest.NewRecorder(), AuthenticateResponse{}))
+}
+
+func TestSessionLogoutRedirectsToWorkOS(t *testing.T) {
+	server := newSessionTestServer(t)
+	defer server.Close()
+
+	m := newTestSessionManager(server)
+	cookie := startSession(t, m, AuthenticateResponse{
+		AccessToken:  server.token(t, "session_01", time.Now().Add(time.Minute)),
+		RefreshToken: "refresh_token",
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/logout", nil)
+	req.AddCookie(cookie)
+	rec := httptest.NewRecorder()
+	m.LogoutHandler("").ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusSeeOther, rec.Code)
+	require.Equal(t, server.URL+"/user_management/sessions/logout?session_id=session_01", rec.Header().Get("Location"))
+	require.Equal(t, []string{"session_01"}, server.revoked)
+}
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/url"
 
+	"github.com/omi-lab/workos-go/v4/pkg/common"
 	"github.com/omi-lab/workos-go/v4/pkg/models"
 )
 
@@ -22,11 +23,22 @@ type Client struct {
 	// REQUIRED.
 	APIKey string
 
+	// The WorkOS Client ID (eg. client_01JG3BCPTRTSTTWQR4VSHXGWCQ). It is used
+	// by the authentication methods when their options do not set one.
+	ClientID string
+
 	// The http.Client that is used to send request to WorkOS.
 	//
 	// Defaults to http.Client.
 	HTTPClient *http.Client
 
+	// The policy used to retry requests that fail with a transient error.
+	// Defaults to common.DefaultRetryPolicy.
+	RetryPolicy *common.RetryPolicy
+
+	// The middlewares that requests are sent through, outermost first.
+	Middlewares []common.Middleware
+
 	// The endpoint to WorkOS API.
 	//
 	// Defaults to https://api.workos.com.
@@ -34,6 +46,8 @@ type Client struct {
 
 	// The function used to encode in JSON. Defaults to json.Marshal.
 	JSONEncode func(v interface{}) ([]byte, error)
+
+	jwks jwksCache
 }
 
 // SetAPIKey configures the default client that is used by the User management methods
@@ -46,40 +60,45 @@ func SetAPIKey(apiKey string) {
 func GetUser(
 	ctx context.Context,
 	opts GetUserOpts,
+	reqOpts ...common.RequestOption,
 ) (models.User, error) {
-	return DefaultClient.GetUser(ctx, opts)
+	return DefaultClient.GetUser(ctx, opts, reqOpts...)
 }
 
 // ListUsers gets a list of Users.
 func ListUsers(
 	ctx context.Context,
 	opts ListUsersOpts,
+	reqOpts ...common.RequestOption,
 ) (ListUsersResponse, error) {
-	return DefaultClient.ListUsers(ctx, opts)
+	return DefaultClient.ListUsers(ctx, opts, reqOpts...)
 }
 
 // CreateUser creates a User.
 func CreateUser(
 	ctx context.Context,
 	opts CreateUserOpts,
+	reqOpts ...common.RequestOption,
 ) (models.User, error) {
-	return DefaultClient.CreateUser(ctx, opts)
+	return DefaultClient.CreateUser(ctx, opts, reqOpts...)
 }
 
 // UpdateUser creates a User.
 func UpdateUser(
 	ctx context.Context,
 	opts UpdateUserOpts,
+	reqOpts ...common.RequestOption,
 ) (models.User, error) {
-	return DefaultClient.UpdateUser(ctx, opts)
+	return DefaultClient.UpdateUser(ctx, opts, reqOpts...)
 }
 
 // DeleteUser deletes a existing User.
 func DeleteUser(
 	ctx context.Context,
 	opts DeleteUserOpts,
+	reqOpts ...common.RequestOption,
 ) error {
-	return DefaultClient.DeleteUser(ctx, opts)
+	return DefaultClient.DeleteUser(ctx, opts, reqOpts...)
 }
 
 // GetAuthorizationURL returns an authorization url generated with the given
@@ -92,16 +111,18 @@ func GetAuthorizationURL(opts GetAuthorizationURLOpts) (*url.URL, error) {
 func AuthenticateWithPassword(
 	ctx context.Context,
 	opts AuthenticateWithPasswordOpts,
+	reqOpts ...common.RequestOption,
 ) (AuthenticateResponse, error) {
-	return DefaultClient.AuthenticateWithPassword(ctx, opts)
+	return DefaultClient.AuthenticateWithPassword(ctx, opts, reqOpts...)
 }
 
 // AuthenticateWithCode authenticates an OAuth user or a managed SSO user that is logging in through SSO
 func AuthenticateWithCode(
 	ctx context.Context,
 	opts AuthenticateWithCodeOpts,
+	reqOpts ...common.RequestOption,
 ) (AuthenticateResponse, error) {
-	return DefaultClient.AuthenticateWithCode(ctx, opts)
+	return DefaultClient.AuthenticateWithCode(ctx, opts, reqOpts...)
 }
 
 // AuthenticateWithRefreshToken obtains a new AccessToken and RefreshToken for
@@ -109,8 +130,9 @@ func AuthenticateWithCode(
 func AuthenticateWithRefreshToken(
 	ctx context.Context,
 	opts AuthenticateWithRefreshTokenOpts,
+	reqOpts ...common.RequestOption,
 ) (RefreshAuthenticationResponse, error) {
-	return DefaultClient.AuthenticateWithRefreshToken(ctx, opts)
+	return DefaultClient.AuthenticateWithRefreshToken(ctx, opts, reqOpts...)
 }
 
 // AuthenticateWithMagicAuth authenticates a user by verifying a one-time code sent to the user's email address by
@@ -118,152 +140,171 @@ func AuthenticateWithRefreshToken(
 func AuthenticateWithMagicAuth(
 	ctx context.Context,
 	opts AuthenticateWithMagicAuthOpts,
+	reqOpts ...common.RequestOption,
 ) (AuthenticateResponse, error) {
-	return DefaultClient.AuthenticateWithMagicAuth(ctx, opts)
+	return DefaultClient.AuthenticateWithMagicAuth(ctx, opts, reqOpts...)
 }
 
 // AuthenticateWithTOTP authenticates a user by verifying a time-based one-time password (TOTP)
 func AuthenticateWithTOTP(
 	ctx context.Context,
 	opts AuthenticateWithTOTPOpts,
+	reqOpts ...common.RequestOption,
 ) (AuthenticateResponse, error) {
-	return DefaultClient.AuthenticateWithTOTP(ctx, opts)
+	return DefaultClient.AuthenticateWithTOTP(ctx, opts, reqOpts...)
 }
 
 // AuthenticateWithEmailVerificationCode authenticates a user by verifying an code sent to their email address.
 func AuthenticateWithEmailVerificationCode(
 	ctx context.Context,
 	opts AuthenticateWithEmailVerificationCodeOpts,
+	reqOpts ...common.RequestOption,
 ) (AuthenticateResponse, error) {
-	return DefaultClient.AuthenticateWithEmailVerificationCode(ctx, opts)
+	return DefaultClient.AuthenticateWithEmailVerificationCode(ctx, opts, reqOpts...)
 }
 
 // AuthenticateWithOrganizationSelection completes authentication for a user given an organization they've selected.
 func AuthenticateWithOrganizationSelection(
 	ctx context.Context,
 	opts AuthenticateWithOrganizationSelectionOpts,
+	reqOpts ...common.RequestOption,
 ) (AuthenticateResponse, error) {
-	return DefaultClient.AuthenticateWithOrganizationSelection(ctx, opts)
+	return DefaultClient.AuthenticateWithOrganizationSelection(ctx, opts, reqOpts...)
 }
 
 // GetEmailVerification fetches an EmailVerification object by its ID.
 func GetEmailVerification(
 	ctx context.Context,
 	opts GetEmailVerificationOpts,
+	reqOpts ...common.RequestOption,
 ) (models.EmailVerification, error) {
-	return DefaultClient.GetEmailVerification(ctx, opts)
+	return DefaultClient.GetEmailVerification(ctx, opts, reqOpts...)
 }
 
 // SendVerificationEmail creates an email verification challenge and emails verification token to user.
 func SendVerificationEmail(
 	ctx context.Context,
 	opts SendVerificationEmailOpts,
+	reqOpts ...common.RequestOption,
 ) (UserResponse, error) {
-	return DefaultClient.SendVerificationEmail(ctx, opts)
+	return DefaultClient.SendVerificationEmail(ctx, opts, reqOpts...)
 }
 
 // VerifyEmail verifies a user's email using the verification token that was sent to the user.
 func VerifyEmail(
 	ctx context.Context,
 	opts VerifyEmailOpts,
+	reqOpts ...common.RequestOption,
 ) (UserResponse, error) {
-	return DefaultClient.VerifyEmail(ctx, opts)
+	return DefaultClient.VerifyEmail(ctx, opts, reqOpts...)
 }
 
 // GetPasswordReset fetches a Password Reset object by its ID.
 func GetPasswordReset(
 	ctx context.Context,
 	opts GetPasswordResetOpts,
+	reqOpts ...common.RequestOption,
 ) (models.PasswordReset, error) {
-	return DefaultClient.GetPasswordReset(ctx, opts)
+	return DefaultClient.GetPasswordReset(ctx, opts, reqOpts...)
 }
 
 // CreatePasswordReset creates a password reset token that can be sent to the user's email address and used to reset the password.
 func CreatePasswordReset(
 	ctx context.Context,
 	opts CreatePasswordResetOpts,
+	reqOpts ...common.RequestOption,
 ) (models.PasswordReset, error) {
-	return DefaultClient.CreatePasswordReset(ctx, opts)
+	return DefaultClient.CreatePasswordReset(ctx, opts, reqOpts...)
 }
 
 // Deprecated: Use CreatePasswordReset instead. This method will be removed in a future major version.
 func SendPasswordResetEmail(
 	ctx context.Context,
 	opts SendPasswordResetEmailOpts,
+	reqOpts ...common.RequestOption,
 ) error {
-	return DefaultClient.SendPasswordResetEmail(ctx, opts)
+	return DefaultClient.SendPasswordResetEmail(ctx, opts, reqOpts...)
 }
 
 // ResetPassword resets user password using token that was sent to the user.
 func ResetPassword(
 	ctx context.Context,
 	opts ResetPasswordOpts,
+	reqOpts ...common.RequestOption,
 ) (UserResponse, error) {
-	return DefaultClient.ResetPassword(ctx, opts)
+	return DefaultClient.ResetPassword(ctx, opts, reqOpts...)
 }
 
 // GetMagicAuth fetches a Magic Auth object by its ID.
 func GetMagicAuth(
 	ctx context.Context,
 	opts GetMagicAuthOpts,
+	reqOpts ...common.RequestOption,
 ) (models.MagicAuth, error) {
-	return DefaultClient.GetMagicAuth(ctx, opts)
+	return DefaultClient.GetMagicAuth(ctx, opts, reqOpts...)
 }
 
 // CreateMagicAuth creates a one-time code that can be sent to the user's email address and used for authentication.
 func CreateMagicAuth(
 	ctx context.Context,
 	opts CreateMagicAuthOpts,
+	reqOpts ...common.RequestOption,
 ) (models.MagicAuth, error) {
-	return DefaultClient.CreateMagicAuth(ctx, opts)
+	return DefaultClient.CreateMagicAuth(ctx, opts, reqOpts...)
 }
 
 // Deprecated: Use CreateMagicAuth instead. This method will be removed in a future major version.
 func SendMagicAuthCode(
 	ctx context.Context,
 	opts SendMagicAuthCodeOpts,
+	reqOpts ...common.RequestOption,
 ) error {
-	return DefaultClient.SendMagicAuthCode(ctx, opts)
+	return DefaultClient.SendMagicAuthCode(ctx, opts, reqOpts...)
 }
 
 // EnrollAuthFactor enrolls an authentication factor for the user.
 func EnrollAuthFactor(
 	ctx context.Context,
 	opts EnrollAuthFactorOpts,
+	reqOpts ...common.RequestOption,
 ) (EnrollAuthFactorResponse, error) {
-	return DefaultClient.EnrollAuthFactor(ctx, opts)
+	return DefaultClient.EnrollAuthFactor(ctx, opts, reqOpts...)
 }
 
 // ListAuthFactors lists the available authentication factors for the user.
 func ListAuthFactors(
 	ctx context.Context,
 	opts ListAuthFactorsOpts,
+	reqOpts ...common.RequestOption,
 ) (ListAuthFactorsResponse, error) {
-	return DefaultClient.ListAuthFactors(ctx, opts)
+	return DefaultClient.ListAuthFactors(ctx, opts, reqOpts...)
 }
 
 // GetOrganizationMembership gets an OrganizationMembership.
 func GetOrganizationMembership(
 	ctx context.Context,
 	opts GetOrganizationMembershipOpts,
+	reqOpts ...common.RequestOption,
 ) (models.OrganizationMembership, error) {
-	return DefaultClient.GetOrganizationMembership(ctx, opts)
+	return DefaultClient.GetOrganizationMembership(ctx, opts, reqOpts...)
 }
 
 // ListOrganizationMemberships gets a list of OrganizationMemberhips.
 func ListOrganizationMemberships(
 	ctx context.Context,
 	opts ListOrganizationMembershipsOpts,
+	reqOpts ...common.RequestOption,
 ) (ListOrganizationMembershipsResponse, error) {
-	return DefaultClient.ListOrganizationMemberships(ctx, opts)
+	return DefaultClient.ListOrganizationMemberships(ctx, opts, reqOpts...)
 }
 
 // CreateOrganizationMembership creates an OrganizationMembership.
 func CreateOrganizationMembership(
 	ctx context.Context,
 	opts CreateOrganizationMembershipOpts,
+	reqOpts ...common.RequestOption,
 ) (models.OrganizationMembership, error) {
-	return DefaultClient.CreateOrganizationMembership(ctx, opts)
+	return DefaultClient.CreateOrganizationMembership(ctx, opts, reqOpts...)
 }
 
 // UpdateOrganizationMembership updates an OrganizationMembership.
@@ -271,67 +312,76 @@ func UpdateOrganizationMembership(
 	ctx context.Context,
 	organizationMembershipId string,
 	opts UpdateOrganizationMembershipOpts,
+	reqOpts ...common.RequestOption,
 ) (models.OrganizationMembership, error) {
-	return DefaultClient.UpdateOrganizationMembership(ctx, organizationMembershipId, opts)
+	return DefaultClient.UpdateOrganizationMembership(ctx, organizationMembershipId, opts, reqOpts...)
 }
 
 // DeleteOrganizationMembership deletes an existing OrganizationMembership.
 func DeleteOrganizationMembership(
 	ctx context.Context,
 	opts DeleteOrganizationMembershipOpts,
+	reqOpts ...common.RequestOption,
 ) error {
-	return DefaultClient.DeleteOrganizationMembership(ctx, opts)
+	return DefaultClient.DeleteOrganizationMembership(ctx, opts, reqOpts...)
 }
 
 // DeactivateOrganizationMembership deactivates an OrganizationMembership.
 func DeactivateOrganizationMembership(
 	ctx context.Context,
 	opts DeactivateOrganizationMembershipOpts,
+	reqOpts ...common.RequestOption,
 ) (models.OrganizationMembership, error) {
-	return DefaultClient.DeactivateOrganizationMembership(ctx, opts)
+	return DefaultClient.DeactivateOrganizationMembership(ctx, opts, reqOpts...)
 }
 
 // ReactivateOrganizationMembership reactivates an OrganizationMembership.
 func ReactivateOrganizationMembership(
 	ctx context.Context,
 	opts ReactivateOrganizationMembershipOpts,
+	reqOpts ...common.RequestOption,
 ) (models.OrganizationMembership, error) {
-	return DefaultClient.ReactivateOrganizationMembership(ctx, opts)
+	return DefaultClient.ReactivateOrganizationMembership(ctx, opts, reqOpts...)
 }
 
 func GetInvitation(
 	ctx context.Context,
 	opts GetInvitationOpts,
+	reqOpts ...common.RequestOption,
 ) (models.Invitation, error) {
-	return DefaultClient.GetInvitation(ctx, opts)
+	return DefaultClient.GetInvitation(ctx, opts, reqOpts...)
 }
 
 func FindInvitationByToken(
 	ctx context.Context,
 	opts FindInvitationByTokenOpts,
+	reqOpts ...common.RequestOption,
 ) (models.Invitation, error) {
-	return DefaultClient.FindInvitationByToken(ctx, opts)
+	return DefaultClient.FindInvitationByToken(ctx, opts, reqOpts...)
 }
 
 func ListInvitations(
 	ctx context.Context,
 	opts ListInvitationsOpts,
+	reqOpts ...common.RequestOption,
 ) (ListInvitationsResponse, error) {
-	return DefaultClient.ListInvitations(ctx, opts)
+	return DefaultClient.ListInvitations(ctx, opts, reqOpts...)
 }
 
 func SendInvitation(
 	ctx context.Context,
 	opts SendInvitationOpts,
+	reqOpts ...common.RequestOption,
 ) (models.Invitation, error) {
-	return DefaultClient.SendInvitation(ctx, opts)
+	return DefaultClient.SendInvitation(ctx, opts, reqOpts...)
 }
 
 func RevokeInvitation(
 	ctx context.Context,
 	opts RevokeInvitationOpts,
+	reqOpts ...common.RequestOption,
 ) (models.Invitation, error) {
-	return DefaultClient.RevokeInvitation(ctx, opts)
+	return DefaultClient.RevokeInvitation(ctx, opts, reqOpts...)
 }
 
 func GetJWKSURL(clientID string) (*url.URL, error) {
@@ -342,6 +392,45 @@ func GetLogoutURL(opts GetLogoutURLOpts) (*url.URL, error) {
 	return DefaultClient.GetLogoutURL(opts)
 }
 
-func RevokeSession(ctx context.Context, opts RevokeSessionOpts) error {
-	return DefaultClient.RevokeSession(ctx, opts)
+func RevokeSession(ctx context.Context, opts RevokeSessionOpts, reqOpts ...common.RequestOption) error {
+	return DefaultClient.RevokeSession(ctx, opts, reqOpts...)
+}
+
+// IterateUsers returns an iterator over the Users matching the given
+// options.
+func IterateUsers(
+	ctx context.Context,
+	opts ListUsersOpts,
+	reqOpts ...common.RequestOption,
+) *UserIterator {
+	return DefaultClient.IterateUsers(ctx, opts, reqOpts...)
+}
+
+// IterateOrganizationMemberships returns an iterator over the Organization Memberships matching the given
+// options.
+func IterateOrganizationMemberships(
+	ctx context.Context,
+	opts ListOrganizationMembershipsOpts,
+	reqOpts ...common.RequestOption,
+) *OrganizationMembershipIterator {
+	return DefaultClient.IterateOrganizationMemberships(ctx, opts, reqOpts...)
+}
+
+// IterateInvitations returns an iterator over the Invitations matching the given
+// options.
+func IterateInvitations(
+	ctx context.Context,
+	opts ListInvitationsOpts,
+	reqOpts ...common.RequestOption,
+) *InvitationIterator {
+	return DefaultClient.IterateInvitations(ctx, opts, reqOpts...)
+}
+
+// VerifyAccessToken verifies an AccessToken and returns its claims.
+func VerifyAccessToken(
+	ctx context.Context,
+	token string,
+	reqOpts ...common.RequestOption,
+) (AccessTokenClaims, error) {
+	return DefaultClient.VerifyAccessToken(ctx, token, reqOpts...)
 }
@@ -12,23 +12,73 @@ import (
 
 // This represents the list of errors that could be raised when using the webhook package.
 var (
-	ErrInvalidHeader    = errors.New("webhook has invalid WorkOS header")
-	ErrNoValidSignature = errors.New("webhook had no valid signature")
-	ErrNotSigned        = errors.New("webhook has no WorkOS header")
-	ErrInvalidTimestamp = errors.New("webhook has an invalid timestamp")
-	ErrOutsideTolerance = errors.New("webhook has a timestamp that is out of tolerance")
+	ErrInvalidHeader     = errors.New("webhook has invalid WorkOS header")
+	ErrNoValidSignature  = errors.New("webhook had no valid signature")
+	ErrNotSigned         = errors.New("webhook has no WorkOS header")
+	ErrInvalidTimestamp  = errors.New("webhook has an invalid timestamp")
+	ErrOutsideTolerance  = errors.New("webhook has a timestamp that is out of tolerance")
+	ErrNoActiveSecret    = errors.New("webhook client has no active secret")
+	ErrMissingTimestamp  = errors.New("webhook has no timestamp")
+	ErrTimestampInFuture = errors.New("webhook has a timestamp in the future")
 )
 
+// Secret is a webhook endpoint secret. Endpoints can have several secrets
+// while one is being rotated.
+type Secret struct {
+	// The identifier of the secret, reported when it matches a signature.
+	ID string
+
+	// The secret shown in the WorkOS dashboard.
+	Value string
+
+	// The time from which the secret is used, if any.
+	NotBefore time.Time
+
+	// The time after which the secret is no longer used, if any.
+	NotAfter time.Time
+}
+
+// activeAt reports whether the secret is used at the given time.
+func (s Secret) activeAt(t time.Time) bool {
+	if !s.NotBefore.IsZero() && t.Before(s.NotBefore) {
+		return false
+	}
+	if !s.NotAfter.IsZero() && t.After(s.NotAfter) {
+		return false
+	}
+	return true
+}
+
+// Verification is the result of a successful webhook verification.
+type Verification struct {
+	// The secret that matched the signature of the webhook.
+	Secret Secret
+
+	// The time the webhook was sent at.
+	Timestamp time.Time
+}
+
 // The Client used to interact with Webhooks.
 type Client struct {
 	now       func() time.Time
 	tolerance time.Duration
-	secret    string
+	secrets   []Secret
 }
 
 // Constructs a new Client.
 func NewClient(secret string) *Client {
-	return &Client{now: time.Now, tolerance: 180 * time.Second, secret: secret}
+	return NewClientWithSecrets(Secret{Value: secret})
+}
+
+// NewClientWithSecrets constructs a new Client that accepts the webhooks
+// signed with any of the given secrets, during their validity window. It is
+// used to rotate the secret of an endpoint without downtime.
+func NewClientWithSecrets(secrets ...Secret) *Client {
+	return &Client{
+		now:       time.Now,
+		tolerance: 180 * time.Second,
+		secrets:   append([]Secret(nil), secrets...),
+	}
 }
 
 // Sets the function used to determine the current time. Usually you'll only
@@ -43,82 +93,125 @@ func (c *Client) SetTolerance(tolerance time.Duration) {
 	c.tolerance = tolerance
 }
 
+// signedHeader is the parsed content of a WorkOS-Signature header.
 type signedHeader struct {
-	timestamp string
-	signature string
+	rawTimestamp string
+	timestamp    time.Time
+	signatures   [][]byte
 }
 
-func parseSignatureHeader(header string) (*signedHeader, error) {
-	signedHeader := &signedHeader{}
+// parseSignatureHeader parses a header of the form "t=<ms>, v1=<hex>". The
+// parts can come in any order and there can be several v1 signatures, e.g.
+// while the secret is rotated. Parts with other keys are ignored.
+func parseSignatureHeader(header string) (signedHeader, error) {
+	var h signedHeader
 	if header == "" {
-		return signedHeader, ErrNotSigned
+		return h, ErrNotSigned
 	}
 
-	// Parse Workos-Signature
-	signatureParts := strings.Split(header, ",")
-	if len(signatureParts) != 2 {
-		return signedHeader, ErrInvalidHeader
+	for _, part := range strings.Split(header, ",") {
+		i := strings.IndexByte(part, '=')
+		if i < 0 {
+			return signedHeader{}, ErrInvalidHeader
+		}
+		key, value := strings.TrimSpace(part[:i]), strings.TrimSpace(part[i+1:])
+
+		switch key {
+		case "t":
+			if h.rawTimestamp != "" {
+				return signedHeader{}, ErrInvalidHeader
+			}
+			ms, err := strconv.ParseInt(value, 10, 64)
+			if err != nil || ms < 0 {
+				return signedHeader{}, ErrInvalidHeader
+			}
+			h.rawTimestamp = value
+			h.timestamp = time.Unix(ms/1000, (ms%1000)*int64(time.Millisecond))
+
+		case "v1":
+			// Signatures that are not hex encoded can not match, but do not
+			// invalidate the other ones.
+			if signature, err := hex.DecodeString(value); err == nil && len(signature) == sha256.Size {
+				h.signatures = append(h.signatures, signature)
+			}
+		}
 	}
 
-	// Turn the timestamp into Unix time
-	rawTimestamp := signatureParts[0][2:len(signatureParts[0])]
-	signedHeader.timestamp = rawTimestamp
-
-	// Create the signature and check that it exists
-	signedHeader.signature = signatureParts[1][4:len(signatureParts[1])]
-	if len(signedHeader.signature) == 0 {
-		return signedHeader, ErrNoValidSignature
+	if h.rawTimestamp == "" {
+		return signedHeader{}, ErrMissingTimestamp
 	}
-
-	return signedHeader, nil
-}
-
-func (c *Client) checkTimestamp(timestamp string) error {
-	intTimestamp, err := strconv.ParseInt(timestamp, 10, 64)
-	if err != nil {
-		return ErrInvalidHeader
+	if len(h.signatures) == 0 {
+		return signedHeader{}, ErrNoValidSignature
 	}
+	return h, nil
+}
 
-	formattedTime := time.Unix(intTimestamp/1000, 0)
-	currentTime := c.now().Round(0)
-
-	diff := currentTime.Sub(formattedTime)
-
-	if diff < c.tolerance {
-		return nil
-	} else {
+// checkTimestamp rejects the webhooks sent more than the tolerance ago, or
+// timestamped more than the tolerance in the future.
+func (c *Client) checkTimestamp(timestamp time.Time) error {
+	diff := c.now().Round(0).Sub(timestamp)
+	if diff >= c.tolerance {
 		return ErrInvalidTimestamp
 	}
+	if -diff >= c.tolerance {
+		return ErrTimestampInFuture
+	}
+	return nil
 }
 
-func (c *Client) checkSignature(bodyString string, rawTimestamp string, signature string) error {
-	unhashedDigest := rawTimestamp + "." + bodyString
-	hash := hmac.New(sha256.New, []byte(c.secret))
-
-	hash.Write([]byte(unhashedDigest))
-
-	digest := hex.EncodeToString(hash.Sum(nil))
+// checkSignature returns the active secret one of the signatures was
+// computed with.
+func (c *Client) checkSignature(bodyString string, rawTimestamp string, signatures [][]byte) (Secret, error) {
+	now := c.now()
+	active := false
+
+	for _, secret := range c.secrets {
+		if !secret.activeAt(now) {
+			continue
+		}
+		active = true
+
+		digest := computeSignature(secret.Value, rawTimestamp, bodyString)
+
+		for _, signature := range signatures {
+			if hmac.Equal(signature, digest) {
+				return secret, nil
+			}
+		}
+	}
 
-	if signature == digest {
-		return nil
-	} else {
-		return ErrNoValidSignature
+	if !active {
+		return Secret{}, ErrNoActiveSecret
 	}
+	return Secret{}, ErrNoValidSignature
 }
 
 func (c *Client) ValidatePayload(workosHeader string, bodyString string) (string, error) {
+	if _, err := c.Verify(workosHeader, bodyString); err != nil {
+		return "", err
+	}
+	return bodyString, nil
+}
+
+// Verify checks the signature and the timestamp of a webhook like
+// ValidatePayload, and reports which secret the webhook was signed with.
+func (c *Client) Verify(workosHeader string, bodyString string) (Verification, error) {
 	header, err := parseSignatureHeader(workosHeader)
 	if err != nil {
-		return "", err
+		return Verification{}, err
 	}
 
 	if err := c.checkTimestamp(header.timestamp); err != nil {
-		return "", err
+		return Verification{}, err
 	}
 
-	if err := c.checkSignature(bodyString, header.timestamp, header.signature); err != nil {
-		return "", err
+	secret, err := c.checkSignature(bodyString, header.rawTimestamp, header.signatures)
+	if err != nil {
+		return Verification{}, err
 	}
 
-	return bodyString, nil
+	return Verification{
+		Secret:    secret,
+		Timestamp: header.timestamp,
+	}, nil
 }
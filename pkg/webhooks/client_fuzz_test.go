@@ -0,0 +1,35 @@
+//go:build go1.18
+// +build go1.18
+
+package webhooks_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/omi-lab/workos-go/v4/pkg/webhooks"
+)
+
+func FuzzValidatePayload(f *testing.F) {
+	now := time.Unix(1700000000, 0)
+	body := "{'data': 'foobar'}"
+
+	f.Add(mockWebhookHeader(now, "secret", body), body)
+	f.Add("t=1700000000000, v1=", body)
+	f.Add("v1=00,t=1,t=2", body)
+	f.Add("t", "")
+	f.Add("=,=,", "")
+	f.Add("t=-1,v1=zz", "")
+	f.Add("t=99999999999999999999,v1=00", "")
+	f.Add(",,,", body)
+
+	client := webhooks.NewClient("secret")
+	client.SetNow(func() time.Time { return now })
+
+	f.Fuzz(func(t *testing.T, header, body string) {
+		verification, err := client.Verify(header, body)
+		if err == nil && verification.Secret.Value != "secret" {
+			t.Errorf("verified with an unexpected secret: %+v", verification)
+		}
+	})
+}
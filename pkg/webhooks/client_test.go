@@ -6,6 +6,7 @@ import (
 	"encoding/hex"
 	"github.com/omi-lab/workos-go/v4/pkg/webhooks"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 )
@@ -131,3 +132,108 @@ func mockWebhookHeader(now time.Time, secret string, body string) string {
 
 	return "t=" + stringTime + ", v1=" + expectedSignature
 }
+
+func TestWebhookWithRotatedSecrets(t *testing.T) {
+	now := time.Now()
+
+	client := webhooks.NewClientWithSecrets(
+		webhooks.Secret{ID: "old", Value: "old_secret", NotAfter: now.Add(time.Hour)},
+		webhooks.Secret{ID: "new", Value: "new_secret"},
+		webhooks.Secret{ID: "next", Value: "next_secret", NotBefore: now.Add(time.Hour)},
+	)
+	client.SetNow(func() time.Time { return now })
+
+	body := "{'data': 'foobar'}"
+	tests := []struct {
+		secret   string
+		expected string
+		err      error
+	}{
+		{secret: "old_secret", expected: "old"},
+		{secret: "new_secret", expected: "new"},
+		{secret: "next_secret", err: webhooks.ErrNoValidSignature},
+		{secret: "other_secret", err: webhooks.ErrNoValidSignature},
+	}
+
+	for _, test := range tests {
+		verification, err := client.Verify(mockWebhookHeader(now, test.secret, body), body)
+		if err != test.err {
+			t.Errorf("%s: expected error %v, but got %v", test.secret, test.err, err)
+		}
+		if verification.Secret.ID != test.expected {
+			t.Errorf("%s: expected secret %q to match, but got %q", test.secret, test.expected, verification.Secret.ID)
+		}
+		if err == nil && verification.Timestamp.Unix() != now.Unix() {
+			t.Errorf("%s: expected timestamp %v, but got %v", test.secret, now, verification.Timestamp)
+		}
+	}
+}
+
+func TestWebhookWithExpiredSecrets(t *testing.T) {
+	now := time.Now()
+
+	client := webhooks.NewClientWithSecrets(
+		webhooks.Secret{ID: "old", Value: "old_secret", NotAfter: now.Add(-time.Minute)},
+	)
+
+	body := "{'data': 'foobar'}"
+	_, err := client.Verify(mockWebhookHeader(now, "old_secret", body), body)
+	if err != webhooks.ErrNoActiveSecret {
+		t.Errorf("expected ErrNoActiveSecret, but got %v", err)
+	}
+}
+
+func TestWebhookWithTimestampInTheFuture(t *testing.T) {
+	secret := "secret"
+	now := time.Unix(1700000000, 0)
+
+	client := webhooks.NewClient(secret)
+	client.SetNow(func() time.Time { return now })
+
+	body := "{'data': 'foobar'}"
+	header := mockWebhookHeader(now.Add(time.Hour), secret, body)
+
+	_, err := client.ValidatePayload(header, body)
+	if err != webhooks.ErrTimestampInFuture {
+		t.Errorf("expected a '%s' error, but got a '%s'", webhooks.ErrTimestampInFuture, err)
+	}
+}
+
+func TestWebhookSignatureHeaderParsing(t *testing.T) {
+	secret := "secret"
+	now := time.Now()
+
+	client := webhooks.NewClient(secret)
+
+	body := "{'data': 'foobar'}"
+	valid := mockWebhookHeader(now, secret, body)
+	parts := strings.Split(valid, ", ")
+	timestamp, signature := parts[0], parts[1]
+	other := strings.Split(mockWebhookHeader(now, "other_secret", body), ", ")[1]
+
+	tests := []struct {
+		scenario string
+		header   string
+		err      error
+	}{
+		{scenario: "reordered parts", header: signature + "," + timestamp},
+		{scenario: "several signatures", header: timestamp + "," + other + "," + signature},
+		{scenario: "unknown parts", header: timestamp + ",v0=abc," + signature},
+		{scenario: "malformed signature", header: timestamp + ",v1=zz," + signature},
+		{scenario: "no header", header: "", err: webhooks.ErrNotSigned},
+		{scenario: "short parts", header: "t,v", err: webhooks.ErrInvalidHeader},
+		{scenario: "missing timestamp", header: signature, err: webhooks.ErrMissingTimestamp},
+		{scenario: "duplicated timestamp", header: timestamp + "," + timestamp + "," + signature, err: webhooks.ErrInvalidHeader},
+		{scenario: "malformed timestamp", header: "t=abc," + signature, err: webhooks.ErrInvalidHeader},
+		{scenario: "missing signature", header: timestamp, err: webhooks.ErrNoValidSignature},
+		{scenario: "empty signature", header: timestamp + ", v1=", err: webhooks.ErrNoValidSignature},
+		{scenario: "other signatures", header: timestamp + "," + other, err: webhooks.ErrNoValidSignature},
+	}
+
+	for _, test := range tests {
+		_, err := client.ValidatePayload(test.header, body)
+		if err != test.err {
+			t.Errorf("%s: expected error '%v', but got '%v'", test.scenario, test.err, err)
+		}
+	}
+}
@@ -0,0 +1,405 @@
+package webhooks
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultDedupeTTL is the default time the ID of a processed event is
+	// remembered by the dedupe stores. It covers the 3 days during which
+	// WorkOS retries a webhook.
+	DefaultDedupeTTL = 72 * time.Hour
+
+	// DefaultDedupeLease is the default time an event is claimed for while it
+	// is processed. A claim that is neither completed nor released within
+	// this time, e.g. because the handler hangs, can be claimed again.
+	DefaultDedupeLease = 5 * time.Minute
+
+	// The number of records of the file of a FileDedupeStore above which it
+	// is compacted, once most of them are expired.
+	dedupeCompactionThreshold = 100
+)
+
+// DedupeStore remembers the events processed by a Handler, so that the
+// deliveries of an event that was already processed are acknowledged without
+// being handled again. It is used with WithDedupeStore.
+//
+// An event is claimed while it is processed, then completed once it was
+// processed, or released when its processing failed.
+//
+// A DedupeStore must be safe for concurrent use.
+type DedupeStore interface {
+	// Claim records that the event with the given ID is being processed. It
+	// returns false when the event was completed, or is claimed and its
+	// claim did not expire.
+	Claim(ctx context.Context, id string) (bool, error)
+
+	// Complete records that the event with the given ID was processed, so
+	// that its next deliveries are not processed.
+	Complete(ctx context.Context, id string) error
+
+	// Release forgets an event whose processing failed, so that its next
+	// delivery is processed.
+	Release(ctx context.Context, id string) error
+}
+
+// MemoryDedupeStore is a DedupeStore that remembers event IDs in memory for a
+// limited time.
+type MemoryDedupeStore struct {
+	mu        sync.Mutex
+	ttl       time.Duration
+	lease     time.Duration
+	now       func() time.Time
+	entries   map[string]dedupeEntry
+	nextSweep time.Time
+}
+
+type dedupeEntry struct {
+	expires   time.Time
+	completed bool
+}
+
+// NewMemoryDedupeStore creates a MemoryDedupeStore that remembers the IDs of
+// the completed events for the given time. Defaults to DefaultDedupeTTL when
+// ttl is not positive. Events are claimed for DefaultDedupeLease.
+func NewMemoryDedupeStore(ttl time.Duration) *MemoryDedupeStore {
+	if ttl <= 0 {
+		ttl = DefaultDedupeTTL
+	}
+	return &MemoryDedupeStore{
+		ttl:     ttl,
+		lease:   DefaultDedupeLease,
+		now:     time.Now,
+		entries: make(map[string]dedupeEntry),
+	}
+}
+
+// Sets the function used to determine the current time. Usually you'll only
+// need to call this for testing purposes.
+func (s *MemoryDedupeStore) SetNow(now func() time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.now = now
+}
+
+// SetLease sets the time events are claimed for. It must be longer than the
+// processing of an event.
+func (s *MemoryDedupeStore) SetLease(lease time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lease = lease
+}
+
+// Claim implements DedupeStore.
+func (s *MemoryDedupeStore) Claim(ctx context.Context, id string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, ok := s.claim(id)
+	return ok, nil
+}
+
+// Complete implements DedupeStore.
+func (s *MemoryDedupeStore) Complete(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.complete(id)
+	return nil
+}
+
+// Release implements DedupeStore.
+func (s *MemoryDedupeStore) Release(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.entries, id)
+	return nil
+}
+
+// claim records that an event is being processed and returns when the claim
+// expires. It returns false when the event is completed or claimed.
+func (s *MemoryDedupeStore) claim(id string) (time.Time, bool) {
+	now := s.now()
+	s.sweep(now)
+
+	if entry, ok := s.entries[id]; ok && now.Before(entry.expires) {
+		return time.Time{}, false
+	}
+
+	expires := now.Add(s.lease)
+	s.entries[id] = dedupeEntry{expires: expires}
+	return expires, true
+}
+
+// complete records that an event was processed and returns when it is
+// forgotten.
+func (s *MemoryDedupeStore) complete(id string) time.Time {
+	expires := s.now().Add(s.ttl)
+	s.entries[id] = dedupeEntry{expires: expires, completed: true}
+	return expires
+}
+
+// sweep forgets the expired event IDs, at most twice per TTL.
+func (s *MemoryDedupeStore) sweep(now time.Time) {
+	if now.Before(s.nextSweep) {
+		return
+	}
+	for id, entry := range s.entries {
+		if !now.Before(entry.expires) {
+			delete(s.entries, id)
+		}
+	}
+	s.nextSweep = now.Add(s.ttl / 2)
+}
+
+// Settle records in a DedupeStore the outcome of the processing of an event
+// claimed with Claim, where err is the error of the processing. The event is
+// completed when it was processed or failed with an error marked with
+// Permanent, and released otherwise so that its next delivery is processed.
+// It returns err, annotated with the error of the store.
+//
+// Handlers that deduplicate events themselves, such as the ones fed by the
+// Events API, should use it so that they settle events like Handler does.
+func Settle(store DedupeStore, id string, err error) error {
+	// The request may be canceled, but the outcome must be recorded.
+	ctx := context.Background()
+
+	if err != nil && !IsPermanent(err) {
+		if releaseErr := store.Release(ctx, id); releaseErr != nil {
+			return fmt.Errorf("%w (releasing the event failed: %v)", err, releaseErr)
+		}
+		return err
+	}
+
+	if completeErr := store.Complete(ctx, id); completeErr != nil {
+		if err != nil {
+			return fmt.Errorf("%w (completing the event failed: %v)", err, completeErr)
+		}
+		return fmt.Errorf("webhooks: completing event %s failed: %w", id, completeErr)
+	}
+	return err
+}
+
+// dedupeRecord is a line of the file of a FileDedupeStore.
+type dedupeRecord struct {
+	ID        string    `json:"id"`
+	ExpiresAt time.Time `json:"expires_at"`
+	Completed bool      `json:"completed,omitempty"`
+}
+
+// FileDedupeStore is a DedupeStore that remembers the completed events in a
+// file, so that they survive restarts. The file is compacted when it is
+// opened, and when most of its records are expired.
+//
+// Claims and releases are only kept in memory: when the process stops while
+// an event is processed, e.g. because it crashed, the next delivery of the
+// event is processed.
+//
+// A file must not be used by several FileDedupeStores at the same time.
+type FileDedupeStore struct {
+	mu      sync.Mutex
+	mem     *MemoryDedupeStore
+	path    string
+	file    *os.File
+	records int
+}
+
+// NewFileDedupeStore opens the FileDedupeStore stored at the given path,
+// creating it if needed. It remembers the IDs of the completed events for the
+// given time, or DefaultDedupeTTL when ttl is not positive.
+func NewFileDedupeStore(path string, ttl time.Duration) (*FileDedupeStore, error) {
+	s := &FileDedupeStore{
+		mem:  NewMemoryDedupeStore(ttl),
+		path: path,
+	}
+
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+	if err := s.compact(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// load reads the records of a file. Lines that can not be decoded, such as a
+// line truncated by a crash, are skipped.
+func (s *FileDedupeStore) load() error {
+	file, err := os.Open(s.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	now := s.mem.now()
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var record dedupeRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil || record.ID == "" {
+			continue
+		}
+
+		if record.Completed && now.Before(record.ExpiresAt) {
+			s.mem.entries[record.ID] = dedupeEntry{expires: record.ExpiresAt, completed: true}
+		}
+	}
+	return scanner.Err()
+}
+
+// compact rewrites the file with the completed events that are not expired,
+// and opens it for appending.
+func (s *FileDedupeStore) compact() error {
+	tmp, err := ioutil.TempFile(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	s.mem.mu.Lock()
+	now := s.mem.now()
+	var records []dedupeRecord
+	for id, entry := range s.mem.entries {
+		if entry.completed && now.Before(entry.expires) {
+			records = append(records, dedupeRecord{ID: id, ExpiresAt: entry.expires, Completed: true})
+		}
+	}
+	s.mem.mu.Unlock()
+
+	w := bufio.NewWriter(tmp)
+	enc := json.NewEncoder(w)
+	for _, record := range records {
+		if err := enc.Encode(record); err != nil {
+			tmp.Close()
+			return err
+		}
+	}
+
+	if err := w.Flush(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(s.path, os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	if s.file != nil {
+		s.file.Close()
+	}
+	s.file = file
+	s.records = len(records)
+	return nil
+}
+
+// compactIfNeeded compacts the file once most of its records are expired.
+func (s *FileDedupeStore) compactIfNeeded() {
+	if s.records < dedupeCompactionThreshold {
+		return
+	}
+
+	s.mem.mu.Lock()
+	now := s.mem.now()
+	live := 0
+	for _, entry := range s.mem.entries {
+		if entry.completed && now.Before(entry.expires) {
+			live++
+		}
+	}
+	s.mem.mu.Unlock()
+
+	if s.records < 2*live {
+		return
+	}
+
+	// The completions are already durable: a failed compaction is attempted
+	// again on the next completion.
+	s.compact()
+}
+
+// Sets the function used to determine the current time. Usually you'll only
+// need to call this for testing purposes.
+func (s *FileDedupeStore) SetNow(now func() time.Time) {
+	s.mem.SetNow(now)
+}
+
+// SetLease sets the time events are claimed for. It must be longer than the
+// processing of an event.
+func (s *FileDedupeStore) SetLease(lease time.Duration) {
+	s.mem.SetLease(lease)
+}
+
+// Claim implements DedupeStore. The claim is only kept in memory.
+func (s *FileDedupeStore) Claim(ctx context.Context, id string) (bool, error) {
+	return s.mem.Claim(ctx, id)
+}
+
+// Complete implements DedupeStore. The completion is written to the file
+// before it returns.
+func (s *FileDedupeStore) Complete(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.mem.mu.Lock()
+	expires := s.mem.complete(id)
+	s.mem.mu.Unlock()
+
+	if err := s.append(dedupeRecord{ID: id, ExpiresAt: expires, Completed: true}); err != nil {
+		// Let the event be processed again rather than lose it after a
+		// restart.
+		s.mem.Release(ctx, id)
+		return err
+	}
+	s.records++
+
+	s.compactIfNeeded()
+	return nil
+}
+
+// Release implements DedupeStore. Like claims, releases are only kept in
+// memory.
+func (s *FileDedupeStore) Release(ctx context.Context, id string) error {
+	return s.mem.Release(ctx, id)
+}
+
+func (s *FileDedupeStore) append(record dedupeRecord) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	if _, err = s.file.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	return s.file.Sync()
+}
+
+// Close closes the file of the store.
+func (s *FileDedupeStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.file.Close()
+}
@@ -0,0 +1,192 @@
+package webhooks_test
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/omi-lab/workos-go/v4/pkg/webhooks"
+)
+
+func claim(t *testing.T, store webhooks.DedupeStore, id string) bool {
+	t.Helper()
+
+	claimed, err := store.Claim(context.Background(), id)
+	if err != nil {
+		t.Fatalf("expected no error, but got %v", err)
+	}
+	return claimed
+}
+
+func complete(t *testing.T, store webhooks.DedupeStore, id string) {
+	t.Helper()
+
+	if err := store.Complete(context.Background(), id); err != nil {
+		t.Fatalf("expected no error, but got %v", err)
+	}
+}
+
+func TestMemoryDedupeStore(t *testing.T) {
+	now := time.Now()
+	store := webhooks.NewMemoryDedupeStore(time.Hour)
+	store.SetNow(func() time.Time { return now })
+	store.SetLease(time.Minute)
+
+	if !claim(t, store, "wh_01") {
+		t.Fatal("expected the first claim to succeed")
+	}
+	if claim(t, store, "wh_01") {
+		t.Fatal("expected the second claim to fail")
+	}
+
+	if err := store.Release(context.Background(), "wh_01"); err != nil {
+		t.Fatalf("expected no error, but got %v", err)
+	}
+	if !claim(t, store, "wh_01") {
+		t.Fatal("expected a released event to be claimed again")
+	}
+
+	// A claim that is not completed expires with its lease.
+	now = now.Add(time.Minute)
+	if !claim(t, store, "wh_01") {
+		t.Fatal("expected a stale claim to be claimed again")
+	}
+
+	complete(t, store, "wh_01")
+	now = now.Add(30 * time.Minute)
+	if claim(t, store, "wh_01") {
+		t.Fatal("expected a completed event not to be claimed")
+	}
+
+	now = now.Add(30 * time.Minute)
+	if !claim(t, store, "wh_01") {
+		t.Fatal("expected an expired event to be claimed again")
+	}
+}
+
+func TestFileDedupeStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "webhooks")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "dedupe.jsonl")
+
+	store, err := webhooks.NewFileDedupeStore(path, time.Hour)
+	if err != nil {
+		t.Fatalf("expected no error, but got %v", err)
+	}
+	for _, id := range []string{"wh_01", "wh_02", "wh_04"} {
+		if !claim(t, store, id) {
+			t.Fatalf("expected the claim of %s to succeed", id)
+		}
+	}
+	complete(t, store, "wh_01")
+	if err := store.Release(context.Background(), "wh_02"); err != nil {
+		t.Fatalf("expected no error, but got %v", err)
+	}
+
+	// The process crashes while wh_04 is processed.
+	if err := store.Close(); err != nil {
+		t.Fatalf("expected no error, but got %v", err)
+	}
+
+	// A line truncated by a crash is skipped.
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(`{"id": "wh_03", "expi`)
+	f.Close()
+
+	store, err = webhooks.NewFileDedupeStore(path, time.Hour)
+	if err != nil {
+		t.Fatalf("expected no error, but got %v", err)
+	}
+	defer store.Close()
+
+	if claim(t, store, "wh_01") {
+		t.Error("expected wh_01 to be remembered after reopening the store")
+	}
+	if !claim(t, store, "wh_02") {
+		t.Error("expected the released wh_02 to be claimed again")
+	}
+	if !claim(t, store, "wh_03") {
+		t.Error("expected the truncated wh_03 to be claimed")
+	}
+	if !claim(t, store, "wh_04") {
+		t.Error("expected wh_04, whose processing was interrupted, to be claimed again")
+	}
+
+	store.SetNow(func() time.Time { return time.Now().Add(time.Hour) })
+	if !claim(t, store, "wh_01") {
+		t.Error("expected an expired event to be claimed again")
+	}
+}
+
+func TestFileDedupeStoreCompaction(t *testing.T) {
+	dir, err := ioutil.TempDir("", "webhooks")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "dedupe.jsonl")
+
+	now := time.Now()
+	store, err := webhooks.NewFileDedupeStore(path, time.Hour)
+	if err != nil {
+		t.Fatalf("expected no error, but got %v", err)
+	}
+	defer store.Close()
+	store.SetNow(func() time.Time { return now })
+
+	lines := func() int {
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return strings.Count(string(b), "\n")
+	}
+
+	// The deliveries of an event that keeps failing are not written.
+	for i := 0; i < 200; i++ {
+		if !claim(t, store, "wh_failing") {
+			t.Fatal("expected the released event to be claimed again")
+		}
+		if err := store.Release(context.Background(), "wh_failing"); err != nil {
+			t.Fatalf("expected no error, but got %v", err)
+		}
+	}
+	if n := lines(); n != 0 {
+		t.Fatalf("expected releases not to be written, but the file has %d lines", n)
+	}
+
+	for i := 0; i < 150; i++ {
+		id := fmt.Sprintf("wh_%03d", i)
+		claim(t, store, id)
+		complete(t, store, id)
+	}
+	if n := lines(); n > 150 {
+		t.Fatalf("expected at most 150 lines, but got %d", n)
+	}
+
+	// Once the completions expire, the file is compacted.
+	now = now.Add(2 * time.Hour)
+	for i := 0; i < 150; i++ {
+		id := fmt.Sprintf("wh_new_%03d", i)
+		claim(t, store, id)
+		complete(t, store, id)
+	}
+	if n := lines(); n >= 200 {
+		t.Fatalf("expected the expired completions to be compacted, but the file has %d lines", n)
+	}
+
+	if claim(t, store, "wh_new_149") {
+		t.Error("expected wh_new_149 to be remembered after the compaction")
+	}
+}
@@ -0,0 +1,94 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+
+	"github.com/omi-lab/workos-go/v4/pkg/models"
+)
+
+// ErrMissingEvent is returned when a webhook payload has no event name.
+var ErrMissingEvent = errors.New("webhook payload has no event")
+
+// UnknownEvent is returned by ParseEvent for the events that have no typed
+// struct. Its Data is left undecoded.
+type UnknownEvent struct {
+	models.WebhookEvent
+	Data json.RawMessage `json:"data"`
+}
+
+// eventTypes maps the events to their typed struct.
+var eventTypes = map[models.WebhookEventName]reflect.Type{
+	models.WebhookEventNameEmailVerificationCreated:      reflect.TypeOf(models.WebhookEventEmailVerificationCreated{}),
+	models.WebhookEventNamePasswordResetCreated:          reflect.TypeOf(models.WebhookEventPasswordResetCreated{}),
+	models.WebhookEventNameConnectionActivated:           reflect.TypeOf(models.WebhookEventConnection{}),
+	models.WebhookEventNameConnectionDeactivated:         reflect.TypeOf(models.WebhookEventConnection{}),
+	models.WebhookEventNameConnectionDeleted:             reflect.TypeOf(models.WebhookEventConnection{}),
+	models.WebhookEventNameInvitationCreated:             reflect.TypeOf(models.WebhookEventInvitationCreated{}),
+	models.WebhookEventNameDirectoryActivated:            reflect.TypeOf(models.WebhookEventDirectory{}),
+	models.WebhookEventNameDirectoryDeleted:              reflect.TypeOf(models.WebhookEventDirectory{}),
+	models.WebhookEventNameDirectoryUserCreated:          reflect.TypeOf(models.WebhookEventDirectoryUser{}),
+	models.WebhookEventNameDirectoryUserUpdated:          reflect.TypeOf(models.WebhookEventDirectoryUser{}),
+	models.WebhookEventNameDirectoryUserDeleted:          reflect.TypeOf(models.WebhookEventDirectoryUser{}),
+	models.WebhookEventNameDirectoryGroupCreated:         reflect.TypeOf(models.WebhookEventDirectoryGroup{}),
+	models.WebhookEventNameDirectoryGroupUpdated:         reflect.TypeOf(models.WebhookEventDirectoryGroup{}),
+	models.WebhookEventNameDirectoryGroupDeleted:         reflect.TypeOf(models.WebhookEventDirectoryGroup{}),
+	models.WebhookEventNameDirectoryGroupUserAdded:       reflect.TypeOf(models.WebhookEventDirectoryGroupMembership{}),
+	models.WebhookEventNameDirectoryGroupUserRemoved:     reflect.TypeOf(models.WebhookEventDirectoryGroupMembership{}),
+	models.WebhookEventNameOrganizationCreated:           reflect.TypeOf(models.WebhookEventOrganization{}),
+	models.WebhookEventNameOrganizationUpdated:           reflect.TypeOf(models.WebhookEventOrganization{}),
+	models.WebhookEventNameOrganizationDeleted:           reflect.TypeOf(models.WebhookEventOrganization{}),
+	models.WebhookEventNameUserCreated:                   reflect.TypeOf(models.WebhookEventUser{}),
+	models.WebhookEventNameUserUpdated:                   reflect.TypeOf(models.WebhookEventUser{}),
+	models.WebhookEventNameUserDeleted:                   reflect.TypeOf(models.WebhookEventUser{}),
+	models.WebhookEventNameOrganizationMembershipCreated: reflect.TypeOf(models.WebhookEventOrganizationMembership{}),
+	models.WebhookEventNameOrganizationMembershipUpdated: reflect.TypeOf(models.WebhookEventOrganizationMembership{}),
+	models.WebhookEventNameOrganizationMembershipDeleted: reflect.TypeOf(models.WebhookEventOrganizationMembership{}),
+	models.WebhookEventNameSessionCreated:                reflect.TypeOf(models.WebhookEventSessionCreated{}),
+	models.WebhookEventNameMagicAuthCreated:              reflect.TypeOf(models.WebhookEventMagicAuthCreated{}),
+	models.WebhookEventNameOrganizationMembershipAdded:   reflect.TypeOf(models.WebhookEventOrganizationMembership{}),
+	models.WebhookEventNameOrganizationMembershipRemoved: reflect.TypeOf(models.WebhookEventOrganizationMembership{}),
+}
+
+// ParseEvent decodes a webhook payload into the typed struct of its event,
+// e.g. a models.WebhookEventConnection for a connection.activated event.
+// Events without a typed struct are returned as an UnknownEvent.
+//
+// The payload must have been validated with ValidatePayload first.
+func ParseEvent(body []byte) (interface{}, error) {
+	header, err := eventHeader(body)
+	if err != nil {
+		return nil, err
+	}
+	return parseEvent(header.Event, body)
+}
+
+// eventHeader decodes the fields common to all the events of a payload.
+func eventHeader(body []byte) (models.WebhookEvent, error) {
+	var event models.WebhookEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		return models.WebhookEvent{}, err
+	}
+	if event.Event == "" {
+		return models.WebhookEvent{}, ErrMissingEvent
+	}
+	return event, nil
+}
+
+func parseEvent(name models.WebhookEventName, body []byte) (interface{}, error) {
+	typ, ok := eventTypes[name]
+	if !ok {
+		var event UnknownEvent
+		if err := json.Unmarshal(body, &event); err != nil {
+			return nil, err
+		}
+		return event, nil
+	}
+
+	event := reflect.New(typ)
+	if err := json.Unmarshal(body, event.Interface()); err != nil {
+		return nil, err
+	}
+	return event.Elem().Interface(), nil
+}
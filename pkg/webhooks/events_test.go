@@ -0,0 +1,119 @@
+package webhooks_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/omi-lab/workos-go/v4/pkg/models"
+	"github.com/omi-lab/workos-go/v4/pkg/webhooks"
+)
+
+const connectionActivatedPayload = `{
+	"id": "wh_01",
+	"event": "connection.activated",
+	"data": {
+		"object": "connection",
+		"id": "conn_01",
+		"organization_id": "org_01",
+		"state": "active",
+		"domains": [{"object": "connection_domain", "id": "conn_domain_01", "domain": "foo-corp.com"}]
+	}
+}`
+
+func TestParseEvent(t *testing.T) {
+	event, err := webhooks.ParseEvent([]byte(connectionActivatedPayload))
+	if err != nil {
+		t.Fatalf("expected no error, but got %v", err)
+	}
+
+	connection, ok := event.(models.WebhookEventConnection)
+	if !ok {
+		t.Fatalf("expected a WebhookEventConnection, but got %T", event)
+	}
+	if connection.ID != "wh_01" || connection.Event != "connection.activated" {
+		t.Errorf("unexpected event: %+v", connection.WebhookEvent)
+	}
+	if connection.Data.ID != "conn_01" || connection.Data.State != models.ConnectionStateActive {
+		t.Errorf("unexpected connection: %+v", connection.Data)
+	}
+	if len(connection.Data.Domains) != 1 || connection.Data.Domains[0].Domain != "foo-corp.com" {
+		t.Errorf("unexpected domains: %+v", connection.Data.Domains)
+	}
+}
+
+func TestParseTypedEvents(t *testing.T) {
+	tests := []struct {
+		payload string
+		check   func(event interface{}) bool
+	}{
+		{
+			payload: `{"id": "wh_01", "event": "dsync.user.created", "data": {"id": "directory_user_01", "state": "active"}}`,
+			check: func(event interface{}) bool {
+				e, ok := event.(models.WebhookEventDirectoryUser)
+				return ok && e.Data.ID == "directory_user_01" && e.Data.State == models.DirectoryUserStateActive
+			},
+		},
+		{
+			payload: `{"id": "wh_01", "event": "dsync.group.user_removed", "data": {"directory_id": "directory_01", "user": {"id": "directory_user_01"}, "group": {"id": "directory_group_01"}}}`,
+			check: func(event interface{}) bool {
+				e, ok := event.(models.WebhookEventDirectoryGroupMembership)
+				return ok && e.Data.User.ID == "directory_user_01" && e.Data.Group.ID == "directory_group_01"
+			},
+		},
+		{
+			payload: `{"id": "wh_01", "event": "organization_membership.updated", "data": {"id": "om_01", "role": {"slug": "admin"}}}`,
+			check: func(event interface{}) bool {
+				e, ok := event.(models.WebhookEventOrganizationMembership)
+				return ok && e.Data.ID == "om_01" && e.Data.Role.Slug == "admin"
+			},
+		},
+		{
+			payload: `{"id": "wh_01", "event": "session.created", "data": {"id": "session_01", "user_id": "user_01"}}`,
+			check: func(event interface{}) bool {
+				e, ok := event.(models.WebhookEventSessionCreated)
+				return ok && e.Data.ID == "session_01" && e.Data.UserID == "user_01"
+			},
+		},
+		{
+			payload: `{"id": "wh_01", "event": "user.deleted", "data": {"id": "user_01"}, "createdAt": "2024-01-01T12:00:00Z"}`,
+			check: func(event interface{}) bool {
+				e, ok := event.(models.WebhookEventUser)
+				return ok && e.Data.ID == "user_01" && e.CreatedAt.Equal(time.Date(2024, time.January, 1, 12, 0, 0, 0, time.UTC))
+			},
+		},
+	}
+
+	for _, test := range tests {
+		event, err := webhooks.ParseEvent([]byte(test.payload))
+		if err != nil {
+			t.Fatalf("expected no error, but got %v", err)
+		}
+		if !test.check(event) {
+			t.Errorf("unexpected event %+v for payload %s", event, test.payload)
+		}
+	}
+}
+
+func TestParseUnknownEvent(t *testing.T) {
+	event, err := webhooks.ParseEvent([]byte(`{"id": "wh_01", "event": "unknown.event", "data": {"id": "foo"}}`))
+	if err != nil {
+		t.Fatalf("expected no error, but got %v", err)
+	}
+
+	unknown, ok := event.(webhooks.UnknownEvent)
+	if !ok {
+		t.Fatalf("expected an UnknownEvent, but got %T", event)
+	}
+	if unknown.Event != "unknown.event" || string(unknown.Data) != `{"id": "foo"}` {
+		t.Errorf("unexpected event: %+v", unknown)
+	}
+}
+
+func TestParseEventWithoutEvent(t *testing.T) {
+	if _, err := webhooks.ParseEvent([]byte(`{"id": "wh_01"}`)); err != webhooks.ErrMissingEvent {
+		t.Errorf("expected ErrMissingEvent, but got %v", err)
+	}
+	if _, err := webhooks.ParseEvent([]byte(`not json`)); err == nil {
+		t.Error("expected an error, but got none")
+	}
+}
@@ -0,0 +1,297 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/omi-lab/workos-go/v4/pkg/models"
+)
+
+// SignatureHeader is the header that carries the signature of a webhook.
+const SignatureHeader = "WorkOS-Signature"
+
+// DefaultMaxBodySize is the default maximum size of the body of a webhook.
+const DefaultMaxBodySize = 1 << 20
+
+// RejectionReason describes why a webhook delivery was rejected.
+type RejectionReason string
+
+// Constants that enumerate the reasons of a Rejection.
+const (
+	RejectionMethodNotAllowed RejectionReason = "method_not_allowed"
+	RejectionBodyTooLarge     RejectionReason = "body_too_large"
+	RejectionInvalidSignature RejectionReason = "invalid_signature"
+	RejectionInvalidPayload   RejectionReason = "invalid_payload"
+	RejectionHandlerFailed    RejectionReason = "handler_failed"
+	RejectionPermanentFailure RejectionReason = "permanent_failure"
+	RejectionHandlerTimedOut  RejectionReason = "handler_timed_out"
+	RejectionDuplicate        RejectionReason = "duplicate"
+)
+
+// Rejection describes a webhook delivery that was not processed.
+type Rejection struct {
+	// Why the delivery was rejected.
+	Reason RejectionReason
+
+	// The status code of the response sent to WorkOS.
+	Status int
+
+	// The error that caused the rejection.
+	Err error
+
+	// The ID and the name of the event, when they are known.
+	EventID string
+	Event   string
+
+	// The address of the sender.
+	RemoteAddr string
+}
+
+// String formats the rejection as logfmt.
+func (r Rejection) String() string {
+	s := fmt.Sprintf("reason=%s status=%d remote_addr=%q", r.Reason, r.Status, r.RemoteAddr)
+	if r.EventID != "" {
+		s += fmt.Sprintf(" event_id=%s event=%s", r.EventID, r.Event)
+	}
+	if r.Err != nil {
+		s += fmt.Sprintf(" error=%q", r.Err.Error())
+	}
+	return s
+}
+
+// permanentError marks the errors that retrying the delivery will not fix.
+type permanentError struct {
+	err error
+}
+
+func (e permanentError) Error() string { return e.err.Error() }
+func (e permanentError) Unwrap() error { return e.err }
+
+// Permanent marks an error returned by a handler as permanent. The delivery
+// is then acknowledged so that WorkOS does not retry it, and logged as a
+// Rejection.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return permanentError{err: err}
+}
+
+// IsPermanent reports whether an error was marked with Permanent.
+func IsPermanent(err error) bool {
+	var p permanentError
+	return errors.As(err, &p)
+}
+
+// HandlerOption customizes a webhook handler created with Handler.
+type HandlerOption func(*handler)
+
+// WithMaxBodySize sets the maximum size of the body of a webhook. Defaults to
+// DefaultMaxBodySize.
+func WithMaxBodySize(n int64) HandlerOption {
+	return func(h *handler) {
+		h.maxBodySize = n
+	}
+}
+
+// WithTolerance sets the maximum time between the timestamp of a webhook and
+// its reception. Defaults to 3 minutes.
+func WithTolerance(tolerance time.Duration) HandlerOption {
+	return func(h *handler) {
+		h.client.SetTolerance(tolerance)
+	}
+}
+
+// WithNow sets the function used to determine the current time. Usually
+// you'll only need to call this for testing purposes.
+func WithNow(now func() time.Time) HandlerOption {
+	return func(h *handler) {
+		h.client.SetNow(now)
+	}
+}
+
+// WithSecrets replaces the secret given to Handler with a set of secrets, as
+// with NewClientWithSecrets.
+func WithSecrets(secrets ...Secret) HandlerOption {
+	return func(h *handler) {
+		h.client.secrets = append([]Secret(nil), secrets...)
+	}
+}
+
+// WithRejectionLogger sets the function rejected deliveries are reported to.
+// Defaults to logging them with the standard logger.
+func WithRejectionLogger(logRejection func(*http.Request, Rejection)) HandlerOption {
+	return func(h *handler) {
+		h.logRejection = logRejection
+	}
+}
+
+// WithDedupeStore sets the store used to recognize the events that were
+// already processed, e.g. a delivery retried by WorkOS or replayed by an
+// attacker within the tolerance. Their deliveries are acknowledged without
+// calling the router, and reported as a Rejection. Events are not
+// deduplicated by default.
+func WithDedupeStore(store DedupeStore) HandlerOption {
+	return func(h *handler) {
+		h.dedupe = store
+	}
+}
+
+type verificationKey struct{}
+
+// VerificationFromContext returns the verification of the webhook being
+// handled by a Handler, e.g. to monitor which secret deliveries are signed
+// with while it is rotated.
+func VerificationFromContext(ctx context.Context) (Verification, bool) {
+	v, ok := ctx.Value(verificationKey{}).(Verification)
+	return v, ok
+}
+
+type handler struct {
+	client       *Client
+	parse        func(name string, body []byte) (interface{}, error)
+	route        func(ctx context.Context, name string, event interface{}) error
+	dedupe       DedupeStore
+	maxBodySize  int64
+	logRejection func(*http.Request, Rejection)
+}
+
+// Handler returns an http.Handler that receives the webhooks sent by WorkOS.
+//
+// It verifies the signature and the timestamp of each delivery with the
+// given secret, then dispatches its event to the router. The response tells
+// WorkOS whether the delivery must be retried:
+//
+//   - 200 OK when the event was handled, ignored by the router, already
+//     processed according to the DedupeStore, or failed with an error marked
+//     with Permanent.
+//   - 400 Bad Request, 401 Unauthorized, 405 Method Not Allowed or 413
+//     Request Entity Too Large when the delivery is rejected.
+//   - 503 Service Unavailable when the handler did not complete before the
+//     request was canceled.
+//   - 500 Internal Server Error for other handler errors.
+//
+// Every delivery that is not handled successfully is reported to the
+// rejection logger.
+func Handler(secret string, router *Router, opts ...HandlerOption) http.Handler {
+	return newHandler(secret, parseEvent, router.route, opts)
+}
+
+// EventsHandler returns an http.Handler like Handler, that passes every event
+// to h as a models.Event instead of dispatching it to a Router. It is meant
+// to process webhooks and the results of the Events API with the same code.
+func EventsHandler(secret string, h func(context.Context, models.Event) error, opts ...HandlerOption) http.Handler {
+	parse := func(name string, body []byte) (interface{}, error) {
+		var event models.Event
+		err := json.Unmarshal(body, &event)
+		return event, err
+	}
+	route := func(ctx context.Context, name string, event interface{}) error {
+		return h(ctx, event.(models.Event))
+	}
+	return newHandler(secret, parse, route, opts)
+}
+
+func newHandler(
+	secret string,
+	parse func(string, []byte) (interface{}, error),
+	route func(context.Context, string, interface{}) error,
+	opts []HandlerOption,
+) *handler {
+	h := &handler{
+		client:      NewClient(secret),
+		parse:       parse,
+		route:       route,
+		maxBodySize: DefaultMaxBodySize,
+		logRejection: func(r *http.Request, rejection Rejection) {
+			log.Printf("webhooks: rejected delivery %s", rejection)
+		},
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rejection := Rejection{RemoteAddr: r.RemoteAddr}
+	reject := func(status int, reason RejectionReason, err error) {
+		rejection.Status = status
+		rejection.Reason = reason
+		rejection.Err = err
+		h.logRejection(r, rejection)
+		http.Error(w, http.StatusText(status), status)
+	}
+
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		reject(http.StatusMethodNotAllowed, RejectionMethodNotAllowed, nil)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, h.maxBodySize+1))
+	if err != nil {
+		reject(http.StatusBadRequest, RejectionInvalidPayload, err)
+		return
+	}
+	if int64(len(body)) > h.maxBodySize {
+		reject(http.StatusRequestEntityTooLarge, RejectionBodyTooLarge, nil)
+		return
+	}
+
+	verification, err := h.client.Verify(r.Header.Get(SignatureHeader), string(body))
+	if err != nil {
+		reject(http.StatusUnauthorized, RejectionInvalidSignature, err)
+		return
+	}
+	ctx := context.WithValue(r.Context(), verificationKey{}, verification)
+
+	header, err := eventHeader(body)
+	if err != nil {
+		reject(http.StatusBadRequest, RejectionInvalidPayload, err)
+		return
+	}
+	rejection.EventID = header.ID
+	rejection.Event = header.Event
+
+	event, err := h.parse(header.Event, body)
+	if err != nil {
+		reject(http.StatusBadRequest, RejectionInvalidPayload, err)
+		return
+	}
+
+	if h.dedupe != nil && header.ID != "" {
+		claimed, err := h.dedupe.Claim(ctx, header.ID)
+		if err != nil {
+			reject(http.StatusInternalServerError, RejectionHandlerFailed, err)
+			return
+		}
+		if !claimed {
+			reject(http.StatusOK, RejectionDuplicate, nil)
+			return
+		}
+	}
+
+	err = h.route(ctx, header.Event, event)
+	if h.dedupe != nil && header.ID != "" {
+		err = Settle(h.dedupe, header.ID, err)
+	}
+	if err != nil {
+		switch {
+		case IsPermanent(err):
+			reject(http.StatusOK, RejectionPermanentFailure, err)
+		case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+			reject(http.StatusServiceUnavailable, RejectionHandlerTimedOut, err)
+		default:
+			reject(http.StatusInternalServerError, RejectionHandlerFailed, err)
+		}
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
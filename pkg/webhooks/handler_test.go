@@ -0,0 +1,327 @@
+package webhooks_test
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/omi-lab/workos-go/v4/pkg/models"
+	"github.com/omi-lab/workos-go/v4/pkg/webhooks"
+)
+
+func deliver(h http.Handler, method, signature, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "/webhooks", strings.NewReader(body))
+	if signature != "" {
+		req.Header.Set(webhooks.SignatureHeader, signature)
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestHandler(t *testing.T) {
+	secret := "secret"
+	handlerErr := error(nil)
+
+	var handled []string
+	router := webhooks.NewRouter()
+	router.OnConnectionActivated(func(ctx context.Context, e models.WebhookEventConnection) error {
+		handled = append(handled, e.Data.ID)
+		return handlerErr
+	})
+
+	var rejections []webhooks.Rejection
+	h := webhooks.Handler(secret, router,
+		webhooks.WithMaxBodySize(1024),
+		webhooks.WithRejectionLogger(func(r *http.Request, rejection webhooks.Rejection) {
+			rejections = append(rejections, rejection)
+		}),
+	)
+
+	tests := []struct {
+		scenario   string
+		method     string
+		signature  string
+		body       string
+		handlerErr error
+		status     int
+		reason     webhooks.RejectionReason
+	}{
+		{
+			scenario:  "handled event",
+			signature: mockWebhookHeader(time.Now(), secret, connectionActivatedPayload),
+			body:      connectionActivatedPayload,
+			status:    http.StatusOK,
+		},
+		{
+			scenario:  "ignored event",
+			signature: mockWebhookHeader(time.Now(), secret, `{"id": "wh_02", "event": "user.created"}`),
+			body:      `{"id": "wh_02", "event": "user.created"}`,
+			status:    http.StatusOK,
+		},
+		{
+			scenario: "GET request",
+			method:   http.MethodGet,
+			status:   http.StatusMethodNotAllowed,
+			reason:   webhooks.RejectionMethodNotAllowed,
+		},
+		{
+			scenario:  "body too large",
+			signature: mockWebhookHeader(time.Now(), secret, strings.Repeat(" ", 1025)),
+			body:      strings.Repeat(" ", 1025),
+			status:    http.StatusRequestEntityTooLarge,
+			reason:    webhooks.RejectionBodyTooLarge,
+		},
+		{
+			scenario: "unsigned delivery",
+			body:     connectionActivatedPayload,
+			status:   http.StatusUnauthorized,
+			reason:   webhooks.RejectionInvalidSignature,
+		},
+		{
+			scenario:  "delivery signed with another secret",
+			signature: mockWebhookHeader(time.Now(), "other_secret", connectionActivatedPayload),
+			body:      connectionActivatedPayload,
+			status:    http.StatusUnauthorized,
+			reason:    webhooks.RejectionInvalidSignature,
+		},
+		{
+			scenario:  "stale delivery",
+			signature: mockWebhookHeader(time.Now().Add(-time.Hour), secret, connectionActivatedPayload),
+			body:      connectionActivatedPayload,
+			status:    http.StatusUnauthorized,
+			reason:    webhooks.RejectionInvalidSignature,
+		},
+		{
+			scenario:  "malformed payload",
+			signature: mockWebhookHeader(time.Now(), secret, `{"id": "wh_01"}`),
+			body:      `{"id": "wh_01"}`,
+			status:    http.StatusBadRequest,
+			reason:    webhooks.RejectionInvalidPayload,
+		},
+		{
+			scenario:   "failed handler",
+			signature:  mockWebhookHeader(time.Now(), secret, connectionActivatedPayload),
+			body:       connectionActivatedPayload,
+			handlerErr: errors.New("database unavailable"),
+			status:     http.StatusInternalServerError,
+			reason:     webhooks.RejectionHandlerFailed,
+		},
+		{
+			scenario:   "timed out handler",
+			signature:  mockWebhookHeader(time.Now(), secret, connectionActivatedPayload),
+			body:       connectionActivatedPayload,
+			handlerErr: context.DeadlineExceeded,
+			status:     http.StatusServiceUnavailable,
+			reason:     webhooks.RejectionHandlerTimedOut,
+		},
+		{
+			scenario:   "permanently failed handler",
+			signature:  mockWebhookHeader(time.Now(), secret, connectionActivatedPayload),
+			body:       connectionActivatedPayload,
+			handlerErr: webhooks.Permanent(errors.New("unknown organization")),
+			status:     http.StatusOK,
+			reason:     webhooks.RejectionPermanentFailure,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			handled, rejections, handlerErr = nil, nil, test.handlerErr
+
+			method := test.method
+			if method == "" {
+				method = http.MethodPost
+			}
+
+			rec := deliver(h, method, test.signature, test.body)
+			if rec.Code != test.status {
+				t.Errorf("expected status %d, but got %d", test.status, rec.Code)
+			}
+
+			if test.reason == "" {
+				if len(rejections) != 0 {
+					t.Errorf("expected no rejection, but got %v", rejections)
+				}
+				return
+			}
+
+			if len(rejections) != 1 {
+				t.Fatalf("expected one rejection, but got %v", rejections)
+			}
+			if rejections[0].Reason != test.reason || rejections[0].Status != test.status {
+				t.Errorf("unexpected rejection: %v", rejections[0])
+			}
+			if test.handlerErr != nil && rejections[0].EventID != "wh_01" {
+				t.Errorf("expected the rejection to have the event ID, but got %v", rejections[0])
+			}
+		})
+	}
+}
+
+func TestRejectionString(t *testing.T) {
+	rejection := webhooks.Rejection{
+		Reason:     webhooks.RejectionHandlerFailed,
+		Status:     http.StatusInternalServerError,
+		Err:        errors.New("database unavailable"),
+		EventID:    "wh_01",
+		Event:      "connection.activated",
+		RemoteAddr: "192.0.2.1:1234",
+	}
+
+	expected := `reason=handler_failed status=500 remote_addr="192.0.2.1:1234" event_id=wh_01 event=connection.activated error="database unavailable"`
+	if s := rejection.String(); s != expected {
+		t.Errorf("expected %s, but got %s", expected, s)
+	}
+}
+
+func TestHandlerWithSecrets(t *testing.T) {
+	var matched string
+	router := webhooks.NewRouter()
+	router.OnConnectionActivated(func(ctx context.Context, e models.WebhookEventConnection) error {
+		verification, _ := webhooks.VerificationFromContext(ctx)
+		matched = verification.Secret.ID
+		return nil
+	})
+
+	h := webhooks.Handler("", router, webhooks.WithSecrets(
+		webhooks.Secret{ID: "old", Value: "old_secret"},
+		webhooks.Secret{ID: "new", Value: "new_secret"},
+	))
+
+	rec := deliver(h, http.MethodPost, mockWebhookHeader(time.Now(), "old_secret", connectionActivatedPayload), connectionActivatedPayload)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, but got %d", rec.Code)
+	}
+	if matched != "old" {
+		t.Errorf("expected the old secret to match, but got %q", matched)
+	}
+}
+
+func TestHandlerWithDedupeStore(t *testing.T) {
+	secret := "secret"
+	handlerErr := errors.New("database unavailable")
+
+	handled := 0
+	router := webhooks.NewRouter()
+	router.OnConnectionActivated(func(ctx context.Context, e models.WebhookEventConnection) error {
+		handled++
+		return handlerErr
+	})
+
+	var rejections []webhooks.Rejection
+	h := webhooks.Handler(secret, router,
+		webhooks.WithDedupeStore(webhooks.NewMemoryDedupeStore(time.Hour)),
+		webhooks.WithRejectionLogger(func(r *http.Request, rejection webhooks.Rejection) {
+			rejections = append(rejections, rejection)
+		}),
+	)
+	send := func() int {
+		signature := mockWebhookHeader(time.Now(), secret, connectionActivatedPayload)
+		return deliver(h, http.MethodPost, signature, connectionActivatedPayload).Code
+	}
+
+	// A failed delivery is retried.
+	if status := send(); status != http.StatusInternalServerError {
+		t.Fatalf("expected status 500, but got %d", status)
+	}
+
+	handlerErr = nil
+	if status := send(); status != http.StatusOK {
+		t.Fatalf("expected status 200, but got %d", status)
+	}
+	if handled != 2 {
+		t.Fatalf("expected the retried delivery to be handled, but got %d calls", handled)
+	}
+
+	// A duplicate delivery is acknowledged without being handled.
+	rejections = nil
+	if status := send(); status != http.StatusOK {
+		t.Fatalf("expected status 200, but got %d", status)
+	}
+	if handled != 2 {
+		t.Errorf("expected the duplicate delivery not to be handled, but got %d calls", handled)
+	}
+	if len(rejections) != 1 || rejections[0].Reason != webhooks.RejectionDuplicate || rejections[0].EventID != "wh_01" {
+		t.Errorf("unexpected rejections: %v", rejections)
+	}
+}
+
+func TestHandlerWithDedupeStoreAfterCrash(t *testing.T) {
+	secret := "secret"
+	dir, err := ioutil.TempDir("", "webhooks")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "dedupe.jsonl")
+
+	// The process claims the event, then crashes before completing it.
+	store, err := webhooks.NewFileDedupeStore(path, time.Hour)
+	if err != nil {
+		t.Fatalf("expected no error, but got %v", err)
+	}
+	if _, err := store.Claim(context.Background(), "wh_01"); err != nil {
+		t.Fatalf("expected no error, but got %v", err)
+	}
+	store.Close()
+
+	store, err = webhooks.NewFileDedupeStore(path, time.Hour)
+	if err != nil {
+		t.Fatalf("expected no error, but got %v", err)
+	}
+	defer store.Close()
+
+	handled := 0
+	router := webhooks.NewRouter()
+	router.OnConnectionActivated(func(ctx context.Context, e models.WebhookEventConnection) error {
+		handled++
+		return nil
+	})
+	h := webhooks.Handler(secret, router,
+		webhooks.WithDedupeStore(store),
+		webhooks.WithRejectionLogger(func(r *http.Request, rejection webhooks.Rejection) {}),
+	)
+
+	// The retry of WorkOS is processed, and the following ones are not.
+	for i := 0; i < 2; i++ {
+		signature := mockWebhookHeader(time.Now(), secret, connectionActivatedPayload)
+		if status := deliver(h, http.MethodPost, signature, connectionActivatedPayload).Code; status != http.StatusOK {
+			t.Fatalf("expected status 200, but got %d", status)
+		}
+	}
+	if handled != 1 {
+		t.Errorf("expected the event to be handled once, but got %d calls", handled)
+	}
+}
+
+func TestEventsHandler(t *testing.T) {
+	secret := "secret"
+
+	var received []models.Event
+	h := webhooks.EventsHandler(secret, func(ctx context.Context, e models.Event) error {
+		received = append(received, e)
+		return nil
+	})
+
+	rec := deliver(h, http.MethodPost, mockWebhookHeader(time.Now(), secret, connectionActivatedPayload), connectionActivatedPayload)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, but got %d", rec.Code)
+	}
+	if len(received) != 1 || received[0].ID != "wh_01" || received[0].Event != models.EventConnectionActivated {
+		t.Fatalf("unexpected events: %+v", received)
+	}
+
+	connection, err := received[0].AsConnection()
+	if err != nil || connection.ID != "conn_01" {
+		t.Errorf("unexpected connection %+v, error: %v", connection, err)
+	}
+}
@@ -0,0 +1,287 @@
+package webhooks
+
+import (
+	"context"
+
+	"github.com/omi-lab/workos-go/v4/pkg/models"
+)
+
+// HandlerFunc handles an event parsed by ParseEvent.
+type HandlerFunc func(ctx context.Context, event interface{}) error
+
+// Router dispatches webhook events to the handlers registered for them.
+//
+// Handlers must be registered before the Router is used to dispatch events.
+type Router struct {
+	handlers map[models.WebhookEventName]HandlerFunc
+	fallback HandlerFunc
+}
+
+// NewRouter creates an empty Router.
+func NewRouter() *Router {
+	return &Router{handlers: make(map[models.WebhookEventName]HandlerFunc)}
+}
+
+// On registers the handler of an event, replacing the previous one. The
+// handler receives the event as returned by ParseEvent.
+func (r *Router) On(event models.WebhookEventName, h HandlerFunc) {
+	if r.handlers == nil {
+		r.handlers = make(map[models.WebhookEventName]HandlerFunc)
+	}
+	r.handlers[event] = h
+}
+
+// Fallback registers the handler of the events that have no handler,
+// including the events ParseEvent returns as an UnknownEvent. Events are
+// ignored when no fallback is registered.
+func (r *Router) Fallback(h HandlerFunc) {
+	r.fallback = h
+}
+
+// OnEmailVerificationCreated registers the handler of the
+// email_verification.created event.
+func (r *Router) OnEmailVerificationCreated(h func(context.Context, models.WebhookEventEmailVerificationCreated) error) {
+	r.On(models.WebhookEventNameEmailVerificationCreated, func(ctx context.Context, event interface{}) error {
+		return h(ctx, event.(models.WebhookEventEmailVerificationCreated))
+	})
+}
+
+// OnPasswordResetCreated registers the handler of the password_reset.created
+// event.
+func (r *Router) OnPasswordResetCreated(h func(context.Context, models.WebhookEventPasswordResetCreated) error) {
+	r.On(models.WebhookEventNamePasswordResetCreated, func(ctx context.Context, event interface{}) error {
+		return h(ctx, event.(models.WebhookEventPasswordResetCreated))
+	})
+}
+
+// OnConnectionActivated registers the handler of the connection.activated
+// event.
+func (r *Router) OnConnectionActivated(h func(context.Context, models.WebhookEventConnection) error) {
+	r.onConnection(models.WebhookEventNameConnectionActivated, h)
+}
+
+// OnConnectionDeactivated registers the handler of the
+// connection.deactivated event.
+func (r *Router) OnConnectionDeactivated(h func(context.Context, models.WebhookEventConnection) error) {
+	r.onConnection(models.WebhookEventNameConnectionDeactivated, h)
+}
+
+// OnConnectionDeleted registers the handler of the connection.deleted event.
+func (r *Router) OnConnectionDeleted(h func(context.Context, models.WebhookEventConnection) error) {
+	r.onConnection(models.WebhookEventNameConnectionDeleted, h)
+}
+
+func (r *Router) onConnection(name models.WebhookEventName, h func(context.Context, models.WebhookEventConnection) error) {
+	r.On(name, func(ctx context.Context, event interface{}) error {
+		return h(ctx, event.(models.WebhookEventConnection))
+	})
+}
+
+// OnInvitationCreated registers the handler of the invitation.created event.
+func (r *Router) OnInvitationCreated(h func(context.Context, models.WebhookEventInvitationCreated) error) {
+	r.On(models.WebhookEventNameInvitationCreated, func(ctx context.Context, event interface{}) error {
+		return h(ctx, event.(models.WebhookEventInvitationCreated))
+	})
+}
+
+// OnDirectoryActivated registers the handler of the dsync.activated event.
+func (r *Router) OnDirectoryActivated(h func(context.Context, models.WebhookEventDirectory) error) {
+	r.onDirectory(models.WebhookEventNameDirectoryActivated, h)
+}
+
+// OnDirectoryDeleted registers the handler of the dsync.deleted event.
+func (r *Router) OnDirectoryDeleted(h func(context.Context, models.WebhookEventDirectory) error) {
+	r.onDirectory(models.WebhookEventNameDirectoryDeleted, h)
+}
+
+func (r *Router) onDirectory(name models.WebhookEventName, h func(context.Context, models.WebhookEventDirectory) error) {
+	r.On(name, func(ctx context.Context, event interface{}) error {
+		return h(ctx, event.(models.WebhookEventDirectory))
+	})
+}
+
+// OnDirectoryUserCreated registers the handler of the
+// dsync.user.created event.
+func (r *Router) OnDirectoryUserCreated(h func(context.Context, models.WebhookEventDirectoryUser) error) {
+	r.onDirectoryUser(models.WebhookEventNameDirectoryUserCreated, h)
+}
+
+// OnDirectoryUserUpdated registers the handler of the
+// dsync.user.updated event.
+func (r *Router) OnDirectoryUserUpdated(h func(context.Context, models.WebhookEventDirectoryUser) error) {
+	r.onDirectoryUser(models.WebhookEventNameDirectoryUserUpdated, h)
+}
+
+// OnDirectoryUserDeleted registers the handler of the
+// dsync.user.deleted event.
+func (r *Router) OnDirectoryUserDeleted(h func(context.Context, models.WebhookEventDirectoryUser) error) {
+	r.onDirectoryUser(models.WebhookEventNameDirectoryUserDeleted, h)
+}
+
+func (r *Router) onDirectoryUser(name models.WebhookEventName, h func(context.Context, models.WebhookEventDirectoryUser) error) {
+	r.On(name, func(ctx context.Context, event interface{}) error {
+		return h(ctx, event.(models.WebhookEventDirectoryUser))
+	})
+}
+
+// OnDirectoryGroupCreated registers the handler of the
+// dsync.group.created event.
+func (r *Router) OnDirectoryGroupCreated(h func(context.Context, models.WebhookEventDirectoryGroup) error) {
+	r.onDirectoryGroup(models.WebhookEventNameDirectoryGroupCreated, h)
+}
+
+// OnDirectoryGroupUpdated registers the handler of the
+// dsync.group.updated event.
+func (r *Router) OnDirectoryGroupUpdated(h func(context.Context, models.WebhookEventDirectoryGroup) error) {
+	r.onDirectoryGroup(models.WebhookEventNameDirectoryGroupUpdated, h)
+}
+
+// OnDirectoryGroupDeleted registers the handler of the
+// dsync.group.deleted event.
+func (r *Router) OnDirectoryGroupDeleted(h func(context.Context, models.WebhookEventDirectoryGroup) error) {
+	r.onDirectoryGroup(models.WebhookEventNameDirectoryGroupDeleted, h)
+}
+
+func (r *Router) onDirectoryGroup(name models.WebhookEventName, h func(context.Context, models.WebhookEventDirectoryGroup) error) {
+	r.On(name, func(ctx context.Context, event interface{}) error {
+		return h(ctx, event.(models.WebhookEventDirectoryGroup))
+	})
+}
+
+// OnDirectoryGroupUserAdded registers the handler of the
+// dsync.group.user_added event.
+func (r *Router) OnDirectoryGroupUserAdded(h func(context.Context, models.WebhookEventDirectoryGroupMembership) error) {
+	r.onDirectoryGroupMembership(models.WebhookEventNameDirectoryGroupUserAdded, h)
+}
+
+// OnDirectoryGroupUserRemoved registers the handler of the
+// dsync.group.user_removed event.
+func (r *Router) OnDirectoryGroupUserRemoved(h func(context.Context, models.WebhookEventDirectoryGroupMembership) error) {
+	r.onDirectoryGroupMembership(models.WebhookEventNameDirectoryGroupUserRemoved, h)
+}
+
+func (r *Router) onDirectoryGroupMembership(name models.WebhookEventName, h func(context.Context, models.WebhookEventDirectoryGroupMembership) error) {
+	r.On(name, func(ctx context.Context, event interface{}) error {
+		return h(ctx, event.(models.WebhookEventDirectoryGroupMembership))
+	})
+}
+
+// OnOrganizationCreated registers the handler of the
+// organization.created event.
+func (r *Router) OnOrganizationCreated(h func(context.Context, models.WebhookEventOrganization) error) {
+	r.onOrganization(models.WebhookEventNameOrganizationCreated, h)
+}
+
+// OnOrganizationUpdated registers the handler of the
+// organization.updated event.
+func (r *Router) OnOrganizationUpdated(h func(context.Context, models.WebhookEventOrganization) error) {
+	r.onOrganization(models.WebhookEventNameOrganizationUpdated, h)
+}
+
+// OnOrganizationDeleted registers the handler of the
+// organization.deleted event.
+func (r *Router) OnOrganizationDeleted(h func(context.Context, models.WebhookEventOrganization) error) {
+	r.onOrganization(models.WebhookEventNameOrganizationDeleted, h)
+}
+
+func (r *Router) onOrganization(name models.WebhookEventName, h func(context.Context, models.WebhookEventOrganization) error) {
+	r.On(name, func(ctx context.Context, event interface{}) error {
+		return h(ctx, event.(models.WebhookEventOrganization))
+	})
+}
+
+// OnUserCreated registers the handler of the user.created event.
+func (r *Router) OnUserCreated(h func(context.Context, models.WebhookEventUser) error) {
+	r.onUser(models.WebhookEventNameUserCreated, h)
+}
+
+// OnUserUpdated registers the handler of the user.updated event.
+func (r *Router) OnUserUpdated(h func(context.Context, models.WebhookEventUser) error) {
+	r.onUser(models.WebhookEventNameUserUpdated, h)
+}
+
+// OnUserDeleted registers the handler of the user.deleted event.
+func (r *Router) OnUserDeleted(h func(context.Context, models.WebhookEventUser) error) {
+	r.onUser(models.WebhookEventNameUserDeleted, h)
+}
+
+func (r *Router) onUser(name models.WebhookEventName, h func(context.Context, models.WebhookEventUser) error) {
+	r.On(name, func(ctx context.Context, event interface{}) error {
+		return h(ctx, event.(models.WebhookEventUser))
+	})
+}
+
+// OnOrganizationMembershipCreated registers the handler of the
+// organization_membership.created event.
+func (r *Router) OnOrganizationMembershipCreated(h func(context.Context, models.WebhookEventOrganizationMembership) error) {
+	r.onOrganizationMembership(models.WebhookEventNameOrganizationMembershipCreated, h)
+}
+
+// OnOrganizationMembershipUpdated registers the handler of the
+// organization_membership.updated event.
+func (r *Router) OnOrganizationMembershipUpdated(h func(context.Context, models.WebhookEventOrganizationMembership) error) {
+	r.onOrganizationMembership(models.WebhookEventNameOrganizationMembershipUpdated, h)
+}
+
+// OnOrganizationMembershipDeleted registers the handler of the
+// organization_membership.deleted event.
+func (r *Router) OnOrganizationMembershipDeleted(h func(context.Context, models.WebhookEventOrganizationMembership) error) {
+	r.onOrganizationMembership(models.WebhookEventNameOrganizationMembershipDeleted, h)
+}
+
+func (r *Router) onOrganizationMembership(name models.WebhookEventName, h func(context.Context, models.WebhookEventOrganizationMembership) error) {
+	r.On(name, func(ctx context.Context, event interface{}) error {
+		return h(ctx, event.(models.WebhookEventOrganizationMembership))
+	})
+}
+
+// OnSessionCreated registers the handler of the session.created event.
+func (r *Router) OnSessionCreated(h func(context.Context, models.WebhookEventSessionCreated) error) {
+	r.On(models.WebhookEventNameSessionCreated, func(ctx context.Context, event interface{}) error {
+		return h(ctx, event.(models.WebhookEventSessionCreated))
+	})
+}
+
+// OnMagicAuthCreated registers the handler of the magic_auth.created event.
+func (r *Router) OnMagicAuthCreated(h func(context.Context, models.WebhookEventMagicAuthCreated) error) {
+	r.On(models.WebhookEventNameMagicAuthCreated, func(ctx context.Context, event interface{}) error {
+		return h(ctx, event.(models.WebhookEventMagicAuthCreated))
+	})
+}
+
+// Dispatch parses a webhook payload and calls the handler of its event. The
+// payload must have been validated with ValidatePayload first.
+//
+// Errors returned by ParseEvent are returned as is, and so are the errors
+// returned by handlers.
+func (r *Router) Dispatch(ctx context.Context, body []byte) error {
+	header, err := eventHeader(body)
+	if err != nil {
+		return err
+	}
+
+	if r.handler(header.Event) == nil {
+		return nil
+	}
+
+	event, err := parseEvent(header.Event, body)
+	if err != nil {
+		return err
+	}
+	return r.route(ctx, header.Event, event)
+}
+
+// handler returns the handler of an event, or nil when it is ignored.
+func (r *Router) handler(name models.WebhookEventName) HandlerFunc {
+	if h, ok := r.handlers[name]; ok {
+		return h
+	}
+	return r.fallback
+}
+
+func (r *Router) route(ctx context.Context, name models.WebhookEventName, event interface{}) error {
+	if h := r.handler(name); h != nil {
+		return h(ctx, event)
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package webhooks_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/omi-lab/workos-go/v4/pkg/models"
+	"github.com/omi-lab/workos-go/v4/pkg/webhooks"
+)
+
+func TestRouter(t *testing.T) {
+	var activated, fallback []string
+
+	router := webhooks.NewRouter()
+	router.OnConnectionActivated(func(ctx context.Context, e models.WebhookEventConnection) error {
+		activated = append(activated, e.Data.ID)
+		return nil
+	})
+	router.Fallback(func(ctx context.Context, e interface{}) error {
+		fallback = append(fallback, e.(webhooks.UnknownEvent).Event)
+		return nil
+	})
+
+	ctx := context.Background()
+	if err := router.Dispatch(ctx, []byte(connectionActivatedPayload)); err != nil {
+		t.Fatalf("expected no error, but got %v", err)
+	}
+	if err := router.Dispatch(ctx, []byte(`{"id": "wh_02", "event": "unknown.event"}`)); err != nil {
+		t.Fatalf("expected no error, but got %v", err)
+	}
+
+	if len(activated) != 1 || activated[0] != "conn_01" {
+		t.Errorf("unexpected activated connections: %v", activated)
+	}
+	if len(fallback) != 1 || fallback[0] != "unknown.event" {
+		t.Errorf("unexpected fallback events: %v", fallback)
+	}
+}
+
+func TestRouterReturnsHandlerErrors(t *testing.T) {
+	failure := errors.New("failure")
+
+	router := webhooks.NewRouter()
+	router.OnConnectionActivated(func(ctx context.Context, e models.WebhookEventConnection) error {
+		return failure
+	})
+
+	if err := router.Dispatch(context.Background(), []byte(connectionActivatedPayload)); err != failure {
+		t.Errorf("expected the handler error, but got %v", err)
+	}
+}
+
+func TestRouterIgnoresUnhandledEvents(t *testing.T) {
+	router := webhooks.NewRouter()
+	if err := router.Dispatch(context.Background(), []byte(connectionActivatedPayload)); err != nil {
+		t.Errorf("expected no error, but got %v", err)
+	}
+}
+
+func TestRouterTypedHandlers(t *testing.T) {
+	var users []string
+	router := webhooks.NewRouter()
+	router.OnDirectoryUserUpdated(func(ctx context.Context, e models.WebhookEventDirectoryUser) error {
+		users = append(users, e.Data.ID)
+		return nil
+	})
+
+	payload := `{"id": "wh_01", "event": "dsync.user.updated", "data": {"id": "directory_user_01"}}`
+	if err := router.Dispatch(context.Background(), []byte(payload)); err != nil {
+		t.Fatalf("expected no error, but got %v", err)
+	}
+	if len(users) != 1 || users[0] != "directory_user_01" {
+		t.Errorf("unexpected directory users: %v", users)
+	}
+}
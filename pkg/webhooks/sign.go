@@ -0,0 +1,28 @@
+package webhooks
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"time"
+)
+
+// Sign returns the WorkOS-Signature header WorkOS would send along with a
+// webhook body signed with the given secret at the given time. It is meant
+// for testing the endpoints that receive webhooks.
+func Sign(secret string, body string, t time.Time) string {
+	timestamp := strconv.FormatInt(t.Round(0).UnixNano()/int64(time.Millisecond), 10)
+	signature := computeSignature(secret, timestamp, body)
+	return "t=" + timestamp + ", v1=" + hex.EncodeToString(signature)
+}
+
+// computeSignature computes the signature of a webhook body sent at the given
+// raw timestamp.
+func computeSignature(secret string, rawTimestamp string, body string) []byte {
+	hash := hmac.New(sha256.New, []byte(secret))
+	hash.Write([]byte(rawTimestamp))
+	hash.Write([]byte("."))
+	hash.Write([]byte(body))
+	return hash.Sum(nil)
+}
@@ -0,0 +1,45 @@
+package webhooks_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/omi-lab/workos-go/v4/pkg/webhooks"
+)
+
+func TestSign(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	body := `{"id": "wh_01", "event": "connection.activated"}`
+
+	header := webhooks.Sign("secret", body, now)
+	if expected := mockWebhookHeader(now, "secret", body); header != expected {
+		t.Errorf("expected %s, but got %s", expected, header)
+	}
+
+	client := webhooks.NewClient("secret")
+	client.SetNow(func() time.Time { return now.Add(time.Second) })
+
+	verification, err := client.Verify(header, body)
+	if err != nil {
+		t.Fatalf("expected no error, but got %v", err)
+	}
+	if !verification.Timestamp.Equal(now) {
+		t.Errorf("expected timestamp %v, but got %v", now, verification.Timestamp)
+	}
+}
+
+func TestSignKeepsMilliseconds(t *testing.T) {
+	now := time.Unix(1700000000, 123456789)
+	body := `{"id": "wh_01", "event": "connection.activated"}`
+
+	header := webhooks.Sign("secret", body, now)
+	if expected := "t=1700000000123, v1="; header[:len(expected)] != expected {
+		t.Errorf("expected a header starting with %s, but got %s", expected, header)
+	}
+
+	client := webhooks.NewClient("secret")
+	client.SetNow(func() time.Time { return now })
+	if _, err := client.Verify(header, body); err != nil {
+		t.Errorf("expected no error, but got %v", err)
+	}
+}
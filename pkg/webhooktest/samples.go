@@ -0,0 +1,247 @@
+package webhooktest
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/omi-lab/workos-go/v4/pkg/common"
+	"github.com/omi-lab/workos-go/v4/pkg/models"
+)
+
+// sampleTime is the creation time of the sample objects.
+var sampleTime = time.Date(2024, time.January, 1, 12, 0, 0, 0, time.UTC)
+
+func sampleConnection() interface{} {
+	return models.Connection{
+		ID:             "conn_01",
+		Status:         models.ConnectionStatusLinked,
+		State:          models.ConnectionStateActive,
+		Name:           "Foo Corp",
+		ConnectionType: models.ConnectionTypeOktaSAML,
+		OrganizationID: "org_01",
+		Domains: []models.ConnectionDomain{
+			{ID: "conn_domain_01", Domain: "foo-corp.com"},
+		},
+		CreatedAt: sampleTime,
+		UpdatedAt: sampleTime,
+	}
+}
+
+func sampleDirectory() interface{} {
+	return models.Directory{
+		ID:             "directory_01",
+		Name:           "Foo Corp",
+		Domain:         "foo-corp.com",
+		Type:           models.DirectoryTypeOktaSCIMV2_0,
+		State:          models.DirectoryStateLinked,
+		OrganizationID: "org_01",
+		CreatedAt:      sampleTime,
+		UpdatedAt:      sampleTime,
+	}
+}
+
+func directoryUser() models.DirectoryUser {
+	return models.DirectoryUser{
+		ID:             "directory_user_01",
+		IdpID:          "idp_user_01",
+		DirectoryID:    "directory_01",
+		OrganizationID: "org_01",
+		Username:       "marcelina@foo-corp.com",
+		Emails: []models.DirectoryUserEmail{
+			{Primary: true, Value: "marcelina@foo-corp.com", Type: "work"},
+		},
+		FirstName:        "Marcelina",
+		LastName:         "Davis",
+		JobTitle:         "Software Engineer",
+		State:            models.DirectoryUserStateActive,
+		RawAttributes:    json.RawMessage(`{}`),
+		CustomAttributes: json.RawMessage(`{}`),
+		CreatedAt:        sampleTime,
+		UpdatedAt:        sampleTime,
+		Role:             common.RoleResponse{Slug: "member"},
+	}
+}
+
+func directoryGroup() models.DirectoryGroup {
+	return models.DirectoryGroup{
+		ID:             "directory_group_01",
+		Name:           "Engineering",
+		IdpID:          "idp_group_01",
+		DirectoryID:    "directory_01",
+		OrganizationID: "org_01",
+		CreatedAt:      sampleTime,
+		UpdatedAt:      sampleTime,
+		RawAttributes:  json.RawMessage(`{}`),
+	}
+}
+
+func sampleDirectoryUser() interface{} {
+	return directoryUser()
+}
+
+func sampleDirectoryGroup() interface{} {
+	return directoryGroup()
+}
+
+func sampleDirectoryGroupMembership() interface{} {
+	return models.DirectoryGroupMembership{
+		DirectoryID: "directory_01",
+		User:        directoryUser(),
+		Group:       directoryGroup(),
+	}
+}
+
+func sampleOrganization() interface{} {
+	return models.Organization{
+		ID:   "org_01",
+		Name: "Foo Corp",
+		Domains: []models.OrganizationDomain{
+			{ID: "org_domain_01", Domain: "foo-corp.com"},
+		},
+		CreatedAt: sampleTime,
+		UpdatedAt: sampleTime,
+	}
+}
+
+func sampleUser() interface{} {
+	return models.User{
+		ID:            "user_01",
+		FirstName:     "Marcelina",
+		LastName:      "Davis",
+		Email:         "marcelina@foo-corp.com",
+		EmailVerified: true,
+		CreatedAt:     sampleTime,
+		UpdatedAt:     sampleTime,
+	}
+}
+
+func sampleOrganizationMembership() interface{} {
+	return models.OrganizationMembership{
+		ID:             "om_01",
+		UserID:         "user_01",
+		OrganizationID: "org_01",
+		Role:           common.RoleResponse{Slug: "member"},
+		Status:         models.OrganizationMembershipStatusActive,
+		CreatedAt:      sampleTime,
+		UpdatedAt:      sampleTime,
+	}
+}
+
+func sampleSession() interface{} {
+	return models.UserSession{
+		ID:             "session_01",
+		UserID:         "user_01",
+		OrganizationID: "org_01",
+		IPAddress:      "192.0.2.1",
+		UserAgent:      "Mozilla/5.0",
+		CreatedAt:      sampleTime,
+		UpdatedAt:      sampleTime,
+	}
+}
+
+func sampleEmailVerification() interface{} {
+	return models.EmailVerification{
+		ID:        "email_verification_01",
+		UserId:    "user_01",
+		Email:     "marcelina@foo-corp.com",
+		ExpiresAt: sampleTime.Add(10 * time.Minute),
+		Code:      "123456",
+		CreatedAt: sampleTime,
+		UpdatedAt: sampleTime,
+	}
+}
+
+func sampleInvitation() interface{} {
+	return models.Invitation{
+		ID:                  "invitation_01",
+		Email:               "marcelina@foo-corp.com",
+		State:               models.InvitationStatePending,
+		Token:               "Z1uX3RbwcIl5fIGJJJCXXisdI",
+		AcceptInvitationUrl: "https://your-app.com/invite?invitation_token=Z1uX3RbwcIl5fIGJJJCXXisdI",
+		OrganizationID:      "org_01",
+		InviterUserID:       "user_02",
+		ExpiresAt:           sampleTime.Add(7 * 24 * time.Hour),
+		CreatedAt:           sampleTime,
+		UpdatedAt:           sampleTime,
+	}
+}
+
+func sampleMagicAuth() interface{} {
+	return models.MagicAuth{
+		ID:        "magic_auth_01",
+		UserId:    "user_01",
+		Email:     "marcelina@foo-corp.com",
+		ExpiresAt: sampleTime.Add(10 * time.Minute),
+		Code:      "123456",
+		CreatedAt: sampleTime,
+		UpdatedAt: sampleTime,
+	}
+}
+
+func samplePasswordReset() interface{} {
+	return models.PasswordReset{
+		ID:                 "password_reset_01",
+		UserId:             "user_01",
+		Email:              "marcelina@foo-corp.com",
+		PasswordResetToken: "Z1uX3RbwcIl5fIGJJJCXXisdI",
+		PasswordResetUrl:   "https://your-app.com/reset-password?token=Z1uX3RbwcIl5fIGJJJCXXisdI",
+		ExpiresAt:          sampleTime.Add(10 * time.Minute),
+		CreatedAt:          sampleTime,
+	}
+}
+
+// samples maps every event to the function that builds its sample data.
+var samples = map[string]func() interface{}{
+	models.EventConnectionActivated:           sampleConnection,
+	models.EventConnectionDeactivated:         sampleConnection,
+	models.EventConnectionDeleted:             sampleConnection,
+	models.EventDirectoryActivated:            sampleDirectory,
+	models.EventDirectoryDeleted:              sampleDirectory,
+	models.EventDirectoryUserCreated:          sampleDirectoryUser,
+	models.EventDirectoryUserUpdated:          sampleDirectoryUser,
+	models.EventDirectoryUserDeleted:          sampleDirectoryUser,
+	models.EventDirectoryGroupCreated:         sampleDirectoryGroup,
+	models.EventDirectoryGroupUpdated:         sampleDirectoryGroup,
+	models.EventDirectoryGroupDeleted:         sampleDirectoryGroup,
+	models.EventDirectoryGroupUserAdded:       sampleDirectoryGroupMembership,
+	models.EventDirectoryGroupUserRemoved:     sampleDirectoryGroupMembership,
+	models.EventOrganizationCreated:           sampleOrganization,
+	models.EventOrganizationUpdated:           sampleOrganization,
+	models.EventOrganizationDeleted:           sampleOrganization,
+	models.EventUserCreated:                   sampleUser,
+	models.EventUserUpdated:                   sampleUser,
+	models.EventUserDeleted:                   sampleUser,
+	models.EventOrganizationMembershipAdded:   sampleOrganizationMembership,
+	models.EventOrganizationMembershipCreated: sampleOrganizationMembership,
+	models.EventOrganizationMembershipDeleted: sampleOrganizationMembership,
+	models.EventOrganizationMembershipUpdated: sampleOrganizationMembership,
+	models.EventOrganizationMembershipRemoved: sampleOrganizationMembership,
+	models.EventSessionCreated:                sampleSession,
+	models.EventEmailVerificationCreated:      sampleEmailVerification,
+	models.EventInvitationCreated:             sampleInvitation,
+	models.EventMagicAuthCreated:              sampleMagicAuth,
+	models.EventPasswordResetCreated:          samplePasswordReset,
+}
+
+// SampleEvents returns the names of the events that have sample data, sorted.
+// It covers every models.WebhookEventName and models.Event constant.
+func SampleEvents() []string {
+	events := make([]string, 0, len(samples))
+	for event := range samples {
+		events = append(events, event)
+	}
+	sort.Strings(events)
+	return events
+}
+
+// SampleData returns the sample data of an event, typed as the object the
+// event is about, e.g. a models.Connection for connection.activated. It
+// returns false when the event has no sample data.
+func SampleData(event string) (interface{}, bool) {
+	sample, ok := samples[event]
+	if !ok {
+		return nil, false
+	}
+	return sample(), true
+}
@@ -0,0 +1,234 @@
+// Package webhooktest sends signed webhooks to an endpoint the way WorkOS
+// does, to test it end to end:
+//
+//	sender := &webhooktest.Sender{
+//		URL:    server.URL + "/webhooks",
+//		Secret: "secret",
+//	}
+//	sender.SendSample(ctx, models.EventUserCreated)
+//
+// Every event has sample data typed as the object it is about, see
+// SampleEvents and SampleData.
+package webhooktest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/omi-lab/workos-go/v4/pkg/models"
+	"github.com/omi-lab/workos-go/v4/pkg/webhooks"
+)
+
+const (
+	// DefaultMaxAttempts is the default number of times an event is sent
+	// before giving up. WorkOS makes up to 6 attempts.
+	DefaultMaxAttempts = 6
+
+	// DefaultBackoff is the default delay before the first retry. It doubles
+	// after each attempt. WorkOS waits much longer between retries, this is
+	// scaled down for tests.
+	DefaultBackoff = 10 * time.Millisecond
+)
+
+// ErrDeliveryFailed is returned when an event was not acknowledged after the
+// maximum number of attempts.
+var ErrDeliveryFailed = errors.New("webhooktest: delivery failed")
+
+// ErrNoSample is returned when sending the sample of an event that has no
+// sample data.
+var ErrNoSample = errors.New("webhooktest: event has no sample data")
+
+// Delivery describes an event sent by a Sender.
+type Delivery struct {
+	// The ID of the event. It is the same across attempts.
+	ID string
+
+	// The name of the event.
+	Event string
+
+	// The payload of the event.
+	Body []byte
+
+	// The number of attempts made to send the event.
+	Attempts int
+
+	// The status code of the last response, or 0 when no response was
+	// received.
+	Status int
+}
+
+// Sender sends signed webhooks to an endpoint.
+//
+// Like WorkOS, it retries an event with an exponential backoff until the
+// endpoint responds with a 2xx status code. The event keeps its ID across
+// attempts and each attempt is signed with a fresh timestamp.
+type Sender struct {
+	// The URL of the endpoint.
+	URL string
+
+	// The secret the webhooks are signed with.
+	Secret string
+
+	// The http.Client used to send the webhooks. Defaults to
+	// http.DefaultClient.
+	HTTPClient *http.Client
+
+	// The maximum number of attempts for each event. Defaults to
+	// DefaultMaxAttempts.
+	MaxAttempts int
+
+	// The delay before the first retry, doubled after each attempt. Defaults
+	// to DefaultBackoff.
+	Backoff time.Duration
+
+	// The function used to determine the current time. Defaults to time.Now.
+	Now func() time.Time
+
+	mu  sync.Mutex
+	seq int
+}
+
+// Send sends an event with the given data, encoded to JSON.
+//
+// It returns an error wrapping ErrDeliveryFailed when the endpoint did not
+// acknowledge the event after the maximum number of attempts, and the error
+// of the context when it is done before.
+func (s *Sender) Send(ctx context.Context, event string, data interface{}) (Delivery, error) {
+	rawData, err := json.Marshal(data)
+	if err != nil {
+		return Delivery{}, err
+	}
+
+	e := webhooks.UnknownEvent{
+		WebhookEvent: models.WebhookEvent{
+			ID:        s.newID(),
+			Event:     event,
+			CreatedAt: s.now().UTC(),
+		},
+		Data: rawData,
+	}
+	body, err := json.Marshal(e)
+	if err != nil {
+		return Delivery{}, err
+	}
+
+	delivery := Delivery{
+		ID:    e.ID,
+		Event: event,
+		Body:  body,
+	}
+	return delivery, s.deliver(ctx, &delivery)
+}
+
+// SendSample sends an event with its sample data. It returns ErrNoSample when
+// the event has no sample data.
+func (s *Sender) SendSample(ctx context.Context, event string) (Delivery, error) {
+	data, ok := SampleData(event)
+	if !ok {
+		return Delivery{}, ErrNoSample
+	}
+	return s.Send(ctx, event, data)
+}
+
+// SendAll sends the sample of every event returned by SampleEvents, in order.
+// It stops at the first event that is not delivered.
+func (s *Sender) SendAll(ctx context.Context) ([]Delivery, error) {
+	var deliveries []Delivery
+	for _, event := range SampleEvents() {
+		delivery, err := s.SendSample(ctx, event)
+		if err != nil {
+			return deliveries, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries, nil
+}
+
+func (s *Sender) deliver(ctx context.Context, delivery *Delivery) error {
+	maxAttempts := s.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = DefaultMaxAttempts
+	}
+
+	backoff := s.Backoff
+	if backoff <= 0 {
+		backoff = DefaultBackoff
+	}
+
+	var lastErr error
+	for {
+		delivery.Attempts++
+		delivery.Status, lastErr = s.post(ctx, delivery.Body)
+		if lastErr == nil && delivery.Status >= 200 && delivery.Status < 300 {
+			return nil
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if delivery.Attempts >= maxAttempts {
+			break
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+		backoff *= 2
+	}
+
+	if lastErr != nil {
+		return fmt.Errorf("%w: %s %s after %d attempts: %v", ErrDeliveryFailed, delivery.Event, delivery.ID, delivery.Attempts, lastErr)
+	}
+	return fmt.Errorf("%w: %s %s after %d attempts: status %d", ErrDeliveryFailed, delivery.Event, delivery.ID, delivery.Attempts, delivery.Status)
+}
+
+// post sends one attempt and returns the status code of the response.
+func (s *Sender) post(ctx context.Context, body []byte) (int, error) {
+	req, err := http.NewRequest(http.MethodPost, s.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(webhooks.SignatureHeader, webhooks.Sign(s.Secret, string(body), s.now()))
+
+	client := s.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	res, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+
+	io.Copy(ioutil.Discard, res.Body)
+	return res.StatusCode, nil
+}
+
+func (s *Sender) newID() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.seq++
+	return fmt.Sprintf("wh_%026d", s.seq)
+}
+
+func (s *Sender) now() time.Time {
+	if s.Now != nil {
+		return s.Now()
+	}
+	return time.Now()
+}
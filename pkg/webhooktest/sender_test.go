@@ -0,0 +1,177 @@
+package webhooktest_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/omi-lab/workos-go/v4/pkg/models"
+	"github.com/omi-lab/workos-go/v4/pkg/webhooks"
+	"github.com/omi-lab/workos-go/v4/pkg/webhooktest"
+)
+
+const secret = "secret"
+
+func TestSendAll(t *testing.T) {
+	var received []interface{}
+
+	router := webhooks.NewRouter()
+	router.Fallback(func(ctx context.Context, event interface{}) error {
+		received = append(received, event)
+		return nil
+	})
+
+	var connections []models.WebhookEventConnection
+	router.OnConnectionActivated(func(ctx context.Context, e models.WebhookEventConnection) error {
+		connections = append(connections, e)
+		return nil
+	})
+
+	server := httptest.NewServer(webhooks.Handler(secret, router))
+	defer server.Close()
+
+	sender := &webhooktest.Sender{URL: server.URL, Secret: secret}
+	deliveries, err := sender.SendAll(context.Background())
+	require.NoError(t, err)
+	require.Len(t, deliveries, len(webhooktest.SampleEvents()))
+
+	var connectionID string
+	for _, delivery := range deliveries {
+		require.Equal(t, 1, delivery.Attempts)
+		require.Equal(t, http.StatusOK, delivery.Status)
+		if delivery.Event == models.WebhookEventNameConnectionActivated {
+			connectionID = delivery.ID
+		}
+	}
+	require.Len(t, received, len(deliveries)-1)
+	require.Len(t, connections, 1)
+	require.Equal(t, connectionID, connections[0].ID)
+	require.False(t, connections[0].CreatedAt.IsZero())
+	require.Equal(t, "conn_01", connections[0].Data.ID)
+	require.Equal(t, models.ConnectionStateActive, connections[0].Data.State)
+}
+
+func TestSampleEvents(t *testing.T) {
+	events := webhooktest.SampleEvents()
+
+	for _, event := range []string{
+		models.WebhookEventNameEmailVerificationCreated,
+		models.WebhookEventNamePasswordResetCreated,
+		models.WebhookEventNameConnectionActivated,
+		models.WebhookEventNameConnectionDeactivated,
+		models.WebhookEventNameConnectionDeleted,
+		models.WebhookEventNameInvitationCreated,
+		models.EventConnectionDeactivated,
+		models.EventDirectoryActivated,
+		models.EventDirectoryUserCreated,
+		models.EventDirectoryGroupUserAdded,
+		models.EventDirectoryGroupUserRemoved,
+		models.EventOrganizationMembershipCreated,
+		models.EventSessionCreated,
+		models.EventMagicAuthCreated,
+	} {
+		require.Contains(t, events, event)
+	}
+
+	// The samples have the type models.Event decodes the data of the event
+	// into.
+	for _, event := range events {
+		data, _ := webhooktest.SampleData(event)
+		b, err := json.Marshal(data)
+		require.NoError(t, err)
+
+		decoded, err := models.Event{Event: event, Data: b}.Decode()
+		require.NoError(t, err, event)
+		require.Equal(t, data, decoded, event)
+	}
+
+	data, ok := webhooktest.SampleData(models.EventDirectoryUserCreated)
+	require.True(t, ok)
+	require.IsType(t, models.DirectoryUser{}, data)
+
+	_, ok = webhooktest.SampleData("unknown.event")
+	require.False(t, ok)
+}
+
+func TestSendRetries(t *testing.T) {
+	var ids []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		_, err = webhooks.NewClient(secret).Verify(r.Header.Get(webhooks.SignatureHeader), string(body))
+		require.NoError(t, err)
+
+		event, err := webhooks.ParseEvent(body)
+		require.NoError(t, err)
+		ids = append(ids, event.(models.WebhookEventUser).ID)
+
+		if len(ids) < 3 {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	sender := &webhooktest.Sender{
+		URL:     server.URL,
+		Secret:  secret,
+		Backoff: time.Millisecond,
+	}
+	delivery, err := sender.SendSample(context.Background(), models.EventUserCreated)
+	require.NoError(t, err)
+	require.Equal(t, 3, delivery.Attempts)
+	require.Equal(t, http.StatusOK, delivery.Status)
+	require.Equal(t, []string{delivery.ID, delivery.ID, delivery.ID}, ids)
+}
+
+func TestSendGivesUp(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	sender := &webhooktest.Sender{
+		URL:         server.URL,
+		Secret:      secret,
+		MaxAttempts: 2,
+		Backoff:     time.Millisecond,
+	}
+	delivery, err := sender.SendSample(context.Background(), models.EventUserCreated)
+	require.True(t, errors.Is(err, webhooktest.ErrDeliveryFailed))
+	require.Equal(t, 2, delivery.Attempts)
+	require.Equal(t, http.StatusServiceUnavailable, delivery.Status)
+	require.Equal(t, 2, attempts)
+}
+
+func TestSendStopsWhenContextIsDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cancel()
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	sender := &webhooktest.Sender{
+		URL:     server.URL,
+		Secret:  secret,
+		Backoff: time.Hour,
+	}
+	delivery, err := sender.SendSample(ctx, models.EventUserCreated)
+	require.Equal(t, context.Canceled, err)
+	require.Equal(t, 1, delivery.Attempts)
+}
+
+func TestSendSampleWithoutSample(t *testing.T) {
+	sender := &webhooktest.Sender{URL: "http://127.0.0.1:0", Secret: secret}
+	_, err := sender.SendSample(context.Background(), "unknown.event")
+	require.Equal(t, webhooktest.ErrNoSample, err)
+}
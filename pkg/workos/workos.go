@@ -0,0 +1,222 @@
+// Package `workos` provides a client that wires every WorkOS product client
+// from a single configuration.
+//
+// Example:
+//
+//	func main() {
+//	    client := workos.New(workos.Config{
+//	        APIKey:   "my_api_key",
+//	        ClientID: "my_client_id",
+//	    })
+//
+//	    org, err := client.Organizations().GetOrganization(
+//	        context.Background(),
+//	        organizations.GetOrganizationOpts{Organization: "org_01EHZNVPK3SFK441A1RGBFSHRT"},
+//	    )
+//	    if err != nil {
+//	        // Handle error.
+//	    }
+//	}
+package workos
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/omi-lab/workos-go/v4/pkg/auditlogs"
+	"github.com/omi-lab/workos-go/v4/pkg/common"
+	"github.com/omi-lab/workos-go/v4/pkg/directorysync"
+	"github.com/omi-lab/workos-go/v4/pkg/events"
+	"github.com/omi-lab/workos-go/v4/pkg/mfa"
+	"github.com/omi-lab/workos-go/v4/pkg/organizations"
+	"github.com/omi-lab/workos-go/v4/pkg/passwordless"
+	"github.com/omi-lab/workos-go/v4/pkg/portal"
+	"github.com/omi-lab/workos-go/v4/pkg/sso"
+	"github.com/omi-lab/workos-go/v4/pkg/usermanagement"
+)
+
+// Config contains the settings shared by every product client.
+type Config struct {
+	// The WorkOS API key. It can be found in
+	// https://dashboard.workos.com/api-keys.
+	//
+	// REQUIRED.
+	APIKey string
+
+	// The WorkOS Client ID (eg. client_01JG3BCPTRTSTTWQR4VSHXGWCQ).
+	ClientID string
+
+	// The endpoint to WorkOS API.
+	//
+	// Defaults to https://api.workos.com.
+	Endpoint string
+
+	// The http.Client that is used to send request to WorkOS.
+	//
+	// Defaults to http.Client with a 10 seconds timeout.
+	HTTPClient *http.Client
+
+	// The policy used to retry requests that fail with a transient error.
+	//
+	// Defaults to common.DefaultRetryPolicy.
+	RetryPolicy *common.RetryPolicy
+
+	// The middlewares that requests are sent through, outermost first.
+	Middlewares []common.Middleware
+
+	// The function used to encode in JSON. Defaults to json.Marshal.
+	JSONEncode func(v interface{}) ([]byte, error)
+}
+
+// Client gives access to every WorkOS product client. The product clients
+// share the same configuration and send their requests through the same
+// http.Client.
+type Client struct {
+	auditLogs      *auditlogs.Client
+	directorySync  *directorysync.Client
+	events         *events.Client
+	mfa            *mfa.Client
+	organizations  *organizations.Client
+	passwordless   *passwordless.Client
+	portal         *portal.Client
+	sso            *sso.Client
+	userManagement *usermanagement.Client
+}
+
+// New returns a Client configured with the given settings.
+func New(cfg Config) *Client {
+	if cfg.Endpoint == "" {
+		cfg.Endpoint = "https://api.workos.com"
+	}
+	cfg.Endpoint = strings.TrimSuffix(cfg.Endpoint, "/")
+
+	if cfg.HTTPClient == nil {
+		cfg.HTTPClient = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	if cfg.JSONEncode == nil {
+		cfg.JSONEncode = json.Marshal
+	}
+
+	return &Client{
+		auditLogs: &auditlogs.Client{
+			APIKey:      cfg.APIKey,
+			HTTPClient:  cfg.HTTPClient,
+			RetryPolicy: cfg.RetryPolicy,
+			Middlewares: cfg.Middlewares,
+			Endpoint:    cfg.Endpoint,
+			JSONEncode:  cfg.JSONEncode,
+		},
+		directorySync: &directorysync.Client{
+			APIKey:      cfg.APIKey,
+			HTTPClient:  cfg.HTTPClient,
+			RetryPolicy: cfg.RetryPolicy,
+			Middlewares: cfg.Middlewares,
+			Endpoint:    cfg.Endpoint,
+		},
+		events: &events.Client{
+			APIKey:      cfg.APIKey,
+			HTTPClient:  cfg.HTTPClient,
+			RetryPolicy: cfg.RetryPolicy,
+			Middlewares: cfg.Middlewares,
+			Endpoint:    cfg.Endpoint,
+		},
+		mfa: &mfa.Client{
+			APIKey:      cfg.APIKey,
+			HTTPClient:  cfg.HTTPClient,
+			RetryPolicy: cfg.RetryPolicy,
+			Middlewares: cfg.Middlewares,
+			Endpoint:    cfg.Endpoint,
+			JSONEncode:  cfg.JSONEncode,
+		},
+		organizations: &organizations.Client{
+			APIKey:      cfg.APIKey,
+			HTTPClient:  cfg.HTTPClient,
+			RetryPolicy: cfg.RetryPolicy,
+			Middlewares: cfg.Middlewares,
+			Endpoint:    cfg.Endpoint,
+			JSONEncode:  cfg.JSONEncode,
+		},
+		passwordless: &passwordless.Client{
+			APIKey:      cfg.APIKey,
+			HTTPClient:  cfg.HTTPClient,
+			RetryPolicy: cfg.RetryPolicy,
+			Middlewares: cfg.Middlewares,
+			Endpoint:    cfg.Endpoint,
+			JSONEncode:  cfg.JSONEncode,
+		},
+		portal: &portal.Client{
+			APIKey:      cfg.APIKey,
+			HTTPClient:  cfg.HTTPClient,
+			RetryPolicy: cfg.RetryPolicy,
+			Middlewares: cfg.Middlewares,
+			Endpoint:    cfg.Endpoint,
+			JSONEncode:  cfg.JSONEncode,
+		},
+		sso: &sso.Client{
+			APIKey:      cfg.APIKey,
+			ClientID:    cfg.ClientID,
+			Endpoint:    cfg.Endpoint,
+			HTTPClient:  cfg.HTTPClient,
+			RetryPolicy: cfg.RetryPolicy,
+			Middlewares: cfg.Middlewares,
+			JSONEncode:  cfg.JSONEncode,
+		},
+		userManagement: &usermanagement.Client{
+			APIKey:      cfg.APIKey,
+			ClientID:    cfg.ClientID,
+			HTTPClient:  cfg.HTTPClient,
+			RetryPolicy: cfg.RetryPolicy,
+			Middlewares: cfg.Middlewares,
+			Endpoint:    cfg.Endpoint,
+			JSONEncode:  cfg.JSONEncode,
+		},
+	}
+}
+
+// AuditLogs returns the Audit Logs client.
+func (c *Client) AuditLogs() *auditlogs.Client {
+	return c.auditLogs
+}
+
+// DirectorySync returns the Directory Sync client.
+func (c *Client) DirectorySync() *directorysync.Client {
+	return c.directorySync
+}
+
+// Events returns the Events client.
+func (c *Client) Events() *events.Client {
+	return c.events
+}
+
+// MFA returns the MFA client.
+func (c *Client) MFA() *mfa.Client {
+	return c.mfa
+}
+
+// Organizations returns the Organizations client.
+func (c *Client) Organizations() *organizations.Client {
+	return c.organizations
+}
+
+// Passwordless returns the Passwordless client.
+func (c *Client) Passwordless() *passwordless.Client {
+	return c.passwordless
+}
+
+// Portal returns the Admin Portal client.
+func (c *Client) Portal() *portal.Client {
+	return c.portal
+}
+
+// SSO returns the SSO client.
+func (c *Client) SSO() *sso.Client {
+	return c.sso
+}
+
+// UserManagement returns the User Management client.
+func (c *Client) UserManagement() *usermanagement.Client {
+	return c.userManagement
+}
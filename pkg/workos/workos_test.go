@@ -0,0 +1,77 @@
+package workos
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/omi-lab/workos-go/v4/pkg/auditlogs"
+	"github.com/omi-lab/workos-go/v4/pkg/organizations"
+	"github.com/omi-lab/workos-go/v4/pkg/usermanagement"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test" {
+			http.Error(w, "bad auth", http.StatusUnauthorized)
+			return
+		}
+
+		paths = append(paths, r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client := New(Config{
+		APIKey:     "test",
+		ClientID:   "client_123",
+		Endpoint:   server.URL + "/",
+		HTTPClient: server.Client(),
+	})
+
+	_, err := client.Organizations().GetOrganization(context.Background(), organizations.GetOrganizationOpts{
+		Organization: "org_123",
+	})
+	require.NoError(t, err)
+
+	err = client.AuditLogs().CreateEvent(context.Background(), auditlogs.CreateEventOpts{
+		OrganizationID: "org_123",
+	})
+	require.NoError(t, err)
+
+	_, err = client.AuditLogs().GetExport(context.Background(), auditlogs.GetExportOpts{
+		ExportID: "audit_log_export_123",
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, []string{
+		"/organizations/org_123",
+		"/audit_logs/events",
+		"/audit_logs/exports/audit_log_export_123",
+	}, paths)
+
+	u, err := client.UserManagement().GetAuthorizationURL(usermanagement.GetAuthorizationURLOpts{
+		RedirectURI: "https://example.com/callback",
+		Provider:    "authkit",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "client_123", u.Query().Get("client_id"))
+	require.Equal(t, "client_123", client.SSO().ClientID)
+
+	require.Same(t, client.SSO().HTTPClient, client.DirectorySync().HTTPClient)
+	require.Same(t, client.Events().HTTPClient, client.UserManagement().HTTPClient)
+}
+
+func TestNewDefaults(t *testing.T) {
+	client := New(Config{APIKey: "test"})
+
+	require.Equal(t, "https://api.workos.com", client.MFA().Endpoint)
+	require.Equal(t, "https://api.workos.com", client.Portal().Endpoint)
+	require.NotNil(t, client.Passwordless().HTTPClient)
+	require.NotNil(t, client.UserManagement().JSONEncode)
+}
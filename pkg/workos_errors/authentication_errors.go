@@ -14,6 +14,8 @@ func (e ErrorEmailVerificationRequired) Error() string {
 	return e.Message
 }
 
+func (ErrorEmailVerificationRequired) authenticationError() {}
+
 type ErrorMFAChallenge struct {
 	Code                       string `json:"code"`
 	Message                    string `json:"message"`
@@ -29,6 +31,8 @@ func (e ErrorMFAChallenge) Error() string {
 	return e.Message
 }
 
+func (ErrorMFAChallenge) authenticationError() {}
+
 type ErrorMFAEnrollment struct {
 	Code                       string      `json:"code"`
 	Message                    string      `json:"message"`
@@ -40,6 +44,8 @@ func (e ErrorMFAEnrollment) Error() string {
 	return e.Message
 }
 
+func (ErrorMFAEnrollment) authenticationError() {}
+
 type ErrorOrganizationAuthenticationMethodsRequiredAuthMethods struct {
 	AppleOAuth     bool `json:"apple_oauth"`
 	GitHubOAuth    bool `json:"github_oauth"`
@@ -61,6 +67,8 @@ func (e ErrorOrganizationAuthenticationMethodsRequired) Error() string {
 	return e.Message
 }
 
+func (ErrorOrganizationAuthenticationMethodsRequired) authenticationError() {}
+
 type ErrorOrganizationSelectionRequired struct {
 	Code                       string `json:"code"`
 	Message                    string `json:"message"`
@@ -76,6 +84,8 @@ func (e ErrorOrganizationSelectionRequired) Error() string {
 	return e.Message
 }
 
+func (ErrorOrganizationSelectionRequired) authenticationError() {}
+
 type ErrorSSORequired struct {
 	Message                    string   `json:"error"`
 	ErrorDescription           string   `json:"error_description"`
@@ -88,6 +98,8 @@ func (e ErrorSSORequired) Error() string {
 	return e.Message
 }
 
+func (ErrorSSORequired) authenticationError() {}
+
 type ErrorInvalidCredentials struct {
 	Message string `json:"error"`
 }
@@ -96,6 +108,8 @@ func (e ErrorInvalidCredentials) Error() string {
 	return e.Message
 }
 
+func (ErrorInvalidCredentials) authenticationError() {}
+
 type ErrorUserCreationError struct {
 	Message string `json:"error"`
 }
@@ -103,3 +117,5 @@ type ErrorUserCreationError struct {
 func (e ErrorUserCreationError) Error() string {
 	return e.Message
 }
+
+func (ErrorUserCreationError) authenticationError() {}
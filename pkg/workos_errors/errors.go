@@ -2,18 +2,96 @@ package workos_errors
 
 import (
 	"errors"
-	"net/http"
+
+	"github.com/omi-lab/workos-go/v4/internal/workos"
+)
+
+// Sentinel errors matching the class of an HTTPError. They can be used with
+// errors.Is:
+//
+//	if errors.Is(err, workos_errors.ErrNotFound) {
+//	    // Handle missing record.
+//	}
+var (
+	ErrBadRequest   = errors.New("workos: bad request")
+	ErrUnauthorized = errors.New("workos: unauthorized")
+	ErrForbidden    = errors.New("workos: forbidden")
+	ErrNotFound     = errors.New("workos: not found")
+	ErrConflict     = errors.New("workos: conflict")
+	ErrRateLimited  = errors.New("workos: rate limited")
+	ErrServerError  = errors.New("workos: server error")
 )
 
+// IsBadRequest reports whether err is an HTTPError with a 400 status.
 func IsBadRequest(err error) bool {
-	var httpError HTTPError
-	return errors.As(err, &httpError) && httpError.Code == http.StatusBadRequest
+	return errors.Is(err, ErrBadRequest)
+}
+
+// IsUnauthorized reports whether err is an HTTPError with a 401 status.
+func IsUnauthorized(err error) bool {
+	return errors.Is(err, ErrUnauthorized)
+}
+
+// IsForbidden reports whether err is an HTTPError with a 403 status.
+func IsForbidden(err error) bool {
+	return errors.Is(err, ErrForbidden)
+}
+
+// IsNotFound reports whether err is an HTTPError with a 404 status.
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound)
+}
+
+// IsConflict reports whether err is an HTTPError with a 409 status.
+func IsConflict(err error) bool {
+	return errors.Is(err, ErrConflict)
+}
+
+// IsRateLimited reports whether err is an HTTPError with a 429 status.
+func IsRateLimited(err error) bool {
+	return errors.Is(err, ErrRateLimited)
+}
+
+// IsServerError reports whether err is an HTTPError with a 5xx status.
+func IsServerError(err error) bool {
+	return errors.Is(err, ErrServerError)
+}
+
+// IsRetryable reports whether the request that returned err can be sent
+// again: request timeouts, rate limited requests, server errors other than
+// 501 and network errors. These are the failures the SDK clients retry
+// according to their RetryPolicy.
+func IsRetryable(err error) bool {
+	if code, ok := statusCode(err); ok {
+		return workos.IsRetryableStatus(code)
+	}
+	return workos.IsRetryableError(err)
 }
 
+// IsAuthenticationError reports whether err is one of the errors returned
+// when an authentication needs an additional step or fails, such as
+// ErrorMFAChallenge or ErrorInvalidCredentials.
 func IsAuthenticationError(err error) bool {
-	switch err.(type) {
-	case *ErrorEmailVerificationRequired, *ErrorMFAChallenge, *ErrorMFAEnrollment, *ErrorOrganizationAuthenticationMethodsRequired, *ErrorOrganizationSelectionRequired, *ErrorSSORequired, *ErrorInvalidCredentials:
-		return true
+	var authErr authenticationError
+	return errors.As(err, &authErr)
+}
+
+// authenticationError is implemented by the authentication errors, both as
+// values and pointers.
+type authenticationError interface {
+	error
+	authenticationError()
+}
+
+func statusCode(err error) (int, bool) {
+	var httpErr HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.Code, true
+	}
+
+	var httpErrPtr *HTTPError
+	if errors.As(err, &httpErrPtr) && httpErrPtr != nil {
+		return httpErrPtr.Code, true
 	}
-	return false
+	return 0, false
 }
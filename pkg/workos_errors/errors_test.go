@@ -1,8 +1,12 @@
 package workos_errors
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net"
 	"net/http"
+	"net/url"
 	"testing"
 )
 
@@ -97,3 +101,59 @@ func TestIsAuthenticationError(t *testing.T) {
 		})
 	}
 }
+
+func TestStatusHelpers(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		is        func(error) bool
+		want      bool
+		retryable bool
+	}{
+		{name: "unauthorized", err: HTTPError{Code: http.StatusUnauthorized}, is: IsUnauthorized, want: true},
+		{name: "forbidden", err: HTTPError{Code: http.StatusForbidden}, is: IsForbidden, want: true},
+		{name: "not found", err: HTTPError{Code: http.StatusNotFound}, is: IsNotFound, want: true},
+		{name: "not found pointer", err: &HTTPError{Code: http.StatusNotFound}, is: IsNotFound, want: true},
+		{name: "wrapped not found", err: fmt.Errorf("get user: %w", HTTPError{Code: http.StatusNotFound}), is: IsNotFound, want: true},
+		{name: "conflict", err: HTTPError{Code: http.StatusConflict}, is: IsConflict, want: true},
+		{name: "rate limited", err: HTTPError{Code: http.StatusTooManyRequests}, is: IsRateLimited, want: true, retryable: true},
+		{name: "server error", err: HTTPError{Code: http.StatusServiceUnavailable}, is: IsServerError, want: true, retryable: true},
+		{name: "not implemented", err: HTTPError{Code: http.StatusNotImplemented}, is: IsServerError, want: true},
+		{name: "http version not supported", err: HTTPError{Code: http.StatusHTTPVersionNotSupported}, is: IsServerError, want: true, retryable: true},
+		{name: "request timeout", err: HTTPError{Code: http.StatusRequestTimeout}, is: IsServerError, want: false, retryable: true},
+		{name: "network error", err: &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}, is: IsServerError, want: false, retryable: true},
+		{name: "canceled", err: &url.Error{Op: "Get", URL: "https://api.workos.com", Err: context.Canceled}, is: IsServerError, want: false},
+		{name: "bad request is not found", err: HTTPError{Code: http.StatusBadRequest}, is: IsNotFound, want: false},
+		{name: "unknown error", err: fmt.Errorf("unknown error"), is: IsServerError, want: false},
+		{name: "nil", err: nil, is: IsRateLimited, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.is(tt.err); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+			if got := IsRetryable(tt.err); got != tt.retryable {
+				t.Errorf("IsRetryable() = %v, want %v", got, tt.retryable)
+			}
+		})
+	}
+}
+
+func TestIsAuthenticationErrorPointers(t *testing.T) {
+	for _, err := range []error{
+		&ErrorMFAChallenge{},
+		&ErrorSSORequired{},
+		&ErrorInvalidCredentials{},
+		ErrorUserCreationError{},
+		&ErrorUserCreationError{},
+		fmt.Errorf("authenticate: %w", &ErrorEmailVerificationRequired{}),
+	} {
+		if !IsAuthenticationError(err) {
+			t.Errorf("IsAuthenticationError(%T) = false, want true", err)
+		}
+	}
+
+	if IsAuthenticationError(HTTPError{Code: http.StatusUnauthorized}) {
+		t.Errorf("IsAuthenticationError(HTTPError) = true, want false")
+	}
+}
@@ -6,7 +6,11 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
+
+	"github.com/omi-lab/workos-go/v4/pkg/common"
 )
 
 // TryGetHTTPError returns an error when the http response contains invalid
@@ -16,6 +20,16 @@ func TryGetHTTPError(r *http.Response) error {
 		return nil
 	}
 
+	err := getHTTPError(r)
+	if httpErr, ok := err.(HTTPError); ok {
+		httpErr.RateLimit = parseRateLimit(r.Header)
+		httpErr.RetryAfter, _ = common.ParseRetryAfter(r.Header.Get("Retry-After"), time.Now())
+		return httpErr
+	}
+	return err
+}
+
+func getHTTPError(r *http.Response) error {
 	var msg string
 
 	body, err := io.ReadAll(r.Body)
@@ -195,6 +209,36 @@ type HTTPError struct {
 	ErrorCode   string
 	Errors      []string
 	FieldErrors []FieldError
+
+	// The rate limit information sent with the response, if any.
+	RateLimit RateLimit
+
+	// The delay requested by the Retry-After header of the response. Zero
+	// when the header is absent.
+	RetryAfter time.Duration
+}
+
+// RateLimit contains the rate limit information sent by WorkOS in the
+// X-RateLimit-* headers.
+type RateLimit struct {
+	// The number of requests allowed in the current window.
+	Limit int
+
+	// The number of requests left in the current window.
+	Remaining int
+
+	// When the current window resets.
+	Reset time.Time
+}
+
+func parseRateLimit(h http.Header) RateLimit {
+	var rl RateLimit
+	rl.Limit, _ = strconv.Atoi(h.Get("X-RateLimit-Limit"))
+	rl.Remaining, _ = strconv.Atoi(h.Get("X-RateLimit-Remaining"))
+	if reset, err := strconv.ParseInt(h.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		rl.Reset = time.Unix(reset, 0)
+	}
+	return rl
 }
 
 type FieldError struct {
@@ -205,3 +249,25 @@ type FieldError struct {
 func (e HTTPError) Error() string {
 	return fmt.Sprintf("%s: request id %q: %s", e.Status, e.RequestID, e.Message)
 }
+
+// Unwrap returns the sentinel error matching the status code, such as
+// ErrNotFound, so that errors.Is can be used to branch on it.
+func (e HTTPError) Unwrap() error {
+	switch {
+	case e.Code == http.StatusBadRequest:
+		return ErrBadRequest
+	case e.Code == http.StatusUnauthorized:
+		return ErrUnauthorized
+	case e.Code == http.StatusForbidden:
+		return ErrForbidden
+	case e.Code == http.StatusNotFound:
+		return ErrNotFound
+	case e.Code == http.StatusConflict:
+		return ErrConflict
+	case e.Code == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case e.Code >= 500:
+		return ErrServerError
+	}
+	return nil
+}
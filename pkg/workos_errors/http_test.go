@@ -1,9 +1,11 @@
 package workos_errors
 
 import (
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -169,3 +171,28 @@ func TestGetHTTPErrorNoError(t *testing.T) {
 	err := TryGetHTTPError(rec.Result())
 	require.NoError(t, err)
 }
+
+func TestGetHTTPErrorWithRateLimit(t *testing.T) {
+	rec := httptest.NewRecorder()
+	rec.Header().Set("X-Request-ID", "GOrOXx")
+	rec.Header().Set("Content-Type", "application/json")
+	rec.Header().Set("Retry-After", "30")
+	rec.Header().Set("X-RateLimit-Limit", "6000")
+	rec.Header().Set("X-RateLimit-Remaining", "0")
+	rec.Header().Set("X-RateLimit-Reset", "1700000000")
+	rec.WriteHeader(http.StatusTooManyRequests)
+	rec.WriteString(`{"message":"Too many requests"}`)
+
+	err := TryGetHTTPError(rec.Result())
+	require.Error(t, err)
+	require.True(t, errors.Is(err, ErrRateLimited))
+
+	httperr := err.(HTTPError)
+	require.Equal(t, "Too many requests", httperr.Message)
+	require.Equal(t, 30*time.Second, httperr.RetryAfter)
+	require.Equal(t, RateLimit{
+		Limit:     6000,
+		Remaining: 0,
+		Reset:     time.Unix(1700000000, 0),
+	}, httperr.RateLimit)
+}
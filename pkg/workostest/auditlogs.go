@@ -0,0 +1,235 @@
+package workostest
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/omi-lab/workos-go/v4/pkg/models"
+)
+
+// AuditLogEvent is an Audit Log Event received by a Server.
+type AuditLogEvent struct {
+	// The identifier of the Organization the Event belongs to.
+	OrganizationID string
+
+	// The Event.
+	Event models.AuditLogEvent
+
+	// The idempotency key the Event was sent with.
+	IdempotencyKey string
+}
+
+type auditLogExport struct {
+	export models.AuditLogExport
+	events []AuditLogEvent
+}
+
+// AuditLogEvents returns the Audit Log Events received by the server, oldest
+// first. Events retried with the same idempotency key are only recorded once.
+func (s *Server) AuditLogEvents() []AuditLogEvent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]AuditLogEvent(nil), s.auditLogEvents...)
+}
+
+// AuditLogExports returns the Audit Log Exports created on the server, oldest
+// first.
+func (s *Server) AuditLogExports() []models.AuditLogExport {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	exports := make([]models.AuditLogExport, 0, len(s.auditLogExports))
+	for _, e := range s.auditLogExports {
+		exports = append(exports, e.export)
+	}
+	return exports
+}
+
+func (s *Server) auditLogExportIndex(id string) int {
+	for i, e := range s.auditLogExports {
+		if e.export.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func (s *Server) serveAuditLogEvents(w http.ResponseWriter, r *http.Request, path []string) {
+	if len(path) != 0 || r.Method != http.MethodPost {
+		writeRouteNotFound(w, r)
+		return
+	}
+
+	var opts struct {
+		OrganizationID string               `json:"organization_id"`
+		Event          models.AuditLogEvent `json:"event"`
+	}
+	if !decodeBody(w, r, &opts) {
+		return
+	}
+
+	key := r.Header.Get("Idempotency-Key")
+	if key != "" {
+		for _, e := range s.auditLogEvents {
+			if e.IdempotencyKey == key {
+				writeJSON(w, http.StatusCreated, map[string]bool{"success": true})
+				return
+			}
+		}
+	}
+
+	if s.organizationIndex(opts.OrganizationID) < 0 {
+		writeNotFound(w, "Organization", opts.OrganizationID)
+		return
+	}
+	switch {
+	case opts.Event.Action == "":
+		writeInvalid(w, "event.action is required")
+		return
+	case opts.Event.Actor.Type == "":
+		writeInvalid(w, "event.actor.type is required")
+		return
+	case opts.Event.OccurredAt.IsZero():
+		writeInvalid(w, "event.occurred_at is required")
+		return
+	}
+
+	s.auditLogEvents = append(s.auditLogEvents, AuditLogEvent{
+		OrganizationID: opts.OrganizationID,
+		Event:          opts.Event,
+		IdempotencyKey: key,
+	})
+	writeJSON(w, http.StatusCreated, map[string]bool{"success": true})
+}
+
+func (s *Server) serveAuditLogExports(w http.ResponseWriter, r *http.Request, path []string) {
+	switch {
+	case len(path) == 0 && r.Method == http.MethodPost:
+		s.createAuditLogExport(w, r)
+	case len(path) == 1 && r.Method == http.MethodGet:
+		s.getAuditLogExport(w, path[0])
+	case len(path) == 2 && r.Method == http.MethodGet && path[1] == "download":
+		s.downloadAuditLogExport(w, path[0])
+	default:
+		writeRouteNotFound(w, r)
+	}
+}
+
+func (s *Server) createAuditLogExport(w http.ResponseWriter, r *http.Request) {
+	var opts struct {
+		OrganizationID string   `json:"organization_id"`
+		RangeStart     string   `json:"range_start"`
+		RangeEnd       string   `json:"range_end"`
+		Actions        []string `json:"actions"`
+		Actors         []string `json:"actors"`
+		ActorNames     []string `json:"actor_names"`
+		ActorIds       []string `json:"actor_ids"`
+		Targets        []string `json:"targets"`
+	}
+	if !decodeBody(w, r, &opts) {
+		return
+	}
+
+	if s.organizationIndex(opts.OrganizationID) < 0 {
+		writeNotFound(w, "Organization", opts.OrganizationID)
+		return
+	}
+	rangeStart, err := time.Parse(time.RFC3339, opts.RangeStart)
+	if err != nil {
+		writeInvalid(w, "range_start must be an ISO 8601 date")
+		return
+	}
+	rangeEnd, err := time.Parse(time.RFC3339, opts.RangeEnd)
+	if err != nil {
+		writeInvalid(w, "range_end must be an ISO 8601 date")
+		return
+	}
+	if !rangeStart.Before(rangeEnd) {
+		writeInvalid(w, "range_start must be before range_end")
+		return
+	}
+
+	var events []AuditLogEvent
+	for _, e := range s.auditLogEvents {
+		switch {
+		case e.OrganizationID != opts.OrganizationID,
+			e.Event.OccurredAt.Before(rangeStart),
+			!e.Event.OccurredAt.Before(rangeEnd),
+			len(opts.Actions) != 0 && !contains(opts.Actions, e.Event.Action),
+			len(opts.Actors) != 0 && !contains(opts.Actors, e.Event.Actor.Name),
+			len(opts.ActorNames) != 0 && !contains(opts.ActorNames, e.Event.Actor.Name),
+			len(opts.ActorIds) != 0 && !contains(opts.ActorIds, e.Event.Actor.ID),
+			len(opts.Targets) != 0 && !hasAuditLogTarget(e.Event, opts.Targets):
+			continue
+		}
+		events = append(events, e)
+	}
+
+	// Exports are ready as soon as they are created.
+	id := s.newID("audit_log_export")
+	export := models.AuditLogExport{
+		Object: models.AuditLogExportObjectName,
+		ID:     id,
+		State:  models.AuditLogExportStateReady,
+		URL:    fmt.Sprintf("%s/audit_logs/exports/%s/download", s.URL, id),
+	}
+	stamp(&export.CreatedAt, &export.UpdatedAt)
+
+	s.auditLogExports = append(s.auditLogExports, auditLogExport{
+		export: export,
+		events: events,
+	})
+	writeJSON(w, http.StatusCreated, export)
+}
+
+func hasAuditLogTarget(e models.AuditLogEvent, targets []string) bool {
+	for _, target := range e.Targets {
+		if contains(targets, target.Type) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) getAuditLogExport(w http.ResponseWriter, id string) {
+	i := s.auditLogExportIndex(id)
+	if i < 0 {
+		writeNotFound(w, "Audit Log Export", id)
+		return
+	}
+	writeJSON(w, http.StatusOK, s.auditLogExports[i].export)
+}
+
+// downloadAuditLogExport writes the Events of an export as CSV, one row per
+// Event.
+func (s *Server) downloadAuditLogExport(w http.ResponseWriter, id string) {
+	i := s.auditLogExportIndex(id)
+	if i < 0 {
+		writeNotFound(w, "Audit Log Export", id)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv")
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"occurred_at", "action", "actor_id", "actor_name", "actor_type", "targets"})
+	for _, e := range s.auditLogExports[i].events {
+		targets := make([]string, 0, len(e.Event.Targets))
+		for _, target := range e.Event.Targets {
+			targets = append(targets, target.Type+":"+target.ID)
+		}
+
+		cw.Write([]string{
+			e.Event.OccurredAt.UTC().Format(time.RFC3339),
+			e.Event.Action,
+			e.Event.Actor.ID,
+			e.Event.Actor.Name,
+			e.Event.Actor.Type,
+			strings.Join(targets, " "),
+		})
+	}
+	cw.Flush()
+}
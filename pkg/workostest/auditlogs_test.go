@@ -0,0 +1,80 @@
+package workostest_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/omi-lab/workos-go/v4/pkg/auditlogs"
+	"github.com/omi-lab/workos-go/v4/pkg/models"
+	"github.com/omi-lab/workos-go/v4/pkg/workos_errors"
+	"github.com/omi-lab/workos-go/v4/pkg/workostest"
+)
+
+func TestAuditLogs(t *testing.T) {
+	server := workostest.NewServer(apiKey)
+	defer server.Close()
+
+	client := newClient(server).AuditLogs()
+	ctx := context.Background()
+
+	org := server.AddOrganization(models.Organization{Name: "Foo Corp"})
+	occurredAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	event := auditlogs.CreateEventOpts{
+		OrganizationID: org.ID,
+		Event: models.AuditLogEvent{
+			Action:     "document.updated",
+			OccurredAt: occurredAt,
+			Actor: models.AuditLogEventActor{
+				ID:   "user_01",
+				Name: "Marcelina Davis",
+				Type: "user",
+			},
+			Targets: []models.AuditLogEventTarget{
+				{ID: "document_01", Type: "document"},
+			},
+		},
+		IdempotencyKey: "key",
+	}
+	require.NoError(t, client.CreateEvent(ctx, event))
+	require.NoError(t, client.CreateEvent(ctx, event))
+
+	received := server.AuditLogEvents()
+	require.Len(t, received, 1)
+	require.Equal(t, org.ID, received[0].OrganizationID)
+	require.Equal(t, "key", received[0].IdempotencyKey)
+	require.Equal(t, "document.updated", received[0].Event.Action)
+
+	event.OrganizationID = "org_unknown"
+	event.IdempotencyKey = ""
+	err := client.CreateEvent(ctx, event)
+	require.True(t, workos_errors.IsNotFound(err))
+
+	export, err := client.CreateExport(ctx, auditlogs.CreateExportOpts{
+		OrganizationID: org.ID,
+		RangeStart:     occurredAt.Add(-time.Hour).Format(time.RFC3339),
+		RangeEnd:       occurredAt.Add(time.Hour).Format(time.RFC3339),
+		Actions:        []string{"document.updated"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, models.AuditLogExportStateReady, export.State)
+
+	got, err := client.GetExport(ctx, auditlogs.GetExportOpts{ExportID: export.ID})
+	require.NoError(t, err)
+	require.Equal(t, export, got)
+	require.Equal(t, []models.AuditLogExport{export}, server.AuditLogExports())
+
+	res, err := http.Get(export.URL)
+	require.NoError(t, err)
+	defer res.Body.Close()
+
+	body, err := ioutil.ReadAll(res.Body)
+	require.NoError(t, err)
+	require.Equal(t, "occurred_at,action,actor_id,actor_name,actor_type,targets\n"+
+		"2024-01-02T03:04:05Z,document.updated,user_01,Marcelina Davis,user,document:document_01\n", string(body))
+}
@@ -0,0 +1,339 @@
+package workostest
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/omi-lab/workos-go/v4/pkg/models"
+)
+
+// AddDirectory stores a Directory on the server. An ID, timestamps and the
+// linked state are assigned when they are not set.
+func (s *Server) AddDirectory(dir models.Directory) models.Directory {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if dir.ID == "" {
+		dir.ID = s.newID("directory")
+	}
+	if dir.State == "" {
+		dir.State = models.DirectoryStateLinked
+	}
+	stamp(&dir.CreatedAt, &dir.UpdatedAt)
+
+	s.directories = append(s.directories, dir)
+	return dir
+}
+
+// Directories returns the Directories stored on the server, oldest first.
+func (s *Server) Directories() []models.Directory {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]models.Directory(nil), s.directories...)
+}
+
+// AddDirectoryUser stores a Directory User on the server. An ID, timestamps
+// and the active state are assigned when they are not set, and the
+// Organization ID is taken from the Directory.
+func (s *Server) AddDirectoryUser(user models.DirectoryUser) models.DirectoryUser {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if user.ID == "" {
+		user.ID = s.newID("directory_user")
+	}
+	if user.State == "" {
+		user.State = models.DirectoryUserStateActive
+	}
+	if i := s.directoryIndex(user.DirectoryID); i >= 0 && user.OrganizationID == "" {
+		user.OrganizationID = s.directories[i].OrganizationID
+	}
+	stamp(&user.CreatedAt, &user.UpdatedAt)
+
+	s.directoryUsers = append(s.directoryUsers, user)
+	return user
+}
+
+// DirectoryUsers returns the Directory Users stored on the server, oldest
+// first.
+func (s *Server) DirectoryUsers() []models.DirectoryUser {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]models.DirectoryUser(nil), s.directoryUsers...)
+}
+
+// AddDirectoryGroup stores a Directory Group on the server. An ID and
+// timestamps are assigned when they are not set, and the Organization ID is
+// taken from the Directory. Members are added by listing the Group in the
+// Groups of a Directory User.
+func (s *Server) AddDirectoryGroup(group models.DirectoryGroup) models.DirectoryGroup {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if group.ID == "" {
+		group.ID = s.newID("directory_group")
+	}
+	if i := s.directoryIndex(group.DirectoryID); i >= 0 && group.OrganizationID == "" {
+		group.OrganizationID = s.directories[i].OrganizationID
+	}
+	stamp(&group.CreatedAt, &group.UpdatedAt)
+
+	s.directoryGroups = append(s.directoryGroups, group)
+	return group
+}
+
+// DirectoryGroups returns the Directory Groups stored on the server, oldest
+// first.
+func (s *Server) DirectoryGroups() []models.DirectoryGroup {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]models.DirectoryGroup(nil), s.directoryGroups...)
+}
+
+func (s *Server) directoryIndex(id string) int {
+	for i, dir := range s.directories {
+		if dir.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func (s *Server) directoryUserIndex(id string) int {
+	for i, user := range s.directoryUsers {
+		if user.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func (s *Server) directoryGroupIndex(id string) int {
+	for i, group := range s.directoryGroups {
+		if group.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func inDirectoryGroup(user models.DirectoryUser, groupID string) bool {
+	for _, group := range user.Groups {
+		if group.ID == groupID {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) serveDirectories(w http.ResponseWriter, r *http.Request, path []string) {
+	switch {
+	case len(path) == 0 && r.Method == http.MethodGet:
+		s.listDirectories(w, r)
+	case len(path) == 1 && r.Method == http.MethodGet:
+		s.getDirectory(w, path[0])
+	case len(path) == 1 && r.Method == http.MethodDelete:
+		s.deleteDirectory(w, path[0])
+	default:
+		writeRouteNotFound(w, r)
+	}
+}
+
+func (s *Server) listDirectories(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	domain := q.Get("domain")
+	search := strings.ToLower(q.Get("search"))
+	organizationID := q.Get("organization_id")
+
+	var dirs []models.Directory
+	var ids []string
+	for _, dir := range s.directories {
+		if domain != "" && !strings.EqualFold(dir.Domain, domain) {
+			continue
+		}
+		if search != "" && !strings.Contains(strings.ToLower(dir.Name), search) {
+			continue
+		}
+		if organizationID != "" && dir.OrganizationID != organizationID {
+			continue
+		}
+		dirs = append(dirs, dir)
+		ids = append(ids, dir.ID)
+	}
+
+	page, metadata, err := paginate(ids, q)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request", err.Error())
+		return
+	}
+
+	data := make([]models.Directory, 0, len(page))
+	for _, i := range page {
+		data = append(data, dirs[i])
+	}
+	writeList(w, data, metadata, "listMetadata")
+}
+
+func (s *Server) getDirectory(w http.ResponseWriter, id string) {
+	i := s.directoryIndex(id)
+	if i < 0 {
+		writeNotFound(w, "Directory", id)
+		return
+	}
+	writeJSON(w, http.StatusOK, s.directories[i])
+}
+
+func (s *Server) deleteDirectory(w http.ResponseWriter, id string) {
+	i := s.directoryIndex(id)
+	if i < 0 {
+		writeNotFound(w, "Directory", id)
+		return
+	}
+	dir := s.directories[i]
+	s.directories = append(s.directories[:i], s.directories[i+1:]...)
+
+	// Deleting a Directory also deletes its Users and Groups.
+	users := s.directoryUsers[:0]
+	for _, user := range s.directoryUsers {
+		if user.DirectoryID != id {
+			users = append(users, user)
+		}
+	}
+	s.directoryUsers = users
+
+	groups := s.directoryGroups[:0]
+	for _, group := range s.directoryGroups {
+		if group.DirectoryID != id {
+			groups = append(groups, group)
+		}
+	}
+	s.directoryGroups = groups
+
+	s.emit(models.EventDirectoryDeleted, dir)
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (s *Server) serveDirectoryUsers(w http.ResponseWriter, r *http.Request, path []string) {
+	switch {
+	case len(path) == 0 && r.Method == http.MethodGet:
+		s.listDirectoryUsers(w, r)
+	case len(path) == 1 && r.Method == http.MethodGet:
+		s.getDirectoryUser(w, path[0])
+	default:
+		writeRouteNotFound(w, r)
+	}
+}
+
+func (s *Server) listDirectoryUsers(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	directoryID := q.Get("directory")
+	groupID := q.Get("group")
+
+	if directoryID == "" && groupID == "" {
+		writeInvalid(w, "Either directory or group is required.")
+		return
+	}
+
+	var users []models.DirectoryUser
+	var ids []string
+	for _, user := range s.directoryUsers {
+		if directoryID != "" && user.DirectoryID != directoryID {
+			continue
+		}
+		if groupID != "" && !inDirectoryGroup(user, groupID) {
+			continue
+		}
+		users = append(users, user)
+		ids = append(ids, user.ID)
+	}
+
+	page, metadata, err := paginate(ids, q)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request", err.Error())
+		return
+	}
+
+	data := make([]models.DirectoryUser, 0, len(page))
+	for _, i := range page {
+		data = append(data, users[i])
+	}
+	writeList(w, data, metadata, "listMetadata")
+}
+
+func (s *Server) getDirectoryUser(w http.ResponseWriter, id string) {
+	i := s.directoryUserIndex(id)
+	if i < 0 {
+		writeNotFound(w, "Directory User", id)
+		return
+	}
+	writeJSON(w, http.StatusOK, s.directoryUsers[i])
+}
+
+func (s *Server) serveDirectoryGroups(w http.ResponseWriter, r *http.Request, path []string) {
+	switch {
+	case len(path) == 0 && r.Method == http.MethodGet:
+		s.listDirectoryGroups(w, r)
+	case len(path) == 1 && r.Method == http.MethodGet:
+		s.getDirectoryGroup(w, path[0])
+	default:
+		writeRouteNotFound(w, r)
+	}
+}
+
+func (s *Server) listDirectoryGroups(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	directoryID := q.Get("directory")
+	userID := q.Get("user")
+
+	if directoryID == "" && userID == "" {
+		writeInvalid(w, "Either directory or user is required.")
+		return
+	}
+
+	var member models.DirectoryUser
+	if userID != "" {
+		i := s.directoryUserIndex(userID)
+		if i < 0 {
+			writeNotFound(w, "Directory User", userID)
+			return
+		}
+		member = s.directoryUsers[i]
+	}
+
+	var groups []models.DirectoryGroup
+	var ids []string
+	for _, group := range s.directoryGroups {
+		if directoryID != "" && group.DirectoryID != directoryID {
+			continue
+		}
+		if userID != "" && !inDirectoryGroup(member, group.ID) {
+			continue
+		}
+		groups = append(groups, group)
+		ids = append(ids, group.ID)
+	}
+
+	page, metadata, err := paginate(ids, q)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request", err.Error())
+		return
+	}
+
+	data := make([]models.DirectoryGroup, 0, len(page))
+	for _, i := range page {
+		data = append(data, groups[i])
+	}
+	writeList(w, data, metadata, "listMetadata")
+}
+
+func (s *Server) getDirectoryGroup(w http.ResponseWriter, id string) {
+	i := s.directoryGroupIndex(id)
+	if i < 0 {
+		writeNotFound(w, "Directory Group", id)
+		return
+	}
+	writeJSON(w, http.StatusOK, s.directoryGroups[i])
+}
@@ -0,0 +1,77 @@
+package workostest_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/omi-lab/workos-go/v4/pkg/directorysync"
+	"github.com/omi-lab/workos-go/v4/pkg/models"
+	"github.com/omi-lab/workos-go/v4/pkg/workostest"
+)
+
+func TestDirectories(t *testing.T) {
+	server := workostest.NewServer(apiKey)
+	defer server.Close()
+
+	client := newClient(server).DirectorySync()
+	ctx := context.Background()
+
+	dir := server.AddDirectory(models.Directory{
+		Name:           "Foo Corp",
+		Domain:         "foo-corp.com",
+		Type:           models.DirectoryTypeOktaSCIMV2_0,
+		OrganizationID: "org_01",
+	})
+	server.AddDirectory(models.Directory{Name: "Bar Corp"})
+
+	admins := server.AddDirectoryGroup(models.DirectoryGroup{
+		Name:        "Admins",
+		DirectoryID: dir.ID,
+	})
+	server.AddDirectoryGroup(models.DirectoryGroup{
+		Name:        "Engineering",
+		DirectoryID: dir.ID,
+	})
+	user := server.AddDirectoryUser(models.DirectoryUser{
+		Username:    "marcelina@foo-corp.com",
+		DirectoryID: dir.ID,
+		Groups:      []models.DirectoryUserGroup{{ID: admins.ID, Name: admins.Name}},
+	})
+	server.AddDirectoryUser(models.DirectoryUser{
+		Username:    "other@foo-corp.com",
+		DirectoryID: dir.ID,
+	})
+	require.Equal(t, "org_01", user.OrganizationID)
+	require.Equal(t, "org_01", admins.OrganizationID)
+
+	dirs, err := client.ListDirectories(ctx, directorysync.ListDirectoriesOpts{Search: "foo"})
+	require.NoError(t, err)
+	require.Equal(t, []models.Directory{dir}, dirs.Data)
+
+	users, err := client.ListUsers(ctx, directorysync.ListUsersOpts{Group: admins.ID})
+	require.NoError(t, err)
+	require.Len(t, users.Data, 1)
+	require.Equal(t, user.ID, users.Data[0].ID)
+
+	users, err = client.ListUsers(ctx, directorysync.ListUsersOpts{Directory: dir.ID})
+	require.NoError(t, err)
+	require.Len(t, users.Data, 2)
+
+	groups, err := client.ListGroups(ctx, directorysync.ListGroupsOpts{User: user.ID})
+	require.NoError(t, err)
+	require.Len(t, groups.Data, 1)
+	require.Equal(t, admins.ID, groups.Data[0].ID)
+
+	got, err := client.GetUser(ctx, directorysync.GetUserOpts{User: user.ID})
+	require.NoError(t, err)
+	require.Equal(t, user.ID, got.ID)
+	require.Equal(t, user.Groups, got.Groups)
+
+	err = client.DeleteDirectory(ctx, directorysync.DeleteDirectoryOpts{Directory: dir.ID})
+	require.NoError(t, err)
+	require.Len(t, server.Directories(), 1)
+	require.Empty(t, server.DirectoryUsers())
+	require.Empty(t, server.DirectoryGroups())
+}
@@ -0,0 +1,118 @@
+package workostest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/omi-lab/workos-go/v4/pkg/models"
+)
+
+// AddEvent appends an Event of the given type to the event stream. The data
+// is encoded to JSON and must describe the object the Event is about.
+//
+// Events are also appended when the API changes the state of the server, e.g.
+// a user.created Event when a User is created.
+func (s *Server) AddEvent(event string, data interface{}) models.Event {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.emit(event, data)
+}
+
+func (s *Server) emit(event string, data interface{}) models.Event {
+	b, err := json.Marshal(data)
+	if err != nil {
+		panic(fmt.Sprintf("workostest: encoding %s event data: %v", event, err))
+	}
+
+	e := models.Event{
+		ID:        s.newID("event"),
+		Event:     event,
+		Data:      b,
+		CreatedAt: now(),
+	}
+	s.events = append(s.events, e)
+	return e
+}
+
+// Events returns the event stream, oldest first.
+func (s *Server) Events() []models.Event {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]models.Event(nil), s.events...)
+}
+
+func (s *Server) serveEvents(w http.ResponseWriter, r *http.Request, path []string) {
+	if len(path) != 0 || r.Method != http.MethodGet {
+		writeRouteNotFound(w, r)
+		return
+	}
+
+	q := r.URL.Query()
+	types := q["events"]
+	organizationID := q.Get("organization_id")
+
+	var rangeStart, rangeEnd time.Time
+	for param, t := range map[string]*time.Time{
+		"range_start": &rangeStart,
+		"range_end":   &rangeEnd,
+	} {
+		v := q.Get(param)
+		if v == "" {
+			continue
+		}
+
+		var err error
+		if *t, err = time.Parse(time.RFC3339, v); err != nil {
+			writeInvalid(w, fmt.Sprintf("%s must be an ISO 8601 date", param))
+			return
+		}
+	}
+
+	var events []models.Event
+	var ids []string
+	for _, e := range s.events {
+		if len(types) != 0 && !contains(types, e.Event) {
+			continue
+		}
+		if organizationID != "" && eventOrganizationID(e) != organizationID {
+			continue
+		}
+		if !rangeStart.IsZero() && e.CreatedAt.Before(rangeStart) {
+			continue
+		}
+		if !rangeEnd.IsZero() && !e.CreatedAt.Before(rangeEnd) {
+			continue
+		}
+		events = append(events, e)
+		ids = append(ids, e.ID)
+	}
+
+	// Events are always listed from oldest to newest.
+	q.Set("order", "asc")
+
+	page, metadata, err := paginate(ids, q)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request", err.Error())
+		return
+	}
+
+	data := make([]models.Event, 0, len(page))
+	for _, i := range page {
+		data = append(data, events[i])
+	}
+	writeList(w, data, metadata, "list_metadata")
+}
+
+// eventOrganizationID returns the ID of the Organization the Event is about,
+// if any.
+func eventOrganizationID(e models.Event) string {
+	var data struct {
+		OrganizationID string `json:"organization_id"`
+	}
+	json.Unmarshal(e.Data, &data)
+	return data.OrganizationID
+}
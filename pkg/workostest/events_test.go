@@ -0,0 +1,62 @@
+package workostest_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/omi-lab/workos-go/v4/pkg/events"
+	"github.com/omi-lab/workos-go/v4/pkg/models"
+	"github.com/omi-lab/workos-go/v4/pkg/workostest"
+)
+
+func TestEvents(t *testing.T) {
+	server := workostest.NewServer(apiKey)
+	defer server.Close()
+
+	client := newClient(server).Events()
+	ctx := context.Background()
+
+	first := server.AddEvent(models.EventDirectoryUserCreated, models.DirectoryUser{
+		ID:             "directory_user_01",
+		OrganizationID: "org_01",
+	})
+	second := server.AddEvent(models.EventDirectoryUserCreated, models.DirectoryUser{
+		ID:             "directory_user_02",
+		OrganizationID: "org_02",
+	})
+	third := server.AddEvent(models.EventConnectionActivated, models.Connection{
+		ID:             "conn_01",
+		OrganizationID: "org_01",
+	})
+
+	list, err := client.ListEvents(ctx, events.ListEventsOpts{
+		Events: []string{models.EventDirectoryUserCreated},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []models.Event{first, second}, list.Data)
+
+	list, err = client.ListEvents(ctx, events.ListEventsOpts{
+		Events:         []string{models.EventDirectoryUserCreated, models.EventConnectionActivated},
+		OrganizationId: "org_01",
+	})
+	require.NoError(t, err)
+	require.Equal(t, []models.Event{first, third}, list.Data)
+
+	list, err = client.ListEvents(ctx, events.ListEventsOpts{
+		Events: []string{models.EventDirectoryUserCreated, models.EventConnectionActivated},
+		After:  first.ID,
+		Limit:  1,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []models.Event{second}, list.Data)
+	require.Equal(t, second.ID, list.ListMetadata.After)
+
+	list, err = client.ListEvents(ctx, events.ListEventsOpts{
+		Events: []string{models.EventConnectionActivated},
+		After:  third.ID,
+	})
+	require.NoError(t, err)
+	require.Empty(t, list.Data)
+}
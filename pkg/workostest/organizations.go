@@ -0,0 +1,206 @@
+package workostest
+
+import (
+	"net/http"
+
+	"github.com/omi-lab/workos-go/v4/pkg/models"
+)
+
+// AddOrganization stores an Organization on the server. An ID and timestamps
+// are assigned when they are not set.
+func (s *Server) AddOrganization(org models.Organization) models.Organization {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.addOrganization(org)
+}
+
+func (s *Server) addOrganization(org models.Organization) models.Organization {
+	if org.ID == "" {
+		org.ID = s.newID("org")
+	}
+	for i := range org.Domains {
+		if org.Domains[i].ID == "" {
+			org.Domains[i].ID = s.newID("org_domain")
+		}
+	}
+	stamp(&org.CreatedAt, &org.UpdatedAt)
+
+	s.organizations = append(s.organizations, org)
+	return org
+}
+
+// Organizations returns the Organizations stored on the server, oldest first.
+func (s *Server) Organizations() []models.Organization {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]models.Organization(nil), s.organizations...)
+}
+
+func (s *Server) organizationIndex(id string) int {
+	for i, org := range s.organizations {
+		if org.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func (s *Server) organizationDomains(domains []string, domainData []models.OrganizationDomainData) []models.OrganizationDomain {
+	orgDomains := []models.OrganizationDomain{}
+	for _, domain := range domains {
+		orgDomains = append(orgDomains, models.OrganizationDomain{
+			ID:     s.newID("org_domain"),
+			Domain: domain,
+		})
+	}
+	for _, data := range domainData {
+		orgDomains = append(orgDomains, models.OrganizationDomain{
+			ID:     s.newID("org_domain"),
+			Domain: data.Domain,
+		})
+	}
+	return orgDomains
+}
+
+func (s *Server) serveOrganizations(w http.ResponseWriter, r *http.Request, path []string) {
+	switch {
+	case len(path) == 0 && r.Method == http.MethodGet:
+		s.listOrganizations(w, r)
+	case len(path) == 0 && r.Method == http.MethodPost:
+		s.createOrganization(w, r)
+	case len(path) == 1 && r.Method == http.MethodGet:
+		s.getOrganization(w, path[0])
+	case len(path) == 1 && r.Method == http.MethodPut:
+		s.updateOrganization(w, r, path[0])
+	case len(path) == 1 && r.Method == http.MethodDelete:
+		s.deleteOrganization(w, path[0])
+	default:
+		writeRouteNotFound(w, r)
+	}
+}
+
+func (s *Server) listOrganizations(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	domains := q["domains[]"]
+
+	var orgs []models.Organization
+	var ids []string
+	for _, org := range s.organizations {
+		if len(domains) != 0 && !organizationHasDomain(org, domains) {
+			continue
+		}
+		orgs = append(orgs, org)
+		ids = append(ids, org.ID)
+	}
+
+	page, metadata, err := paginate(ids, q)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request", err.Error())
+		return
+	}
+
+	data := make([]models.Organization, 0, len(page))
+	for _, i := range page {
+		data = append(data, orgs[i])
+	}
+	writeList(w, data, metadata, "listMetadata")
+}
+
+func organizationHasDomain(org models.Organization, domains []string) bool {
+	for _, domain := range org.Domains {
+		if contains(domains, domain.Domain) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) createOrganization(w http.ResponseWriter, r *http.Request) {
+	var opts struct {
+		Name                             string                          `json:"name"`
+		AllowProfilesOutsideOrganization bool                            `json:"allow_profiles_outside_organization"`
+		Domains                          []string                        `json:"domains"`
+		DomainData                       []models.OrganizationDomainData `json:"domain_data"`
+	}
+	if !decodeBody(w, r, &opts) {
+		return
+	}
+
+	// Like the WorkOS API, a request retried with the same idempotency key
+	// gets the Organization created by the first one.
+	key := r.Header.Get("Idempotency-Key")
+	if id, ok := s.organizationIdempotency[key]; ok && key != "" {
+		if i := s.organizationIndex(id); i >= 0 {
+			writeJSON(w, http.StatusCreated, s.organizations[i])
+			return
+		}
+	}
+
+	if opts.Name == "" {
+		writeInvalid(w, "name is required")
+		return
+	}
+
+	org := s.addOrganization(models.Organization{
+		Name:                             opts.Name,
+		AllowProfilesOutsideOrganization: opts.AllowProfilesOutsideOrganization,
+		Domains:                          s.organizationDomains(opts.Domains, opts.DomainData),
+	})
+	if key != "" {
+		s.organizationIdempotency[key] = org.ID
+	}
+	writeJSON(w, http.StatusCreated, org)
+}
+
+func (s *Server) getOrganization(w http.ResponseWriter, id string) {
+	i := s.organizationIndex(id)
+	if i < 0 {
+		writeNotFound(w, "Organization", id)
+		return
+	}
+	writeJSON(w, http.StatusOK, s.organizations[i])
+}
+
+func (s *Server) updateOrganization(w http.ResponseWriter, r *http.Request, id string) {
+	i := s.organizationIndex(id)
+	if i < 0 {
+		writeNotFound(w, "Organization", id)
+		return
+	}
+
+	var opts struct {
+		Name                             string                          `json:"name"`
+		AllowProfilesOutsideOrganization bool                            `json:"allow_profiles_outside_organization"`
+		Domains                          []string                        `json:"domains"`
+		DomainData                       []models.OrganizationDomainData `json:"domain_data"`
+	}
+	if !decodeBody(w, r, &opts) {
+		return
+	}
+
+	org := s.organizations[i]
+	if opts.Name != "" {
+		org.Name = opts.Name
+	}
+	org.AllowProfilesOutsideOrganization = opts.AllowProfilesOutsideOrganization
+	if len(opts.Domains) != 0 || len(opts.DomainData) != 0 {
+		org.Domains = s.organizationDomains(opts.Domains, opts.DomainData)
+	}
+	org.UpdatedAt = now()
+
+	s.organizations[i] = org
+	writeJSON(w, http.StatusOK, org)
+}
+
+func (s *Server) deleteOrganization(w http.ResponseWriter, id string) {
+	i := s.organizationIndex(id)
+	if i < 0 {
+		writeNotFound(w, "Organization", id)
+		return
+	}
+
+	s.organizations = append(s.organizations[:i], s.organizations[i+1:]...)
+	w.WriteHeader(http.StatusAccepted)
+}
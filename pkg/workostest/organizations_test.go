@@ -0,0 +1,86 @@
+package workostest_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/omi-lab/workos-go/v4/pkg/common"
+	"github.com/omi-lab/workos-go/v4/pkg/models"
+	"github.com/omi-lab/workos-go/v4/pkg/organizations"
+	"github.com/omi-lab/workos-go/v4/pkg/workos_errors"
+	"github.com/omi-lab/workos-go/v4/pkg/workostest"
+)
+
+func TestOrganizations(t *testing.T) {
+	server := workostest.NewServer(apiKey)
+	defer server.Close()
+
+	client := newClient(server).Organizations()
+	ctx := context.Background()
+
+	org, err := client.CreateOrganization(ctx, organizations.CreateOrganizationOpts{
+		Name: "Foo Corp",
+		DomainData: []models.OrganizationDomainData{
+			{Domain: "foo-corp.com", State: models.OrganizationDomainDataStateVerified},
+		},
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, org.ID)
+	require.Equal(t, "Foo Corp", org.Name)
+	require.Len(t, org.Domains, 1)
+	require.Equal(t, "foo-corp.com", org.Domains[0].Domain)
+	require.Equal(t, []models.Organization{org}, server.Organizations())
+
+	server.AddOrganization(models.Organization{
+		Name:    "Bar Corp",
+		Domains: []models.OrganizationDomain{{Domain: "bar-corp.com"}},
+	})
+
+	list, err := client.ListOrganizations(ctx, organizations.ListOrganizationsOpts{
+		Domains: []string{"foo-corp.com"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []models.Organization{org}, list.Data)
+
+	updated, err := client.UpdateOrganization(ctx, organizations.UpdateOrganizationOpts{
+		Organization: org.ID,
+		Name:         "Foo Corp 2",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "Foo Corp 2", updated.Name)
+	require.Equal(t, org.Domains, updated.Domains)
+
+	got, err := client.GetOrganization(ctx, organizations.GetOrganizationOpts{Organization: org.ID})
+	require.NoError(t, err)
+	require.Equal(t, updated, got)
+
+	err = client.DeleteOrganization(ctx, organizations.DeleteOrganizationOpts{Organization: org.ID})
+	require.NoError(t, err)
+
+	_, err = client.GetOrganization(ctx, organizations.GetOrganizationOpts{Organization: org.ID})
+	require.True(t, workos_errors.IsNotFound(err))
+	require.Len(t, server.Organizations(), 1)
+}
+
+func TestCreateOrganizationIsIdempotent(t *testing.T) {
+	server := workostest.NewServer(apiKey)
+	defer server.Close()
+
+	client := newClient(server).Organizations()
+	ctx := context.Background()
+
+	opts := organizations.CreateOrganizationOpts{Name: "Foo Corp"}
+	first, err := client.CreateOrganization(ctx, opts, common.WithIdempotencyKey("key"))
+	require.NoError(t, err)
+
+	second, err := client.CreateOrganization(ctx, opts, common.WithIdempotencyKey("key"))
+	require.NoError(t, err)
+	require.Equal(t, first, second)
+	require.Len(t, server.Organizations(), 1)
+
+	_, err = client.CreateOrganization(ctx, opts)
+	require.NoError(t, err)
+	require.Len(t, server.Organizations(), 2)
+}
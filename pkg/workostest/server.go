@@ -0,0 +1,356 @@
+// Package workostest provides an in-memory fake of the WorkOS API for tests.
+//
+// A Server keeps Organizations, Users, Organization Memberships, Invitations,
+// Connections, Directories, Directory Users and Groups, Events and Audit Logs
+// in memory and serves them over HTTP the way the WorkOS API does. Any client
+// can be pointed at it by using the Server URL as its Endpoint:
+//
+//	server := workostest.NewServer("sk_test")
+//	defer server.Close()
+//
+//	org := server.AddOrganization(models.Organization{Name: "Foo Corp"})
+//
+//	client := &organizations.Client{
+//		APIKey:   "sk_test",
+//		Endpoint: server.URL,
+//	}
+//	client.GetOrganization(ctx, organizations.GetOrganizationOpts{
+//		Organization: org.ID,
+//	})
+//
+// Resources can be seeded with the Add methods and inspected with the methods
+// named after them, e.g. AddUser and Users.
+package workostest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/omi-lab/workos-go/v4/pkg/common"
+	"github.com/omi-lab/workos-go/v4/pkg/models"
+)
+
+const (
+	// The number of records returned by list endpoints when no limit is
+	// given.
+	defaultLimit = 10
+
+	// The maximum number of records returned by list endpoints.
+	maxLimit = 100
+)
+
+// Request is a request received by a Server.
+type Request struct {
+	// The HTTP method of the request.
+	Method string
+
+	// The path of the request, e.g. /organizations/org_01.
+	Path string
+
+	// The query parameters of the request.
+	Query url.Values
+
+	// The headers of the request.
+	Header http.Header
+
+	// The body of the request.
+	Body []byte
+}
+
+// Server is an in-memory fake of the WorkOS API.
+type Server struct {
+	// The base URL of the server, e.g. http://127.0.0.1:4242. It can be used
+	// as the Endpoint of any client.
+	URL string
+
+	apiKey string
+	srv    *httptest.Server
+
+	mu                      sync.Mutex
+	seq                     int
+	requests                []Request
+	organizations           []models.Organization
+	organizationIdempotency map[string]string
+	users                   []models.User
+	memberships             []models.OrganizationMembership
+	invitations             []models.Invitation
+	connections             []models.Connection
+	directories             []models.Directory
+	directoryUsers          []models.DirectoryUser
+	directoryGroups         []models.DirectoryGroup
+	events                  []models.Event
+	auditLogEvents          []AuditLogEvent
+	auditLogExports         []auditLogExport
+}
+
+// NewServer starts a Server. Requests must be authenticated with the given
+// API key, unless it is empty. The Server should be closed when it is no
+// longer used.
+func NewServer(apiKey string) *Server {
+	s := &Server{
+		apiKey:                  apiKey,
+		organizationIdempotency: make(map[string]string),
+	}
+	s.srv = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	s.URL = s.srv.URL
+	return s
+}
+
+// Close shuts down the server and blocks until all outstanding requests on
+// it have completed.
+func (s *Server) Close() {
+	s.srv.Close()
+}
+
+// Requests returns the requests received by the server, oldest first.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Request(nil), s.requests...)
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request", err.Error())
+		return
+	}
+	r.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests = append(s.requests, Request{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Query:  r.URL.Query(),
+		Header: r.Header.Clone(),
+		Body:   body,
+	})
+
+	w.Header().Set("X-Request-ID", s.newID("req"))
+
+	path := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+
+	// Export download URLs are pre-signed and can be fetched without the
+	// API key.
+	download := len(path) == 4 && path[0] == "audit_logs" && path[1] == "exports" && path[3] == "download"
+	if s.apiKey != "" && !download && r.Header.Get("Authorization") != "Bearer "+s.apiKey {
+		writeError(w, http.StatusUnauthorized, "unauthorized", "Unauthorized")
+		return
+	}
+
+	switch path[0] {
+	case "organizations":
+		s.serveOrganizations(w, r, path[1:])
+
+	case "user_management":
+		if len(path) < 2 {
+			writeRouteNotFound(w, r)
+			return
+		}
+
+		switch path[1] {
+		case "users":
+			s.serveUsers(w, r, path[2:])
+		case "organization_memberships":
+			s.serveOrganizationMemberships(w, r, path[2:])
+		case "invitations":
+			s.serveInvitations(w, r, path[2:])
+		default:
+			writeRouteNotFound(w, r)
+		}
+
+	case "connections":
+		s.serveConnections(w, r, path[1:])
+
+	case "directories":
+		s.serveDirectories(w, r, path[1:])
+
+	case "directory_users":
+		s.serveDirectoryUsers(w, r, path[1:])
+
+	case "directory_groups":
+		s.serveDirectoryGroups(w, r, path[1:])
+
+	case "events":
+		s.serveEvents(w, r, path[1:])
+
+	case "audit_logs":
+		if len(path) < 2 {
+			writeRouteNotFound(w, r)
+			return
+		}
+
+		switch path[1] {
+		case "events":
+			s.serveAuditLogEvents(w, r, path[2:])
+		case "exports":
+			s.serveAuditLogExports(w, r, path[2:])
+		default:
+			writeRouteNotFound(w, r)
+		}
+
+	default:
+		writeRouteNotFound(w, r)
+	}
+}
+
+// newID returns a new unique identifier with the given prefix. Identifiers
+// sort in the order they are created, like the ones of the WorkOS API.
+func (s *Server) newID(prefix string) string {
+	s.seq++
+	return fmt.Sprintf("%s_%026d", prefix, s.seq)
+}
+
+func now() time.Time {
+	return time.Now().UTC()
+}
+
+// stamp sets the creation and update times that are not already set.
+func stamp(createdAt, updatedAt *time.Time) {
+	if createdAt.IsZero() {
+		*createdAt = now()
+	}
+	if updatedAt.IsZero() {
+		*updatedAt = *createdAt
+	}
+}
+
+// paginate selects the page of ids requested by the limit, order, before and
+// after query parameters. It returns the indexes of the selected ids, in the
+// order they are listed, along with the cursors of the adjacent pages. The ids
+// must be sorted from oldest to newest.
+func paginate(ids []string, q url.Values) ([]int, common.ListMetadata, error) {
+	order := make([]int, len(ids))
+	for i := range order {
+		order[i] = i
+	}
+	if q.Get("order") != "asc" {
+		for i, j := 0, len(order)-1; i < j; i, j = i+1, j-1 {
+			order[i], order[j] = order[j], order[i]
+		}
+	}
+
+	limit := defaultLimit
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return nil, common.ListMetadata{}, fmt.Errorf("invalid limit: %q", v)
+		}
+		if n > maxLimit {
+			n = maxLimit
+		}
+		limit = n
+	}
+
+	position := func(cursor string) int {
+		for pos, i := range order {
+			if ids[i] == cursor {
+				return pos
+			}
+		}
+		return -1
+	}
+
+	start, end := 0, len(order)
+	after, before := q.Get("after"), q.Get("before")
+	if after != "" {
+		if start = position(after); start < 0 {
+			return nil, common.ListMetadata{}, fmt.Errorf("invalid cursor: %q", after)
+		}
+		start++
+	}
+	if before != "" {
+		if end = position(before); end < 0 {
+			return nil, common.ListMetadata{}, fmt.Errorf("invalid cursor: %q", before)
+		}
+	}
+	if start > end {
+		start = end
+	}
+
+	if before != "" && after == "" {
+		if end-start > limit {
+			start = end - limit
+		}
+	} else if end-start > limit {
+		end = start + limit
+	}
+
+	var metadata common.ListMetadata
+	if start < end {
+		if start > 0 {
+			metadata.Before = ids[order[start]]
+		}
+		if end < len(order) {
+			metadata.After = ids[order[end-1]]
+		}
+	}
+	return order[start:end], metadata, nil
+}
+
+// writeList writes a page of records. The list metadata is written under the
+// given key since it differs between endpoints.
+func writeList(w http.ResponseWriter, data interface{}, metadata common.ListMetadata, metadataKey string) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"object":    "list",
+		"data":      data,
+		metadataKey: metadata,
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	writeJSON(w, status, map[string]string{
+		"code":    code,
+		"message": message,
+	})
+}
+
+func writeNotFound(w http.ResponseWriter, kind, id string) {
+	writeError(w, http.StatusNotFound, "entity_not_found", fmt.Sprintf("%s not found: '%s'.", kind, id))
+}
+
+func writeRouteNotFound(w http.ResponseWriter, r *http.Request) {
+	writeError(w, http.StatusNotFound, "route_not_found", fmt.Sprintf("Cannot %s %s", r.Method, r.URL.Path))
+}
+
+func writeInvalid(w http.ResponseWriter, message string) {
+	writeError(w, http.StatusUnprocessableEntity, "invalid_request_parameters", message)
+}
+
+// decodeBody decodes the JSON body of a request, writing an error response
+// when it is malformed.
+func decodeBody(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request", err.Error())
+		return false
+	}
+	return true
+}
+
+// contains reports whether values contains v.
+func contains(values []string, v string) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,136 @@
+package workostest_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/omi-lab/workos-go/v4/pkg/common"
+	"github.com/omi-lab/workos-go/v4/pkg/models"
+	"github.com/omi-lab/workos-go/v4/pkg/organizations"
+	"github.com/omi-lab/workos-go/v4/pkg/workos"
+	"github.com/omi-lab/workos-go/v4/pkg/workos_errors"
+	"github.com/omi-lab/workos-go/v4/pkg/workostest"
+)
+
+const apiKey = "sk_test"
+
+func newClient(server *workostest.Server) *workos.Client {
+	return workos.New(workos.Config{
+		APIKey:      apiKey,
+		Endpoint:    server.URL,
+		RetryPolicy: &common.NoRetry,
+	})
+}
+
+func TestServerAuthentication(t *testing.T) {
+	server := workostest.NewServer(apiKey)
+	defer server.Close()
+
+	client := workos.New(workos.Config{
+		APIKey:   "sk_wrong",
+		Endpoint: server.URL,
+	})
+	_, err := client.Organizations().ListOrganizations(context.Background(), organizations.ListOrganizationsOpts{})
+	require.True(t, workos_errors.IsUnauthorized(err))
+
+	_, err = newClient(server).Organizations().ListOrganizations(context.Background(), organizations.ListOrganizationsOpts{})
+	require.NoError(t, err)
+}
+
+func TestServerNotFound(t *testing.T) {
+	server := workostest.NewServer(apiKey)
+	defer server.Close()
+
+	_, err := newClient(server).Organizations().GetOrganization(context.Background(), organizations.GetOrganizationOpts{
+		Organization: "org_unknown",
+	})
+	require.True(t, workos_errors.IsNotFound(err))
+
+	httpErr, ok := err.(workos_errors.HTTPError)
+	require.True(t, ok)
+	require.Equal(t, "Organization not found: 'org_unknown'.", httpErr.Message)
+	require.NotEmpty(t, httpErr.RequestID)
+}
+
+func TestServerPagination(t *testing.T) {
+	server := workostest.NewServer(apiKey)
+	defer server.Close()
+
+	var ids []string
+	for i := 0; i < 5; i++ {
+		ids = append(ids, server.AddOrganization(models.Organization{Name: "Foo Corp"}).ID)
+	}
+
+	client := newClient(server).Organizations()
+	ctx := context.Background()
+
+	page, err := client.ListOrganizations(ctx, organizations.ListOrganizationsOpts{
+		Limit: 2,
+		Order: organizations.Asc,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{ids[0], ids[1]}, organizationIDs(page.Data))
+	require.Equal(t, common.ListMetadata{After: ids[1]}, page.ListMetadata)
+
+	page, err = client.ListOrganizations(ctx, organizations.ListOrganizationsOpts{
+		Limit: 2,
+		Order: organizations.Asc,
+		After: page.ListMetadata.After,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{ids[2], ids[3]}, organizationIDs(page.Data))
+	require.Equal(t, common.ListMetadata{Before: ids[2], After: ids[3]}, page.ListMetadata)
+
+	page, err = client.ListOrganizations(ctx, organizations.ListOrganizationsOpts{
+		Limit:  2,
+		Order:  organizations.Asc,
+		Before: ids[4],
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{ids[2], ids[3]}, organizationIDs(page.Data))
+
+	page, err = client.ListOrganizations(ctx, organizations.ListOrganizationsOpts{
+		Limit: 2,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{ids[4], ids[3]}, organizationIDs(page.Data))
+
+	all, err := client.IterateOrganizations(ctx, organizations.ListOrganizationsOpts{Limit: 2}).CollectAll(0)
+	require.NoError(t, err)
+	require.Len(t, all, 5)
+	require.Equal(t, ids[4], all[0].ID)
+	require.Equal(t, ids[0], all[4].ID)
+}
+
+func TestServerRequests(t *testing.T) {
+	server := workostest.NewServer(apiKey)
+	defer server.Close()
+
+	_, err := newClient(server).Organizations().CreateOrganization(context.Background(), organizations.CreateOrganizationOpts{
+		Name: "Foo Corp",
+	})
+	require.NoError(t, err)
+
+	requests := server.Requests()
+	require.Len(t, requests, 1)
+	require.Equal(t, http.MethodPost, requests[0].Method)
+	require.Equal(t, "/organizations", requests[0].Path)
+	require.NotEmpty(t, requests[0].Header.Get("Idempotency-Key"))
+	require.JSONEq(t, `{
+		"name": "Foo Corp",
+		"allow_profiles_outside_organization": false,
+		"domains": null,
+		"domain_data": null
+	}`, string(requests[0].Body))
+}
+
+func organizationIDs(orgs []models.Organization) []string {
+	ids := make([]string, 0, len(orgs))
+	for _, org := range orgs {
+		ids = append(ids, org.ID)
+	}
+	return ids
+}
@@ -0,0 +1,133 @@
+package workostest
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/omi-lab/workos-go/v4/pkg/models"
+)
+
+// AddConnection stores a Connection on the server. An ID, timestamps and the
+// active state are assigned when they are not set.
+func (s *Server) AddConnection(conn models.Connection) models.Connection {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if conn.ID == "" {
+		conn.ID = s.newID("conn")
+	}
+	if conn.State == "" {
+		conn.State = models.ConnectionStateActive
+	}
+	if conn.Status == "" {
+		conn.Status = models.ConnectionStatusLinked
+		if conn.State != models.ConnectionStateActive {
+			conn.Status = models.ConnectionStatusUnlinked
+		}
+	}
+	for i := range conn.Domains {
+		if conn.Domains[i].ID == "" {
+			conn.Domains[i].ID = s.newID("conn_domain")
+		}
+	}
+	stamp(&conn.CreatedAt, &conn.UpdatedAt)
+
+	s.connections = append(s.connections, conn)
+	return conn
+}
+
+// Connections returns the Connections stored on the server, oldest first.
+func (s *Server) Connections() []models.Connection {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]models.Connection(nil), s.connections...)
+}
+
+func (s *Server) connectionIndex(id string) int {
+	for i, conn := range s.connections {
+		if conn.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func (s *Server) serveConnections(w http.ResponseWriter, r *http.Request, path []string) {
+	switch {
+	case len(path) == 0 && r.Method == http.MethodGet:
+		s.listConnections(w, r)
+	case len(path) == 1 && r.Method == http.MethodGet:
+		s.getConnection(w, path[0])
+	case len(path) == 1 && r.Method == http.MethodDelete:
+		s.deleteConnection(w, path[0])
+	default:
+		writeRouteNotFound(w, r)
+	}
+}
+
+func (s *Server) listConnections(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	connectionType := q.Get("connection_type")
+	organizationID := q.Get("organization_id")
+	domain := q.Get("domain")
+
+	var conns []models.Connection
+	var ids []string
+	for _, conn := range s.connections {
+		if connectionType != "" && string(conn.ConnectionType) != connectionType {
+			continue
+		}
+		if organizationID != "" && conn.OrganizationID != organizationID {
+			continue
+		}
+		if domain != "" && !connectionHasDomain(conn, domain) {
+			continue
+		}
+		conns = append(conns, conn)
+		ids = append(ids, conn.ID)
+	}
+
+	page, metadata, err := paginate(ids, q)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request", err.Error())
+		return
+	}
+
+	data := make([]models.Connection, 0, len(page))
+	for _, i := range page {
+		data = append(data, conns[i])
+	}
+	writeList(w, data, metadata, "listMetadata")
+}
+
+func connectionHasDomain(conn models.Connection, domain string) bool {
+	for _, d := range conn.Domains {
+		if strings.EqualFold(d.Domain, domain) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) getConnection(w http.ResponseWriter, id string) {
+	i := s.connectionIndex(id)
+	if i < 0 {
+		writeNotFound(w, "Connection", id)
+		return
+	}
+	writeJSON(w, http.StatusOK, s.connections[i])
+}
+
+func (s *Server) deleteConnection(w http.ResponseWriter, id string) {
+	i := s.connectionIndex(id)
+	if i < 0 {
+		writeNotFound(w, "Connection", id)
+		return
+	}
+	conn := s.connections[i]
+
+	s.connections = append(s.connections[:i], s.connections[i+1:]...)
+	s.emit(models.EventConnectionDeleted, conn)
+	w.WriteHeader(http.StatusAccepted)
+}
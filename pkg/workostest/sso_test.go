@@ -0,0 +1,56 @@
+package workostest_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/omi-lab/workos-go/v4/pkg/models"
+	"github.com/omi-lab/workos-go/v4/pkg/sso"
+	"github.com/omi-lab/workos-go/v4/pkg/workos_errors"
+	"github.com/omi-lab/workos-go/v4/pkg/workostest"
+)
+
+func TestConnections(t *testing.T) {
+	server := workostest.NewServer(apiKey)
+	defer server.Close()
+
+	client := newClient(server).SSO()
+	ctx := context.Background()
+
+	conn := server.AddConnection(models.Connection{
+		Name:           "Foo Corp",
+		ConnectionType: models.ConnectionTypeOktaSAML,
+		OrganizationID: "org_01",
+		Domains:        []models.ConnectionDomain{{Domain: "foo-corp.com"}},
+	})
+	server.AddConnection(models.Connection{
+		Name:           "Bar Corp",
+		ConnectionType: models.ConnectionTypeGoogleOAuth,
+	})
+	require.Equal(t, models.ConnectionStateActive, conn.State)
+
+	list, err := client.ListConnections(ctx, sso.ListConnectionsOpts{Domain: "foo-corp.com"})
+	require.NoError(t, err)
+	require.Equal(t, []models.Connection{conn}, list.Data)
+
+	list, err = client.ListConnections(ctx, sso.ListConnectionsOpts{ConnectionType: models.ConnectionTypeGoogleOAuth})
+	require.NoError(t, err)
+	require.Len(t, list.Data, 1)
+	require.Equal(t, "Bar Corp", list.Data[0].Name)
+
+	got, err := client.GetConnection(ctx, sso.GetConnectionOpts{Connection: conn.ID})
+	require.NoError(t, err)
+	require.Equal(t, conn, got)
+
+	err = client.DeleteConnection(ctx, sso.DeleteConnectionOpts{Connection: conn.ID})
+	require.NoError(t, err)
+
+	_, err = client.GetConnection(ctx, sso.GetConnectionOpts{Connection: conn.ID})
+	require.True(t, workos_errors.IsNotFound(err))
+
+	events := server.Events()
+	require.Len(t, events, 1)
+	require.Equal(t, models.EventConnectionDeleted, events[0].Event)
+}
@@ -0,0 +1,600 @@
+package workostest
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/omi-lab/workos-go/v4/pkg/common"
+	"github.com/omi-lab/workos-go/v4/pkg/models"
+)
+
+// The role granted to Organization Memberships created without one.
+const defaultRoleSlug = "member"
+
+// AddUser stores a User on the server. An ID and timestamps are assigned when
+// they are not set.
+func (s *Server) AddUser(user models.User) models.User {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.addUser(user)
+}
+
+func (s *Server) addUser(user models.User) models.User {
+	if user.ID == "" {
+		user.ID = s.newID("user")
+	}
+	stamp(&user.CreatedAt, &user.UpdatedAt)
+
+	s.users = append(s.users, user)
+	return user
+}
+
+// Users returns the Users stored on the server, oldest first.
+func (s *Server) Users() []models.User {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]models.User(nil), s.users...)
+}
+
+// AddOrganizationMembership stores an Organization Membership on the server.
+// An ID, timestamps, the active status and the member role are assigned when
+// they are not set.
+func (s *Server) AddOrganizationMembership(membership models.OrganizationMembership) models.OrganizationMembership {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.addOrganizationMembership(membership)
+}
+
+func (s *Server) addOrganizationMembership(membership models.OrganizationMembership) models.OrganizationMembership {
+	if membership.ID == "" {
+		membership.ID = s.newID("om")
+	}
+	if membership.Status == "" {
+		membership.Status = models.OrganizationMembershipStatusActive
+	}
+	if membership.Role.Slug == "" {
+		membership.Role.Slug = defaultRoleSlug
+	}
+	stamp(&membership.CreatedAt, &membership.UpdatedAt)
+
+	s.memberships = append(s.memberships, membership)
+	return membership
+}
+
+// OrganizationMemberships returns the Organization Memberships stored on the
+// server, oldest first.
+func (s *Server) OrganizationMemberships() []models.OrganizationMembership {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]models.OrganizationMembership(nil), s.memberships...)
+}
+
+// AddInvitation stores an Invitation on the server. An ID, a token, an
+// acceptance URL, timestamps and the pending state are assigned when they are
+// not set. Invitations expire after a week by default.
+func (s *Server) AddInvitation(invitation models.Invitation) models.Invitation {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.addInvitation(invitation)
+}
+
+func (s *Server) addInvitation(invitation models.Invitation) models.Invitation {
+	if invitation.ID == "" {
+		invitation.ID = s.newID("invitation")
+	}
+	if invitation.Token == "" {
+		invitation.Token = newToken()
+	}
+	if invitation.AcceptInvitationUrl == "" {
+		invitation.AcceptInvitationUrl = s.URL + "/invite?invitation_token=" + invitation.Token
+	}
+	if invitation.State == "" {
+		invitation.State = models.InvitationStatePending
+	}
+	stamp(&invitation.CreatedAt, &invitation.UpdatedAt)
+	if invitation.ExpiresAt.IsZero() {
+		invitation.ExpiresAt = invitation.CreatedAt.Add(7 * 24 * time.Hour)
+	}
+
+	s.invitations = append(s.invitations, invitation)
+	return invitation
+}
+
+// Invitations returns the Invitations stored on the server, oldest first.
+func (s *Server) Invitations() []models.Invitation {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]models.Invitation(nil), s.invitations...)
+}
+
+func (s *Server) userIndex(id string) int {
+	for i, user := range s.users {
+		if user.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func (s *Server) organizationMembershipIndex(id string) int {
+	for i, membership := range s.memberships {
+		if membership.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func (s *Server) invitationIndex(id string) int {
+	for i, invitation := range s.invitations {
+		if invitation.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// isMember reports whether the User is a member of the Organization.
+func (s *Server) isMember(userID, organizationID string) bool {
+	for _, membership := range s.memberships {
+		if membership.UserID == userID && membership.OrganizationID == organizationID {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) serveUsers(w http.ResponseWriter, r *http.Request, path []string) {
+	switch {
+	case len(path) == 0 && r.Method == http.MethodGet:
+		s.listUsers(w, r)
+	case len(path) == 0 && r.Method == http.MethodPost:
+		s.createUser(w, r)
+	case len(path) == 1 && r.Method == http.MethodGet:
+		s.getUser(w, path[0])
+	case len(path) == 1 && r.Method == http.MethodPut:
+		s.updateUser(w, r, path[0])
+	case len(path) == 1 && r.Method == http.MethodDelete:
+		s.deleteUser(w, path[0])
+	default:
+		writeRouteNotFound(w, r)
+	}
+}
+
+func (s *Server) listUsers(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	email := q.Get("email")
+	organizationID := q.Get("organization_id")
+
+	var users []models.User
+	var ids []string
+	for _, user := range s.users {
+		if email != "" && !strings.EqualFold(user.Email, email) {
+			continue
+		}
+		if organizationID != "" && !s.isMember(user.ID, organizationID) {
+			continue
+		}
+		users = append(users, user)
+		ids = append(ids, user.ID)
+	}
+
+	page, metadata, err := paginate(ids, q)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request", err.Error())
+		return
+	}
+
+	data := make([]models.User, 0, len(page))
+	for _, i := range page {
+		data = append(data, users[i])
+	}
+	writeList(w, data, metadata, "list_metadata")
+}
+
+func (s *Server) createUser(w http.ResponseWriter, r *http.Request) {
+	var opts struct {
+		Email         string `json:"email"`
+		FirstName     string `json:"first_name"`
+		LastName      string `json:"last_name"`
+		EmailVerified bool   `json:"email_verified"`
+	}
+	if !decodeBody(w, r, &opts) {
+		return
+	}
+
+	if opts.Email == "" {
+		writeInvalid(w, "email is required")
+		return
+	}
+	for _, user := range s.users {
+		if strings.EqualFold(user.Email, opts.Email) {
+			writeError(w, http.StatusConflict, "email_not_available", "This email is not available.")
+			return
+		}
+	}
+
+	user := s.addUser(models.User{
+		Email:         opts.Email,
+		FirstName:     opts.FirstName,
+		LastName:      opts.LastName,
+		EmailVerified: opts.EmailVerified,
+	})
+	s.emit(models.EventUserCreated, user)
+	writeJSON(w, http.StatusCreated, user)
+}
+
+func (s *Server) getUser(w http.ResponseWriter, id string) {
+	i := s.userIndex(id)
+	if i < 0 {
+		writeNotFound(w, "User", id)
+		return
+	}
+	writeJSON(w, http.StatusOK, s.users[i])
+}
+
+func (s *Server) updateUser(w http.ResponseWriter, r *http.Request, id string) {
+	i := s.userIndex(id)
+	if i < 0 {
+		writeNotFound(w, "User", id)
+		return
+	}
+
+	var opts struct {
+		FirstName     string `json:"first_name"`
+		LastName      string `json:"last_name"`
+		EmailVerified bool   `json:"email_verified"`
+	}
+	if !decodeBody(w, r, &opts) {
+		return
+	}
+
+	user := s.users[i]
+	if opts.FirstName != "" {
+		user.FirstName = opts.FirstName
+	}
+	if opts.LastName != "" {
+		user.LastName = opts.LastName
+	}
+	if opts.EmailVerified {
+		user.EmailVerified = true
+	}
+	user.UpdatedAt = now()
+
+	s.users[i] = user
+	s.emit(models.EventUserUpdated, user)
+	writeJSON(w, http.StatusOK, user)
+}
+
+func (s *Server) deleteUser(w http.ResponseWriter, id string) {
+	i := s.userIndex(id)
+	if i < 0 {
+		writeNotFound(w, "User", id)
+		return
+	}
+	user := s.users[i]
+	s.users = append(s.users[:i], s.users[i+1:]...)
+
+	// Deleting a User also deletes their Organization Memberships.
+	memberships := s.memberships[:0]
+	for _, membership := range s.memberships {
+		if membership.UserID == id {
+			s.emit(models.EventOrganizationMembershipDeleted, membership)
+			continue
+		}
+		memberships = append(memberships, membership)
+	}
+	s.memberships = memberships
+
+	s.emit(models.EventUserDeleted, user)
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (s *Server) serveOrganizationMemberships(w http.ResponseWriter, r *http.Request, path []string) {
+	switch {
+	case len(path) == 0 && r.Method == http.MethodGet:
+		s.listOrganizationMemberships(w, r)
+	case len(path) == 0 && r.Method == http.MethodPost:
+		s.createOrganizationMembership(w, r)
+	case len(path) == 1 && r.Method == http.MethodGet:
+		s.getOrganizationMembership(w, path[0])
+	case len(path) == 1 && r.Method == http.MethodPut:
+		s.updateOrganizationMembership(w, r, path[0])
+	case len(path) == 1 && r.Method == http.MethodDelete:
+		s.deleteOrganizationMembership(w, path[0])
+	case len(path) == 2 && r.Method == http.MethodPut && path[1] == "deactivate":
+		s.setOrganizationMembershipStatus(w, path[0], models.OrganizationMembershipStatusInactive)
+	case len(path) == 2 && r.Method == http.MethodPut && path[1] == "reactivate":
+		s.setOrganizationMembershipStatus(w, path[0], models.OrganizationMembershipStatusActive)
+	default:
+		writeRouteNotFound(w, r)
+	}
+}
+
+func (s *Server) listOrganizationMemberships(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	organizationID := q.Get("organization_id")
+	userID := q.Get("user_id")
+	statuses := q["statuses"]
+
+	if organizationID == "" && userID == "" {
+		writeInvalid(w, "Either organization_id or user_id is required.")
+		return
+	}
+
+	var memberships []models.OrganizationMembership
+	var ids []string
+	for _, membership := range s.memberships {
+		if organizationID != "" && membership.OrganizationID != organizationID {
+			continue
+		}
+		if userID != "" && membership.UserID != userID {
+			continue
+		}
+		if len(statuses) != 0 && !contains(statuses, string(membership.Status)) {
+			continue
+		}
+		memberships = append(memberships, membership)
+		ids = append(ids, membership.ID)
+	}
+
+	page, metadata, err := paginate(ids, q)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request", err.Error())
+		return
+	}
+
+	data := make([]models.OrganizationMembership, 0, len(page))
+	for _, i := range page {
+		data = append(data, memberships[i])
+	}
+	writeList(w, data, metadata, "list_metadata")
+}
+
+func (s *Server) createOrganizationMembership(w http.ResponseWriter, r *http.Request) {
+	var opts struct {
+		UserID         string `json:"user_id"`
+		OrganizationID string `json:"organization_id"`
+		RoleSlug       string `json:"role_slug"`
+	}
+	if !decodeBody(w, r, &opts) {
+		return
+	}
+
+	if s.userIndex(opts.UserID) < 0 {
+		writeNotFound(w, "User", opts.UserID)
+		return
+	}
+	if s.organizationIndex(opts.OrganizationID) < 0 {
+		writeNotFound(w, "Organization", opts.OrganizationID)
+		return
+	}
+	if s.isMember(opts.UserID, opts.OrganizationID) {
+		writeError(w, http.StatusConflict, "organization_membership_already_exists", "The user is already a member of the organization.")
+		return
+	}
+
+	membership := s.addOrganizationMembership(models.OrganizationMembership{
+		UserID:         opts.UserID,
+		OrganizationID: opts.OrganizationID,
+		Role:           common.RoleResponse{Slug: opts.RoleSlug},
+	})
+	s.emit(models.EventOrganizationMembershipCreated, membership)
+	writeJSON(w, http.StatusCreated, membership)
+}
+
+func (s *Server) getOrganizationMembership(w http.ResponseWriter, id string) {
+	i := s.organizationMembershipIndex(id)
+	if i < 0 {
+		writeNotFound(w, "Organization Membership", id)
+		return
+	}
+	writeJSON(w, http.StatusOK, s.memberships[i])
+}
+
+func (s *Server) updateOrganizationMembership(w http.ResponseWriter, r *http.Request, id string) {
+	i := s.organizationMembershipIndex(id)
+	if i < 0 {
+		writeNotFound(w, "Organization Membership", id)
+		return
+	}
+
+	var opts struct {
+		RoleSlug string `json:"role_slug"`
+	}
+	if !decodeBody(w, r, &opts) {
+		return
+	}
+
+	membership := s.memberships[i]
+	if opts.RoleSlug != "" {
+		membership.Role.Slug = opts.RoleSlug
+	}
+	membership.UpdatedAt = now()
+
+	s.memberships[i] = membership
+	s.emit(models.EventOrganizationMembershipUpdated, membership)
+	writeJSON(w, http.StatusOK, membership)
+}
+
+func (s *Server) setOrganizationMembershipStatus(w http.ResponseWriter, id string, status models.OrganizationMembershipStatus) {
+	i := s.organizationMembershipIndex(id)
+	if i < 0 {
+		writeNotFound(w, "Organization Membership", id)
+		return
+	}
+
+	membership := s.memberships[i]
+	if membership.Status != status {
+		membership.Status = status
+		membership.UpdatedAt = now()
+
+		s.memberships[i] = membership
+		s.emit(models.EventOrganizationMembershipUpdated, membership)
+	}
+	writeJSON(w, http.StatusOK, membership)
+}
+
+func (s *Server) deleteOrganizationMembership(w http.ResponseWriter, id string) {
+	i := s.organizationMembershipIndex(id)
+	if i < 0 {
+		writeNotFound(w, "Organization Membership", id)
+		return
+	}
+	membership := s.memberships[i]
+
+	s.memberships = append(s.memberships[:i], s.memberships[i+1:]...)
+	s.emit(models.EventOrganizationMembershipDeleted, membership)
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (s *Server) serveInvitations(w http.ResponseWriter, r *http.Request, path []string) {
+	switch {
+	case len(path) == 0 && r.Method == http.MethodGet:
+		s.listInvitations(w, r)
+	case len(path) == 0 && r.Method == http.MethodPost:
+		s.sendInvitation(w, r)
+	case len(path) == 1 && r.Method == http.MethodGet:
+		s.getInvitation(w, path[0])
+	case len(path) == 2 && r.Method == http.MethodGet && path[0] == "by_token":
+		s.findInvitationByToken(w, path[1])
+	case len(path) == 2 && r.Method == http.MethodPost && path[1] == "revoke":
+		s.revokeInvitation(w, path[0])
+	default:
+		writeRouteNotFound(w, r)
+	}
+}
+
+func (s *Server) listInvitations(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	organizationID := q.Get("organization_id")
+	email := q.Get("email")
+
+	var invitations []models.Invitation
+	var ids []string
+	for _, invitation := range s.invitations {
+		if organizationID != "" && invitation.OrganizationID != organizationID {
+			continue
+		}
+		if email != "" && !strings.EqualFold(invitation.Email, email) {
+			continue
+		}
+		invitations = append(invitations, invitation)
+		ids = append(ids, invitation.ID)
+	}
+
+	page, metadata, err := paginate(ids, q)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request", err.Error())
+		return
+	}
+
+	data := make([]models.Invitation, 0, len(page))
+	for _, i := range page {
+		data = append(data, invitations[i])
+	}
+	writeList(w, data, metadata, "listMetadata")
+}
+
+func (s *Server) sendInvitation(w http.ResponseWriter, r *http.Request) {
+	var opts struct {
+		Email          string `json:"email"`
+		OrganizationID string `json:"organization_id"`
+		ExpiresInDays  int    `json:"expires_in_days"`
+		InviterUserID  string `json:"inviter_user_id"`
+	}
+	if !decodeBody(w, r, &opts) {
+		return
+	}
+
+	if opts.Email == "" {
+		writeInvalid(w, "email is required")
+		return
+	}
+	if opts.ExpiresInDays < 0 || opts.ExpiresInDays > 30 {
+		writeInvalid(w, "expires_in_days must be between 1 and 30")
+		return
+	}
+	if opts.OrganizationID != "" && s.organizationIndex(opts.OrganizationID) < 0 {
+		writeNotFound(w, "Organization", opts.OrganizationID)
+		return
+	}
+	if opts.InviterUserID != "" && s.userIndex(opts.InviterUserID) < 0 {
+		writeNotFound(w, "User", opts.InviterUserID)
+		return
+	}
+
+	invitation := models.Invitation{
+		Email:          opts.Email,
+		OrganizationID: opts.OrganizationID,
+		InviterUserID:  opts.InviterUserID,
+	}
+	if opts.ExpiresInDays != 0 {
+		invitation.CreatedAt = now()
+		invitation.ExpiresAt = invitation.CreatedAt.AddDate(0, 0, opts.ExpiresInDays)
+	}
+
+	invitation = s.addInvitation(invitation)
+	s.emit(models.EventInvitationCreated, invitation)
+	writeJSON(w, http.StatusCreated, invitation)
+}
+
+func (s *Server) getInvitation(w http.ResponseWriter, id string) {
+	i := s.invitationIndex(id)
+	if i < 0 {
+		writeNotFound(w, "Invitation", id)
+		return
+	}
+	writeJSON(w, http.StatusOK, s.invitations[i])
+}
+
+func (s *Server) findInvitationByToken(w http.ResponseWriter, token string) {
+	for _, invitation := range s.invitations {
+		if invitation.Token == token {
+			writeJSON(w, http.StatusOK, invitation)
+			return
+		}
+	}
+	writeNotFound(w, "Invitation", token)
+}
+
+func (s *Server) revokeInvitation(w http.ResponseWriter, id string) {
+	i := s.invitationIndex(id)
+	if i < 0 {
+		writeNotFound(w, "Invitation", id)
+		return
+	}
+
+	invitation := s.invitations[i]
+	if invitation.State != models.InvitationStatePending {
+		writeError(w, http.StatusBadRequest, "invite_not_pending", fmt.Sprintf("Invite has status %s.", invitation.State))
+		return
+	}
+
+	revokedAt := now()
+	invitation.State = models.InvitationStateRevoked
+	invitation.RevokedAt = &revokedAt
+	invitation.UpdatedAt = revokedAt
+
+	s.invitations[i] = invitation
+	writeJSON(w, http.StatusOK, invitation)
+}
+
+// newToken returns a random token, like the ones of Invitations.
+func newToken() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,151 @@
+package workostest_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/omi-lab/workos-go/v4/pkg/models"
+	"github.com/omi-lab/workos-go/v4/pkg/usermanagement"
+	"github.com/omi-lab/workos-go/v4/pkg/workos_errors"
+	"github.com/omi-lab/workos-go/v4/pkg/workostest"
+)
+
+func TestUsers(t *testing.T) {
+	server := workostest.NewServer(apiKey)
+	defer server.Close()
+
+	client := newClient(server).UserManagement()
+	ctx := context.Background()
+
+	user, err := client.CreateUser(ctx, usermanagement.CreateUserOpts{
+		Email:     "marcelina@foo-corp.com",
+		FirstName: "Marcelina",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "marcelina@foo-corp.com", user.Email)
+
+	_, err = client.CreateUser(ctx, usermanagement.CreateUserOpts{Email: "marcelina@foo-corp.com"})
+	require.True(t, workos_errors.IsConflict(err))
+
+	user, err = client.UpdateUser(ctx, usermanagement.UpdateUserOpts{
+		User:     user.ID,
+		LastName: "Davis",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "Marcelina", user.FirstName)
+	require.Equal(t, "Davis", user.LastName)
+
+	server.AddUser(models.User{Email: "other@foo-corp.com"})
+
+	list, err := client.ListUsers(ctx, usermanagement.ListUsersOpts{Email: "marcelina@foo-corp.com"})
+	require.NoError(t, err)
+	require.Equal(t, []models.User{user}, list.Data)
+
+	err = client.DeleteUser(ctx, usermanagement.DeleteUserOpts{User: user.ID})
+	require.NoError(t, err)
+	require.Len(t, server.Users(), 1)
+
+	var types []string
+	for _, e := range server.Events() {
+		types = append(types, e.Event)
+	}
+	require.Equal(t, []string{
+		models.EventUserCreated,
+		models.EventUserUpdated,
+		models.EventUserDeleted,
+	}, types)
+}
+
+func TestOrganizationMemberships(t *testing.T) {
+	server := workostest.NewServer(apiKey)
+	defer server.Close()
+
+	client := newClient(server).UserManagement()
+	ctx := context.Background()
+
+	org := server.AddOrganization(models.Organization{Name: "Foo Corp"})
+	user := server.AddUser(models.User{Email: "marcelina@foo-corp.com"})
+
+	membership, err := client.CreateOrganizationMembership(ctx, usermanagement.CreateOrganizationMembershipOpts{
+		UserID:         user.ID,
+		OrganizationID: org.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "member", membership.Role.Slug)
+	require.Equal(t, models.OrganizationMembershipStatusActive, membership.Status)
+
+	_, err = client.CreateOrganizationMembership(ctx, usermanagement.CreateOrganizationMembershipOpts{
+		UserID:         "user_unknown",
+		OrganizationID: org.ID,
+	})
+	require.True(t, workos_errors.IsNotFound(err))
+
+	membership, err = client.UpdateOrganizationMembership(ctx, membership.ID, usermanagement.UpdateOrganizationMembershipOpts{
+		RoleSlug: "admin",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "admin", membership.Role.Slug)
+
+	membership, err = client.DeactivateOrganizationMembership(ctx, usermanagement.DeactivateOrganizationMembershipOpts{
+		OrganizationMembership: membership.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, models.OrganizationMembershipStatusInactive, membership.Status)
+
+	list, err := client.ListOrganizationMemberships(ctx, usermanagement.ListOrganizationMembershipsOpts{
+		OrganizationID: org.ID,
+		Statuses:       []models.OrganizationMembershipStatus{models.OrganizationMembershipStatusActive},
+	})
+	require.NoError(t, err)
+	require.Empty(t, list.Data)
+
+	users, err := client.ListUsers(ctx, usermanagement.ListUsersOpts{OrganizationID: org.ID})
+	require.NoError(t, err)
+	require.Equal(t, []models.User{user}, users.Data)
+
+	err = client.DeleteOrganizationMembership(ctx, usermanagement.DeleteOrganizationMembershipOpts{
+		OrganizationMembership: membership.ID,
+	})
+	require.NoError(t, err)
+	require.Empty(t, server.OrganizationMemberships())
+}
+
+func TestInvitations(t *testing.T) {
+	server := workostest.NewServer(apiKey)
+	defer server.Close()
+
+	client := newClient(server).UserManagement()
+	ctx := context.Background()
+
+	org := server.AddOrganization(models.Organization{Name: "Foo Corp"})
+
+	invitation, err := client.SendInvitation(ctx, usermanagement.SendInvitationOpts{
+		Email:          "marcelina@foo-corp.com",
+		OrganizationID: org.ID,
+		ExpiresInDays:  3,
+	})
+	require.NoError(t, err)
+	require.Equal(t, models.InvitationStatePending, invitation.State)
+	require.NotEmpty(t, invitation.Token)
+	require.Equal(t, invitation.CreatedAt.AddDate(0, 0, 3), invitation.ExpiresAt)
+
+	found, err := client.FindInvitationByToken(ctx, usermanagement.FindInvitationByTokenOpts{
+		InvitationToken: invitation.Token,
+	})
+	require.NoError(t, err)
+	require.Equal(t, invitation, found)
+
+	list, err := client.ListInvitations(ctx, usermanagement.ListInvitationsOpts{OrganizationID: org.ID})
+	require.NoError(t, err)
+	require.Equal(t, []models.Invitation{invitation}, list.Data)
+
+	revoked, err := client.RevokeInvitation(ctx, usermanagement.RevokeInvitationOpts{Invitation: invitation.ID})
+	require.NoError(t, err)
+	require.Equal(t, models.InvitationStateRevoked, revoked.State)
+	require.NotNil(t, revoked.RevokedAt)
+
+	_, err = client.RevokeInvitation(ctx, usermanagement.RevokeInvitationOpts{Invitation: invitation.ID})
+	require.True(t, workos_errors.IsBadRequest(err))
+}